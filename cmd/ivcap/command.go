@@ -0,0 +1,181 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ivcap-works/ivcap-core-api/ivcap"
+)
+
+// group holds the commands calling the methods of a service.
+type group struct {
+	name        string
+	description string
+	commands    []*command
+}
+
+// command calls a service method.
+type command struct {
+	name        string
+	description string
+	flags       []flagSpec
+	// project names the flag taking the default project of the context when
+	// left empty. Only commands reading or listing set it, so that a
+	// forgotten flag never changes the default project.
+	project string
+	run     func(ctx context.Context, c *ivcap.Client, f *flags) (any, error)
+}
+
+// flagSpec describes a command flag. All flags are strings, as taken by the
+// Build*Payload functions.
+type flagSpec struct {
+	name  string
+	def   string
+	usage string
+}
+
+// flags holds the values of the flags of a command.
+type flags struct {
+	fs     *flag.FlagSet
+	values map[string]*string
+}
+
+// findGroup returns the group named name, or nil.
+func findGroup(name string) *group {
+	for _, grp := range groups {
+		if grp.name == name {
+			return grp
+		}
+	}
+	return nil
+}
+
+// find returns the command of grp named name, or nil.
+func (grp *group) find(name string) *command {
+	for _, cmd := range grp.commands {
+		if cmd.name == name {
+			return cmd
+		}
+	}
+	return nil
+}
+
+// usage writes the usage of grp to w.
+func (grp *group) usage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: ivcap [global flags] %s COMMAND [flags]\n\n", grp.name)
+	fmt.Fprintf(w, "%s\n\nCommands:\n", grp.description)
+	for _, cmd := range grp.commands {
+		fmt.Fprintf(w, "  %-22s %s\n", cmd.name, cmd.description)
+	}
+}
+
+// newFlags returns the flag set of cmd of group grp.
+func newFlags(grp *group, cmd *command, stderr io.Writer) *flags {
+	f := &flags{
+		fs:     flag.NewFlagSet(grp.name+" "+cmd.name, flag.ContinueOnError),
+		values: make(map[string]*string, len(cmd.flags)),
+	}
+	f.fs.SetOutput(stderr)
+	for _, spec := range cmd.flags {
+		f.values[spec.name] = f.fs.String(spec.name, spec.def, spec.usage)
+	}
+	f.fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: ivcap [global flags] %s %s [flags]\n\n%s\n\nFlags:\n", grp.name, cmd.name, cmd.description)
+		f.fs.PrintDefaults()
+	}
+	return f
+}
+
+// get returns the value of the flag name.
+func (f *flags) get(name string) string {
+	if v, ok := f.values[name]; ok {
+		return *v
+	}
+	panic("ivcap: undefined flag " + name)
+}
+
+// setDefault sets the flag name to value if the command has such a flag and
+// it was left empty.
+func (f *flags) setDefault(name, value string) {
+	if v, ok := f.values[name]; ok && *v == "" {
+		*v = value
+	}
+}
+
+// body returns the value of the -body flag. A value starting with "@" names
+// a file holding the body, "-" reads it from standard input.
+func (f *flags) body() (string, error) {
+	v := f.get("body")
+	var b []byte
+	var err error
+	switch {
+	case v == "-":
+		b, err = io.ReadAll(os.Stdin)
+	case strings.HasPrefix(v, "@"):
+		b, err = os.ReadFile(v[1:])
+	default:
+		return v, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading body: %w", err)
+	}
+	return string(b), nil
+}
+
+// open returns the file named by the -file flag, or standard input if it is
+// "-" or empty. The size is -1 if unknown.
+func (f *flags) open() (io.ReadCloser, int64, error) {
+	path := f.get("file")
+	if path == "" || path == "-" {
+		return io.NopCloser(os.Stdin), -1, nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, 0, err
+	}
+	return file, info.Size(), nil
+}
+
+// bodyFlag returns the -body flag, documented with example.
+func bodyFlag(example string) flagSpec {
+	return flagSpec{"body", "", "JSON body, @FILE or - for standard input, e.g. " + example}
+}
+
+// idFlag returns the -id flag.
+func idFlag(usage string) flagSpec {
+	return flagSpec{"id", "", usage}
+}
+
+// listFlags are the flags of the list commands sharing the common paging and
+// filtering parameters.
+var listFlags = []flagSpec{
+	{"limit", "10", "maximum number of items to return (1-50)"},
+	{"page", "", "page token returned by a previous call"},
+	{"filter", "", "filter expression, e.g. \"status ~= 'active'\""},
+	{"order-by", "", "name of the property to order by"},
+	{"order-desc", "true", "order in descending order"},
+	{"at-time", "", "return the state at this RFC 3339 time"},
+}
@@ -0,0 +1,1365 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	artifactc "github.com/ivcap-works/ivcap-core-api/http/artifact"
+	aspectc "github.com/ivcap-works/ivcap-core-api/http/aspect"
+	dashboardc "github.com/ivcap-works/ivcap-core-api/http/dashboard"
+	metadatac "github.com/ivcap-works/ivcap-core-api/http/metadata"
+	orderc "github.com/ivcap-works/ivcap-core-api/http/order"
+	packagec "github.com/ivcap-works/ivcap-core-api/http/package_"
+	projectc "github.com/ivcap-works/ivcap-core-api/http/project"
+	queuec "github.com/ivcap-works/ivcap-core-api/http/queue"
+	searchc "github.com/ivcap-works/ivcap-core-api/http/search"
+	secretc "github.com/ivcap-works/ivcap-core-api/http/secret"
+	servicec "github.com/ivcap-works/ivcap-core-api/http/service"
+	"github.com/ivcap-works/ivcap-core-api/ivcap"
+)
+
+// groups lists the commands of all services. The JWT arguments of the
+// Build*Payload functions are left empty: the client authorizes the requests
+// with the token selected by the global flags.
+var groups = []*group{
+	artifactCommands,
+	aspectCommands,
+	dashboardCommands,
+	metadataCommands,
+	openapiCommands,
+	orderCommands,
+	packageCommands,
+	projectCommands,
+	queueCommands,
+	searchCommands,
+	secretCommands,
+	serviceCommands,
+}
+
+var artifactCommands = &group{
+	name:        "artifact",
+	description: "Manage and query artifacts.",
+	commands: []*command{
+		{
+			name:        "list",
+			description: "List artifacts.",
+			flags:       listFlags,
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				p, err := artifactc.BuildListPayload(f.get("limit"), f.get("page"), f.get("filter"), f.get("order-by"), f.get("order-desc"), f.get("at-time"), "")
+				if err != nil {
+					return nil, err
+				}
+				return c.Artifacts.List(ctx, p)
+			},
+		},
+		{
+			name:        "read",
+			description: "Show the status of an artifact.",
+			flags:       []flagSpec{idFlag("ID of the artifact")},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				p, err := artifactc.BuildReadPayload(f.get("id"), "")
+				if err != nil {
+					return nil, err
+				}
+				return c.Artifacts.Read(ctx, p.ID)
+			},
+		},
+		{
+			name:        "wait",
+			description: "Wait for artifacts to be ready.",
+			flags:       []flagSpec{idFlag("comma separated IDs of the artifacts")},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				ids := strings.Split(f.get("id"), ",")
+				if len(ids) == 1 {
+					return c.Artifacts.WaitReady(ctx, ids[0], nil)
+				}
+				return c.Artifacts.WaitAllReady(ctx, ids, nil)
+			},
+		},
+		{
+			name:        "upload",
+			description: "Upload a file as a new artifact.",
+			flags: []flagSpec{
+				{"file", "-", "file to upload, - for standard input"},
+				{"name", "", "name of the artifact, defaults to the file name"},
+				{"collection", "", "URN of the collection to add the artifact to"},
+				{"policy", "", "URN of the policy controlling access"},
+				{"content-type", "", "content type, guessed from the file name if not set"},
+				{"content-encoding", "", "content encoding"},
+				{"resumable", "", "upload in chunks which are resent after failures, requires -file"},
+				{"chunk-size", "16777216", "size of the chunks of a resumable upload in bytes"},
+				{"state-file", "", "file recording the progress of a resumable upload, defaults to FILE.ivcap-upload"},
+				{"dedup", "", "return the existing artifact with the same content instead of uploading it again, requires -file"},
+				{"compress", "", "content encoding to compress the content with on the fly, such as gzip or zstd, unless already compressed"},
+				{"encrypt-with", "", "name of the secret holding the key to encrypt the content with, see secret create-key"},
+			},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				body, size, err := f.open()
+				if err != nil {
+					return nil, err
+				}
+				defer body.Close()
+				name, ct, length := f.get("name"), f.get("content-type"), ""
+				if path := f.get("file"); path != "-" && path != "" {
+					if name == "" {
+						name = filepath.Base(path)
+					}
+					if ct == "" {
+						ct = mime.TypeByExtension(filepath.Ext(path))
+					}
+				}
+				if ct == "" {
+					ct = "application/octet-stream"
+				}
+				if size >= 0 {
+					length = strconv.FormatInt(size, 10)
+				}
+				p, err := artifactc.BuildUploadPayload("", ct, f.get("content-encoding"), length, name, f.get("collection"), f.get("policy"), "", "", "", "")
+				if err != nil {
+					return nil, err
+				}
+				resumable, dedup, compress := f.get("resumable") == "true", f.get("dedup") == "true", f.get("compress")
+				if key := f.get("encrypt-with"); key != "" {
+					if resumable || dedup || compress != "" {
+						return nil, errors.New("-encrypt-with cannot be combined with -resumable, -dedup or -compress")
+					}
+					return c.Artifacts.UploadEncrypted(ctx, p, body, key)
+				}
+				if compress != "" && (resumable || dedup) {
+					return nil, errors.New("-compress cannot be combined with -resumable or -dedup")
+				}
+				if !resumable && !dedup {
+					return c.Artifacts.UploadCompressed(ctx, p, body, compress)
+				}
+				content, ok := body.(io.ReadSeeker)
+				if !ok || size < 0 {
+					return nil, errors.New("resumable and deduplicated uploads require -file")
+				}
+				if dedup {
+					if resumable {
+						return nil, errors.New("-resumable and -dedup cannot be combined")
+					}
+					st, _, err := c.Artifacts.UploadDeduplicated(ctx, p, content)
+					return st, err
+				}
+				chunk, err := strconv.ParseInt(f.get("chunk-size"), 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid -chunk-size: %w", err)
+				}
+				state := f.get("state-file")
+				if state == "" {
+					state = f.get("file") + ".ivcap-upload"
+				}
+				p.ContentLength = nil
+				return c.Artifacts.UploadResumable(ctx, p, content, size, &ivcap.ResumableUpload{ChunkSize: chunk, StatePath: state})
+			},
+		},
+		{
+			name:        "upload-tree",
+			description: "Upload the files below a directory as artifacts named by their relative path.",
+			flags: []flagSpec{
+				{"dir", "", "directory to upload"},
+				{"collection", "", "URN of the collection to add the artifacts to"},
+				{"policy", "", "URN of the policy controlling access"},
+				{"concurrency", "4", "number of files uploaded at the same time"},
+				{"dedup", "", "reuse the existing artifacts with the same content as a file"},
+				{"compress", "", "content encoding to compress the files with, such as gzip or zstd, unless already compressed"},
+				{"manifest", "", "file recording the uploaded files, which are skipped when run again, defaults to DIR.ivcap-manifest.json"},
+			},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				dir := f.get("dir")
+				if dir == "" {
+					return nil, errors.New("missing -dir")
+				}
+				n, err := strconv.Atoi(f.get("concurrency"))
+				if err != nil {
+					return nil, fmt.Errorf("invalid -concurrency: %w", err)
+				}
+				manifest := f.get("manifest")
+				if manifest == "" {
+					manifest = filepath.Clean(dir) + ".ivcap-manifest.json"
+				}
+				return c.Artifacts.UploadTree(ctx, dir, &ivcap.TreeUpload{
+					Collection:   f.get("collection"),
+					Policy:       f.get("policy"),
+					Dedup:        f.get("dedup") == "true",
+					Compression:  f.get("compress"),
+					Concurrency:  n,
+					ManifestPath: manifest,
+				})
+			},
+		},
+		{
+			name:        "download",
+			description: "Download the content of an artifact.",
+			flags: []flagSpec{
+				idFlag("ID of the artifact"),
+				{"file", "-", "file to write the content to, resuming a FILE.part left by an interrupted download of the same artifact, - for standard output"},
+			},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				if path := f.get("file"); path != "-" && path != "" {
+					return c.Artifacts.DownloadFile(ctx, f.get("id"), path, nil)
+				}
+				r, w := io.Pipe()
+				go func() {
+					_, err := c.Artifacts.Download(ctx, f.get("id"), w, nil)
+					w.CloseWithError(err)
+				}()
+				return r, nil
+			},
+		},
+	},
+}
+
+var aspectCommands = &group{
+	name:        "aspect",
+	description: "Manage and query aspects attached to entities.",
+	commands: []*command{
+		{
+			name:        "read",
+			description: "Show an aspect.",
+			flags:       []flagSpec{idFlag("ID of the aspect")},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				p, err := aspectc.BuildReadPayload(f.get("id"), "")
+				if err != nil {
+					return nil, err
+				}
+				return c.Aspects.Read(ctx, p.ID)
+			},
+		},
+		{
+			name:        "list",
+			description: "List aspects.",
+			flags: []flagSpec{
+				{"entity", "", "URN of the entity the aspects are attached to"},
+				{"schema", "", "schema of the aspects, may end with '%' as wildcard"},
+				{"content-path", "", "JSONPath filter on the aspect content"},
+				{"at-time", "", "return the state at this RFC 3339 time"},
+				{"limit", "10", "maximum number of items to return (1-50)"},
+				{"filter", "", "filter expression"},
+				{"order-by", "valid_from", "name of the property to order by"},
+				{"order-direction", "DESC", "ASC or DESC"},
+				{"include-content", "", "include the content of the aspects"},
+				{"page", "", "page token returned by a previous call"},
+			},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				p, err := aspectc.BuildListPayload(f.get("entity"), f.get("schema"), f.get("content-path"), f.get("at-time"), f.get("limit"), f.get("filter"), f.get("order-by"), f.get("order-direction"), f.get("include-content"), f.get("page"), "")
+				if err != nil {
+					return nil, err
+				}
+				return c.Aspects.List(ctx, p)
+			},
+		},
+		{
+			name:        "create",
+			description: "Attach a new aspect to an entity.",
+			flags:       aspectWriteFlags,
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				body, err := f.body()
+				if err != nil {
+					return nil, err
+				}
+				p, err := aspectc.BuildCreatePayload(body, f.get("entity"), f.get("schema"), f.get("policy"), "", f.get("content-type"))
+				if err != nil {
+					return nil, err
+				}
+				return c.Aspects.Create(ctx, p)
+			},
+		},
+		{
+			name:        "update",
+			description: "Replace the aspect of an entity with the given schema.",
+			flags:       aspectWriteFlags,
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				body, err := f.body()
+				if err != nil {
+					return nil, err
+				}
+				p, err := aspectc.BuildUpdatePayload(body, f.get("entity"), f.get("schema"), f.get("policy"), "", f.get("content-type"))
+				if err != nil {
+					return nil, err
+				}
+				return c.Aspects.Update(ctx, p)
+			},
+		},
+		{
+			name:        "upsert",
+			description: "Replace the aspect of an entity unless it changed since it was read.",
+			flags: []flagSpec{
+				bodyFlag(`'{"$schema": "urn:example:schema", ...}'`),
+				{"entity", "", "URN of the entity"},
+				{"schema", "", "schema of the aspect"},
+				{"expected", "", "ID of the aspect to replace, empty if there is none"},
+			},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				body, err := f.body()
+				if err != nil {
+					return nil, err
+				}
+				var content any
+				if err := json.Unmarshal([]byte(body), &content); err != nil {
+					return nil, fmt.Errorf("invalid -body: %w", err)
+				}
+				return c.Aspects.Upsert(ctx, f.get("entity"), f.get("schema"), content, f.get("expected"))
+			},
+		},
+		{
+			name:        "retract",
+			description: "Retract an aspect.",
+			flags:       []flagSpec{idFlag("ID of the aspect")},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				p, err := aspectc.BuildRetractPayload(f.get("id"), "")
+				if err != nil {
+					return nil, err
+				}
+				return nil, c.Aspects.Retract(ctx, p.ID)
+			},
+		},
+		{
+			name:        "export",
+			description: "Write the aspects matching a query as JSON lines.",
+			flags: []flagSpec{
+				{"entity", "", "URN of the entity the aspects are attached to"},
+				{"schema", "", "schema of the aspects, may end with '%' as wildcard"},
+				{"content-path", "", "JSONPath filter on the aspect content"},
+				{"at-time", "", "export the state at this RFC 3339 time"},
+				{"file", "-", "file to write the aspects to, - for standard output"},
+			},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				p, err := aspectc.BuildListPayload(f.get("entity"), f.get("schema"), f.get("content-path"), f.get("at-time"), "", "", "", "", "", "", "")
+				if err != nil {
+					return nil, err
+				}
+				path := f.get("file")
+				if path == "-" || path == "" {
+					r, w := io.Pipe()
+					go func() {
+						_, err := c.Aspects.Export(ctx, w, p)
+						w.CloseWithError(err)
+					}()
+					return r, nil
+				}
+				file, err := os.Create(path)
+				if err != nil {
+					return nil, err
+				}
+				n, err := c.Aspects.Export(ctx, file, p)
+				if cerr := file.Close(); err == nil {
+					err = cerr
+				}
+				return map[string]int{"exported": n}, err
+			},
+		},
+		{
+			name:        "import",
+			description: "Assert the aspects read as JSON lines, as written by export.",
+			flags: []flagSpec{
+				{"file", "-", "file to read the aspects from, - for standard input"},
+				{"concurrency", "4", "number of aspects imported at the same time"},
+				{"rate", "0", "maximum number of aspects imported per second, 0 for no limit"},
+				{"checkpoint", "", "file recording the records imported, which are skipped when run again"},
+				{"replace", "", "replace the active aspect of the same entity and schema if its content differs"},
+				{"policy", "", "URN of the policy controlling access"},
+				{"dry-run", "", "only list the action each aspect would take"},
+			},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				n, err := strconv.Atoi(f.get("concurrency"))
+				if err != nil {
+					return nil, fmt.Errorf("invalid -concurrency: %w", err)
+				}
+				rate, err := strconv.ParseFloat(f.get("rate"), 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid -rate: %w", err)
+				}
+				r, _, err := f.open()
+				if err != nil {
+					return nil, err
+				}
+				defer r.Close()
+				opts := &ivcap.AspectImport{
+					Concurrency:    n,
+					RateLimit:      rate,
+					CheckpointPath: f.get("checkpoint"),
+					Replace:        f.get("replace") == "true",
+					DryRun:         f.get("dry-run") == "true",
+					Policy:         f.get("policy"),
+				}
+				var results []*ivcap.AspectImportResult
+				if opts.DryRun {
+					var mu sync.Mutex
+					opts.Progress = func(r *ivcap.AspectImportResult, err error) {
+						if err == nil {
+							mu.Lock()
+							results = append(results, r)
+							mu.Unlock()
+						}
+					}
+				}
+				report, err := c.Aspects.Import(ctx, r, opts)
+				if err != nil || !opts.DryRun {
+					return report, err
+				}
+				sort.Slice(results, func(i, j int) bool { return results[i].Line < results[j].Line })
+				return results, nil
+			},
+		},
+		{
+			name:        "watch",
+			description: "Print the aspects asserted and retracted as JSON lines, until interrupted.",
+			flags: []flagSpec{
+				{"entity", "", "URN of the entity the aspects are attached to"},
+				{"schema", "", "schema of the aspects, may end with '%' as wildcard"},
+				{"content-path", "", "JSONPath filter on the aspect content"},
+				{"include-content", "", "include the content of the aspects"},
+				{"interval", "10s", "delay between two polls"},
+				{"since", "", "RFC 3339 time to report the changes from, defaults to now"},
+				{"cursor", "", "file recording the last change printed, to continue from when run again"},
+			},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				interval, err := time.ParseDuration(f.get("interval"))
+				if err != nil {
+					return nil, fmt.Errorf("invalid -interval: %w", err)
+				}
+				opts := &ivcap.AspectWatch{
+					Entity:         f.get("entity"),
+					Schema:         f.get("schema"),
+					ContentPath:    f.get("content-path"),
+					IncludeContent: f.get("include-content") == "true",
+					Interval:       interval,
+					CursorPath:     f.get("cursor"),
+				}
+				if v := f.get("since"); v != "" {
+					if opts.Since, err = time.Parse(time.RFC3339, v); err != nil {
+						return nil, fmt.Errorf("invalid -since: %w", err)
+					}
+				}
+				w, err := c.Aspects.Watch(ctx, opts)
+				if err != nil {
+					return nil, err
+				}
+				r, pw := io.Pipe()
+				go func() {
+					enc := json.NewEncoder(pw)
+					for ev := range w.C {
+						if err := enc.Encode(ev); err != nil {
+							break
+						}
+					}
+					if err := w.Err(); !errors.Is(err, context.Canceled) {
+						pw.CloseWithError(err)
+					}
+					pw.Close()
+				}()
+				return r, nil
+			},
+		},
+		{
+			name:        "graph",
+			description: "Show the entities reachable from an entity through the references in their aspects.",
+			flags: []flagSpec{
+				{"entity", "", "URN of the entity to start from"},
+				{"depth", "1", "number of hops to follow"},
+				{"format", "", "dot, mermaid or json-ld, instead of the output format"},
+			},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				entity := f.get("entity")
+				if entity == "" {
+					return nil, errors.New("missing -entity")
+				}
+				depth, err := strconv.Atoi(f.get("depth"))
+				if err != nil {
+					return nil, fmt.Errorf("invalid -depth: %w", err)
+				}
+				g, err := c.Aspects.Graph(ctx, entity, &ivcap.GraphTraversal{Depth: depth})
+				if err != nil {
+					return nil, err
+				}
+				var buf bytes.Buffer
+				switch f.get("format") {
+				case "":
+					return g, nil
+				case "dot":
+					err = g.DOT(&buf)
+				case "mermaid":
+					err = g.Mermaid(&buf)
+				case "json-ld":
+					err = g.JSONLD(&buf)
+				default:
+					return nil, fmt.Errorf("invalid -format %q", f.get("format"))
+				}
+				return io.NopCloser(&buf), err
+			},
+		},
+		{
+			name:        "history",
+			description: "List the changes to the aspects of an entity.",
+			flags:       aspectHistoryFlags,
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				h, err := aspectHistory(ctx, c, f)
+				if err != nil {
+					return nil, err
+				}
+				return h.Changes, nil
+			},
+		},
+		{
+			name:        "diff",
+			description: "Compare the aspects of an entity at two points in time.",
+			flags: append(aspectHistoryFlags,
+				flagSpec{"from", "", "RFC3339 time of the first point"},
+				flagSpec{"to", "", "RFC3339 time of the second point, defaults to now"},
+			),
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				from, err := time.Parse(time.RFC3339, f.get("from"))
+				if err != nil {
+					return nil, fmt.Errorf("invalid -from: %w", err)
+				}
+				to := time.Now()
+				if v := f.get("to"); v != "" {
+					if to, err = time.Parse(time.RFC3339, v); err != nil {
+						return nil, fmt.Errorf("invalid -to: %w", err)
+					}
+				}
+				h, err := aspectHistory(ctx, c, f)
+				if err != nil {
+					return nil, err
+				}
+				return h.Diff(from, to), nil
+			},
+		},
+	},
+}
+
+var aspectHistoryFlags = []flagSpec{
+	{"entity", "", "URN of the entity"},
+	{"schema", "", "schema of the aspects, % as wildcard, defaults to all"},
+}
+
+// aspectHistory returns the history of the aspects selected by the
+// aspectHistoryFlags.
+func aspectHistory(ctx context.Context, c *ivcap.Client, f *flags) (*ivcap.AspectHistory, error) {
+	if f.get("entity") == "" {
+		return nil, errors.New("missing -entity")
+	}
+	return c.Aspects.History(ctx, f.get("entity"), f.get("schema"))
+}
+
+var aspectWriteFlags = []flagSpec{
+	bodyFlag(`'{"$schema": "urn:example:schema", ...}'`),
+	{"entity", "", "URN of the entity"},
+	{"schema", "", "schema of the aspect"},
+	{"policy", "", "URN of the policy controlling access"},
+	{"content-type", "application/json", "content type of the body"},
+}
+
+var dashboardCommands = &group{
+	name:        "dashboard",
+	description: "List dashboards.",
+	commands: []*command{
+		{
+			name:        "list",
+			description: "List dashboards.",
+			flags:       listFlags,
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				p, err := dashboardc.BuildListPayload(f.get("limit"), f.get("page"), f.get("filter"), f.get("order-by"), f.get("order-desc"), f.get("at-time"), "")
+				if err != nil {
+					return nil, err
+				}
+				return c.Dashboards.List(ctx, p)
+			},
+		},
+	},
+}
+
+var metadataCommands = &group{
+	name:        "metadata",
+	description: "Manage and query metadata records.",
+	commands: []*command{
+		{
+			name:        "read",
+			description: "Show a metadata record.",
+			flags:       []flagSpec{idFlag("ID of the metadata record")},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				p, err := metadatac.BuildReadPayload(f.get("id"), "")
+				if err != nil {
+					return nil, err
+				}
+				return c.Metadata.Read(ctx, p.ID)
+			},
+		},
+		{
+			name:        "list",
+			description: "List metadata records.",
+			flags: []flagSpec{
+				{"entity-id", "", "URN of the entity the records are attached to"},
+				{"schema", "", "schema of the records, may end with '%' as wildcard"},
+				{"aspect-path", "", "JSONPath filter on the record content"},
+				{"at-time", "", "return the state at this RFC 3339 time"},
+				{"limit", "10", "maximum number of items to return (1-50)"},
+				{"filter", "", "filter expression"},
+				{"order-by", "", "name of the property to order by"},
+				{"order-desc", "true", "order in descending order"},
+				{"include-content", "", "include the content of the records"},
+				{"page", "", "page token returned by a previous call"},
+			},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				p, err := metadatac.BuildListPayload(f.get("entity-id"), f.get("schema"), f.get("aspect-path"), f.get("at-time"), f.get("limit"), f.get("filter"), f.get("order-by"), f.get("order-desc"), f.get("include-content"), f.get("page"), "")
+				if err != nil {
+					return nil, err
+				}
+				return c.Metadata.List(ctx, p)
+			},
+		},
+		{
+			name:        "add",
+			description: "Attach a new metadata record to an entity.",
+			flags: []flagSpec{
+				bodyFlag(`'{"$schema": "urn:example:schema", ...}'`),
+				{"entity-id", "", "URN of the entity"},
+				{"schema", "", "schema of the record"},
+				{"policy-id", "", "URN of the policy controlling access"},
+				{"content-type", "application/json", "content type of the body"},
+			},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				body, err := f.body()
+				if err != nil {
+					return nil, err
+				}
+				p, err := metadatac.BuildAddPayload(body, f.get("entity-id"), f.get("schema"), f.get("policy-id"), "", f.get("content-type"))
+				if err != nil {
+					return nil, err
+				}
+				return c.Metadata.Add(ctx, p)
+			},
+		},
+		{
+			name:        "update-record",
+			description: "Replace a metadata record.",
+			flags: []flagSpec{
+				bodyFlag(`'{"$schema": "urn:example:schema", ...}'`),
+				idFlag("ID of the metadata record"),
+				{"entity-id", "", "URN of the entity"},
+				{"schema", "", "schema of the record"},
+				{"policy-id", "", "URN of the policy controlling access"},
+				{"content-type", "application/json", "content type of the body"},
+			},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				body, err := f.body()
+				if err != nil {
+					return nil, err
+				}
+				p, err := metadatac.BuildUpdateRecordPayload(body, f.get("id"), f.get("entity-id"), f.get("schema"), f.get("policy-id"), "", f.get("content-type"))
+				if err != nil {
+					return nil, err
+				}
+				return c.Metadata.UpdateRecord(ctx, p)
+			},
+		},
+		{
+			name:        "revoke",
+			description: "Revoke a metadata record.",
+			flags:       []flagSpec{idFlag("ID of the metadata record")},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				p, err := metadatac.BuildRevokePayload(f.get("id"), "")
+				if err != nil {
+					return nil, err
+				}
+				return nil, c.Metadata.Revoke(ctx, *p.ID)
+			},
+		},
+	},
+}
+
+var openapiCommands = &group{
+	name:        "openapi",
+	description: "Inspect the API served by the deployment.",
+	commands: []*command{
+		{
+			name:        "fetch-spec",
+			description: "Print the OpenAPI document of the deployment.",
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				spec, err := c.OpenAPI.FetchSpec(ctx)
+				if err != nil {
+					return nil, err
+				}
+				return io.NopCloser(bytes.NewReader(spec.Document)), nil
+			},
+		},
+		{
+			name:        "check",
+			description: "Check the deployment serves the API this tool was built for.",
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				return c.CheckCompatibility(ctx)
+			},
+		},
+	},
+}
+
+var orderCommands = &group{
+	name:        "order",
+	description: "Place and inspect orders.",
+	commands: []*command{
+		{
+			name:        "list",
+			description: "List orders.",
+			flags:       listFlags,
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				p, err := orderc.BuildListPayload(f.get("limit"), f.get("page"), f.get("filter"), f.get("order-by"), f.get("order-desc"), f.get("at-time"), "")
+				if err != nil {
+					return nil, err
+				}
+				return c.Orders.List(ctx, p)
+			},
+		},
+		{
+			name:        "read",
+			description: "Show the status of an order.",
+			flags:       []flagSpec{idFlag("ID of the order")},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				p, err := orderc.BuildReadPayload(f.get("id"), "")
+				if err != nil {
+					return nil, err
+				}
+				return c.Orders.Read(ctx, p.ID)
+			},
+		},
+		{
+			name:        "products",
+			description: "List the products created by an order.",
+			flags:       orderPageFlags,
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				p, err := orderc.BuildProductsPayload(f.get("order-id"), f.get("order-by"), f.get("order-desc"), f.get("limit"), f.get("page"), "")
+				if err != nil {
+					return nil, err
+				}
+				return c.Orders.Products(ctx, p)
+			},
+		},
+		{
+			name:        "metadata",
+			description: "List the metadata created by an order.",
+			flags:       orderPageFlags,
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				p, err := orderc.BuildMetadataPayload(f.get("order-id"), f.get("order-by"), f.get("order-desc"), f.get("limit"), f.get("page"), "")
+				if err != nil {
+					return nil, err
+				}
+				return c.Orders.Metadata(ctx, p)
+			},
+		},
+		{
+			name:        "create",
+			description: "Place a new order.",
+			flags:       []flagSpec{bodyFlag(`'{"service": "urn:ivcap:service:...", "parameters": [{"name": "msg", "value": "hi"}]}'`)},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				body, err := f.body()
+				if err != nil {
+					return nil, err
+				}
+				p, err := orderc.BuildCreatePayload(body, "")
+				if err != nil {
+					return nil, err
+				}
+				return c.Orders.Create(ctx, p.Orders)
+			},
+		},
+		{
+			name:        "logs",
+			description: "Stream the logs of an order.",
+			flags: []flagSpec{
+				{"order-id", "", "ID of the order"},
+				{"from", "", "start of the logs as Unix time in seconds"},
+				{"to", "", "end of the logs as Unix time in seconds"},
+			},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				p, err := orderc.BuildLogsPayload(f.get("order-id"), f.get("from"), f.get("to"), "")
+				if err != nil {
+					return nil, err
+				}
+				return c.Orders.Logs(ctx, p)
+			},
+		},
+		{
+			name:        "top",
+			description: "Show the resource usage of an order.",
+			flags:       []flagSpec{{"order-id", "", "ID of the order"}},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				p, err := orderc.BuildTopPayload(f.get("order-id"), "")
+				if err != nil {
+					return nil, err
+				}
+				return c.Orders.Top(ctx, p.OrderID)
+			},
+		},
+	},
+}
+
+var orderPageFlags = []flagSpec{
+	{"order-id", "", "ID of the order"},
+	{"order-by", "", "name of the property to order by"},
+	{"order-desc", "true", "order in descending order"},
+	{"limit", "10", "maximum number of items to return (1-50)"},
+	{"page", "", "page token returned by a previous call"},
+}
+
+var packageCommands = &group{
+	name:        "package",
+	description: "Manage the docker images of services.",
+	commands: []*command{
+		{
+			name:        "list",
+			description: "List docker image tags.",
+			flags: []flagSpec{
+				{"tag", "", "docker image tag"},
+				{"page", "", "page token returned by a previous call"},
+				{"limit", "10", "maximum number of items to return (1-50)"},
+			},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				p, err := packagec.BuildListPayload(f.get("tag"), f.get("page"), f.get("limit"), "")
+				if err != nil {
+					return nil, err
+				}
+				return c.Packages.List(ctx, p)
+			},
+		},
+		{
+			name:        "pull",
+			description: "Stream a manifest, config or layer of a docker image.",
+			flags: []flagSpec{
+				{"ref", "", "docker image tag or layer digest"},
+				{"type", "", "manifest, config or layer"},
+				{"offset", "", "offset in bytes to start from"},
+			},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				p, err := packagec.BuildPullPayload(f.get("ref"), f.get("type"), f.get("offset"), "")
+				if err != nil {
+					return nil, err
+				}
+				_, body, err := c.Packages.Pull(ctx, p)
+				return body, err
+			},
+		},
+		{
+			name:        "push",
+			description: "Upload a manifest, config or layer of a docker image.",
+			flags: []flagSpec{
+				{"file", "-", "file to upload, - for standard input"},
+				{"tag", "", "docker image tag"},
+				{"force", "", "overwrite an existing tag"},
+				{"type", "", "manifest, config or layer"},
+				{"digest", "", "digest of the content"},
+				{"total", "", "total size of the layer in bytes"},
+				{"start", "", "offset of the first byte uploaded"},
+				{"end", "", "offset of the last byte uploaded"},
+			},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				p, err := packagec.BuildPushPayload(f.get("tag"), f.get("force"), f.get("type"), f.get("digest"), f.get("total"), f.get("start"), f.get("end"), "")
+				if err != nil {
+					return nil, err
+				}
+				body, _, err := f.open()
+				if err != nil {
+					return nil, err
+				}
+				defer body.Close()
+				return c.Packages.Push(ctx, p, body)
+			},
+		},
+		{
+			name:        "status",
+			description: "Show the push status of an image layer.",
+			flags: []flagSpec{
+				{"tag", "", "docker image tag"},
+				{"digest", "", "digest of the layer"},
+			},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				p, err := packagec.BuildStatusPayload(f.get("tag"), f.get("digest"), "")
+				if err != nil {
+					return nil, err
+				}
+				return c.Packages.Status(ctx, p.Tag, p.Digest)
+			},
+		},
+		{
+			name:        "remove",
+			description: "Remove a docker image.",
+			flags:       []flagSpec{{"tag", "", "docker image tag"}},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				p, err := packagec.BuildRemovePayload(f.get("tag"), "")
+				if err != nil {
+					return nil, err
+				}
+				return nil, c.Packages.Remove(ctx, p.Tag)
+			},
+		},
+	},
+}
+
+var projectCommands = &group{
+	name:        "project",
+	description: "Manage projects and their members.",
+	commands: []*command{
+		{
+			name:        "list",
+			description: "List projects.",
+			flags:       listFlags,
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				p, err := projectc.BuildListPayload(f.get("limit"), f.get("page"), f.get("filter"), f.get("order-by"), f.get("order-desc"), f.get("at-time"), "")
+				if err != nil {
+					return nil, err
+				}
+				return c.Projects.List(ctx, p)
+			},
+		},
+		{
+			name:        "create-project",
+			description: "Create a project.",
+			flags:       []flagSpec{bodyFlag(`'{"name": "My project", "account_urn": "urn:ivcap:account:..."}'`)},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				body, err := f.body()
+				if err != nil {
+					return nil, err
+				}
+				p, err := projectc.BuildCreateProjectPayload(body, "")
+				if err != nil {
+					return nil, err
+				}
+				return c.Projects.CreateProject(ctx, p.Project)
+			},
+		},
+		{
+			name:        "delete",
+			description: "Delete a project.",
+			flags:       []flagSpec{idFlag("URN of the project")},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				p, err := projectc.BuildDeletePayload(f.get("id"), "")
+				if err != nil {
+					return nil, err
+				}
+				return nil, c.Projects.Delete(ctx, p.ID)
+			},
+		},
+		{
+			name:        "read",
+			description: "Show a project.",
+			flags:       []flagSpec{idFlag("URN of the project")},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				p, err := projectc.BuildReadPayload(f.get("id"), "")
+				if err != nil {
+					return nil, err
+				}
+				return c.Projects.Read(ctx, p.ID)
+			},
+		},
+		{
+			name:        "list-project-members",
+			description: "List the members of a project.",
+			flags: []flagSpec{
+				{"urn", "", "URN of the project"},
+				{"role", "", "only list members with this role"},
+				{"limit", "10", "maximum number of items to return (1-50)"},
+				{"page", "", "page token returned by a previous call"},
+			},
+			project: "urn",
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				p, err := projectc.BuildListProjectMembersPayload(f.get("urn"), f.get("role"), f.get("limit"), f.get("page"), "")
+				if err != nil {
+					return nil, err
+				}
+				return c.Projects.ListProjectMembers(ctx, p)
+			},
+		},
+		{
+			name:        "update-membership",
+			description: "Add a user to a project or change their role.",
+			flags: []flagSpec{
+				bodyFlag(`'{"role": "member"}'`),
+				{"project-urn", "", "URN of the project"},
+				{"user-urn", "", "URN of the user"},
+			},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				body, err := f.body()
+				if err != nil {
+					return nil, err
+				}
+				p, err := projectc.BuildUpdateMembershipPayload(body, f.get("project-urn"), f.get("user-urn"), "")
+				if err != nil {
+					return nil, err
+				}
+				return nil, c.Projects.UpdateMembership(ctx, p.ProjectUrn, p.UserUrn, p.Role)
+			},
+		},
+		{
+			name:        "remove-membership",
+			description: "Remove a user from a project.",
+			flags: []flagSpec{
+				{"project-urn", "", "URN of the project"},
+				{"user-urn", "", "URN of the user"},
+			},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				p, err := projectc.BuildRemoveMembershipPayload(f.get("project-urn"), f.get("user-urn"), "")
+				if err != nil {
+					return nil, err
+				}
+				return nil, c.Projects.RemoveMembership(ctx, p.ProjectUrn, p.UserUrn)
+			},
+		},
+		{
+			name:        "default-project",
+			description: "Show the default project of the caller.",
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				if _, err := projectc.BuildDefaultProjectPayload(""); err != nil {
+					return nil, err
+				}
+				return c.Projects.DefaultProject(ctx)
+			},
+		},
+		{
+			name:        "set-default-project",
+			description: "Set the default project of a user.",
+			flags:       []flagSpec{bodyFlag(`'{"project_urn": "urn:ivcap:project:..."}'`)},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				body, err := f.body()
+				if err != nil {
+					return nil, err
+				}
+				p, err := projectc.BuildSetDefaultProjectPayload(body, "")
+				if err != nil {
+					return nil, err
+				}
+				return nil, c.Projects.SetDefaultProject(ctx, p)
+			},
+		},
+		{
+			name:        "project-account",
+			description: "Show the billing account of a project.",
+			flags:       []flagSpec{{"project-urn", "", "URN of the project"}},
+			project:     "project-urn",
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				p, err := projectc.BuildProjectAccountPayload(f.get("project-urn"), "")
+				if err != nil {
+					return nil, err
+				}
+				return c.Projects.ProjectAccount(ctx, p.ProjectUrn)
+			},
+		},
+		{
+			name:        "set-project-account",
+			description: "Set the billing account of a project.",
+			flags: []flagSpec{
+				bodyFlag(`'{"account_urn": "urn:ivcap:account:..."}'`),
+				{"project-urn", "", "URN of the project"},
+			},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				body, err := f.body()
+				if err != nil {
+					return nil, err
+				}
+				p, err := projectc.BuildSetProjectAccountPayload(body, f.get("project-urn"), "")
+				if err != nil {
+					return nil, err
+				}
+				return nil, c.Projects.SetProjectAccount(ctx, p.ProjectUrn, p.AccountUrn)
+			},
+		},
+	},
+}
+
+var queueCommands = &group{
+	name:        "queue",
+	description: "Manage queues and their messages.",
+	commands: []*command{
+		{
+			name:        "create",
+			description: "Create a queue.",
+			flags:       []flagSpec{bodyFlag(`'{"name": "events", "description": "Events for the event service"}'`)},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				body, err := f.body()
+				if err != nil {
+					return nil, err
+				}
+				p, err := queuec.BuildCreatePayload(body, "")
+				if err != nil {
+					return nil, err
+				}
+				return c.Queues.Create(ctx, p.Queues)
+			},
+		},
+		{
+			name:        "read",
+			description: "Show a queue.",
+			flags:       []flagSpec{idFlag("ID of the queue")},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				p, err := queuec.BuildReadPayload(f.get("id"), "")
+				if err != nil {
+					return nil, err
+				}
+				return c.Queues.Read(ctx, p.ID)
+			},
+		},
+		{
+			name:        "delete",
+			description: "Delete a queue.",
+			flags:       []flagSpec{idFlag("ID of the queue")},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				p, err := queuec.BuildDeletePayload(f.get("id"), "")
+				if err != nil {
+					return nil, err
+				}
+				return nil, c.Queues.Delete(ctx, p.ID)
+			},
+		},
+		{
+			name:        "list",
+			description: "List queues.",
+			flags:       listFlags,
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				p, err := queuec.BuildListPayload(f.get("limit"), f.get("page"), f.get("filter"), f.get("order-by"), f.get("order-desc"), f.get("at-time"), "")
+				if err != nil {
+					return nil, err
+				}
+				return c.Queues.List(ctx, p)
+			},
+		},
+		{
+			name:        "enqueue",
+			description: "Send a message to a queue.",
+			flags: []flagSpec{
+				bodyFlag(`'{"temperature": "21", "location": "Buoy101"}'`),
+				idFlag("ID of the queue"),
+				{"schema", "", "schema of the message"},
+				{"content-type", "application/json", "content type of the message"},
+			},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				body, err := f.body()
+				if err != nil {
+					return nil, err
+				}
+				p, err := queuec.BuildEnqueuePayload(body, f.get("id"), f.get("schema"), "", f.get("content-type"))
+				if err != nil {
+					return nil, err
+				}
+				return c.Queues.Enqueue(ctx, p)
+			},
+		},
+		{
+			name:        "dequeue",
+			description: "Receive messages from a queue.",
+			flags: []flagSpec{
+				idFlag("ID of the queue"),
+				{"limit", "", "maximum number of messages to receive"},
+			},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				p, err := queuec.BuildDequeuePayload(f.get("id"), f.get("limit"), "")
+				if err != nil {
+					return nil, err
+				}
+				limit := 0
+				if p.Limit != nil {
+					limit = *p.Limit
+				}
+				return c.Queues.Dequeue(ctx, p.ID, limit)
+			},
+		},
+	},
+}
+
+var searchCommands = &group{
+	name:        "search",
+	description: "Query the graph of entities and aspects.",
+	commands: []*command{
+		{
+			name:        "search",
+			description: "Run a query.",
+			flags: []flagSpec{
+				{"body", "", "query, @FILE or - for standard input"},
+				{"content-type", "application/datalog+mangle", "content type of the query"},
+				{"at-time", "", "query the state at this RFC 3339 time"},
+				{"limit", "10", "maximum number of items to return (1-50)"},
+				{"page", "", "JSON encoded page token returned by a previous call"},
+			},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				body, err := f.body()
+				if err != nil {
+					return nil, err
+				}
+				p, err := searchc.BuildSearchPayload(body, f.get("at-time"), f.get("limit"), f.get("page"), "", f.get("content-type"))
+				if err != nil {
+					return nil, err
+				}
+				return c.Search.Search(ctx, p)
+			},
+		},
+	},
+}
+
+var secretCommands = &group{
+	name:        "secret",
+	description: "Manage secrets.",
+	commands: []*command{
+		{
+			name:        "list",
+			description: "List secrets.",
+			flags: []flagSpec{
+				{"page", "", "page token returned by a previous call"},
+				{"filter", "", "filter expression"},
+				{"offset", "", "offset of the first secret"},
+				{"limit", "", "maximum number of items to return"},
+			},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				p, err := secretc.BuildListPayload(f.get("page"), f.get("filter"), f.get("offset"), f.get("limit"), "")
+				if err != nil {
+					return nil, err
+				}
+				return c.Secrets.List(ctx, p)
+			},
+		},
+		{
+			name:        "get",
+			description: "Show a secret.",
+			flags: []flagSpec{
+				{"secret-name", "", "name of the secret"},
+				{"secret-type", "", "type of the secret"},
+			},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				p, err := secretc.BuildGetPayload(f.get("secret-name"), f.get("secret-type"), "")
+				if err != nil {
+					return nil, err
+				}
+				return c.Secrets.Get(ctx, p)
+			},
+		},
+		{
+			name:        "set",
+			description: "Create or replace a secret.",
+			flags:       []flagSpec{bodyFlag(`'{"secret-name": "key", "secret-value": "...", "expiry-time": 1767225600}'`)},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				body, err := f.body()
+				if err != nil {
+					return nil, err
+				}
+				p, err := secretc.BuildSetPayload(body, "")
+				if err != nil {
+					return nil, err
+				}
+				return nil, c.Secrets.Set(ctx, p.Secrets)
+			},
+		},
+		{
+			name:        "create-key",
+			description: "Create a secret holding a random key to encrypt artifacts with.",
+			flags:       []flagSpec{{"name", "", "name of the secret"}},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				if f.get("name") == "" {
+					return nil, errors.New("missing -name")
+				}
+				return nil, c.Secrets.CreateKey(ctx, f.get("name"))
+			},
+		},
+	},
+}
+
+var serviceCommands = &group{
+	name:        "service",
+	description: "Manage service definitions.",
+	commands: []*command{
+		{
+			name:        "list",
+			description: "List services.",
+			flags:       listFlags,
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				p, err := servicec.BuildListPayload(f.get("limit"), f.get("page"), f.get("filter"), f.get("order-by"), f.get("order-desc"), f.get("at-time"), "")
+				if err != nil {
+					return nil, err
+				}
+				return c.Services.List(ctx, p)
+			},
+		},
+		{
+			name:        "create-service",
+			description: "Create a service.",
+			flags:       []flagSpec{bodyFlag("@service.json")},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				body, err := f.body()
+				if err != nil {
+					return nil, err
+				}
+				p, err := servicec.BuildCreateServicePayload(body, "")
+				if err != nil {
+					return nil, err
+				}
+				return c.Services.CreateService(ctx, p.Services)
+			},
+		},
+		{
+			name:        "read",
+			description: "Show a service.",
+			flags:       []flagSpec{idFlag("ID of the service")},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				p, err := servicec.BuildReadPayload(f.get("id"), "")
+				if err != nil {
+					return nil, err
+				}
+				return c.Services.Read(ctx, p.ID)
+			},
+		},
+		{
+			name:        "update",
+			description: "Replace a service.",
+			flags: []flagSpec{
+				bodyFlag("@service.json"),
+				idFlag("ID of the service"),
+				{"force-create", "", "create the service if it does not exist"},
+			},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				body, err := f.body()
+				if err != nil {
+					return nil, err
+				}
+				p, err := servicec.BuildUpdatePayload(body, f.get("id"), f.get("force-create"), "")
+				if err != nil {
+					return nil, err
+				}
+				return c.Services.Update(ctx, p)
+			},
+		},
+		{
+			name:        "delete",
+			description: "Delete a service.",
+			flags:       []flagSpec{idFlag("ID of the service")},
+			run: func(ctx context.Context, c *ivcap.Client, f *flags) (any, error) {
+				p, err := servicec.BuildDeletePayload(f.get("id"), "")
+				if err != nil {
+					return nil, err
+				}
+				return nil, c.Services.Delete(ctx, p.ID)
+			},
+		},
+	},
+}
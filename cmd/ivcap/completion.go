@@ -0,0 +1,91 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// completion writes the shell completion script for the shell named in args
+// to w. Zsh loads the bash script through bashcompinit.
+func completion(w io.Writer, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: ivcap completion bash|zsh")
+	}
+	switch args[0] {
+	case "bash":
+	case "zsh":
+		fmt.Fprintln(w, "autoload -U +X bashcompinit && bashcompinit")
+	default:
+		return fmt.Errorf("unsupported shell %q, must be bash or zsh", args[0])
+	}
+	var globalFlags []string
+	fs := flag.NewFlagSet("ivcap", flag.ContinueOnError)
+	(&globals{}).register(fs)
+	fs.VisitAll(func(f *flag.Flag) { globalFlags = append(globalFlags, "-"+f.Name) })
+
+	var services []string
+	for _, grp := range groups {
+		services = append(services, grp.name)
+	}
+	fmt.Fprintln(w, "_ivcap() {")
+	fmt.Fprintln(w, `	local cur="${COMP_WORDS[COMP_CWORD]}" words=() i`)
+	// All flags take a value, so the word following a flag without "=" is
+	// skipped.
+	fmt.Fprintln(w, `	for ((i = 1; i < COMP_CWORD; i++)); do`)
+	fmt.Fprintln(w, `		case "${COMP_WORDS[i]}" in`)
+	fmt.Fprintln(w, `		-*=*) ;;`)
+	fmt.Fprintln(w, `		-*) ((i++)) ;;`)
+	fmt.Fprintln(w, `		*) words+=("${COMP_WORDS[i]}") ;;`)
+	fmt.Fprintln(w, `		esac`)
+	fmt.Fprintln(w, `	done`)
+	fmt.Fprintln(w, `	local opts`)
+	fmt.Fprintln(w, `	case "${#words[@]}:${words[*]}" in`)
+	fmt.Fprintf(w, "\t0:*) opts=%q ;;\n", strings.Join(append(services, "completion", "context", "help"), " "))
+	fmt.Fprintln(w, "\t1:completion) opts=\"bash zsh\" ;;")
+	fmt.Fprintln(w, "\t1:context) opts=\"list use show set delete\" ;;")
+	fmt.Fprintln(w, "\t3:context\\ set\\ *) opts=\"-url -jwt -token-file -token-env -default-project -default-account -timeout\" ;;")
+	for _, grp := range groups {
+		var names []string
+		for _, cmd := range grp.commands {
+			names = append(names, cmd.name)
+		}
+		fmt.Fprintf(w, "\t1:%s) opts=%q ;;\n", grp.name, strings.Join(names, " "))
+		for _, cmd := range grp.commands {
+			var flags []string
+			for _, spec := range cmd.flags {
+				flags = append(flags, "-"+spec.name)
+			}
+			fmt.Fprintf(w, "\t2:%s\\ %s) opts=%q ;;\n", grp.name, cmd.name, strings.Join(append(flags, globalFlags...), " "))
+		}
+	}
+	fmt.Fprintln(w, "\t*) opts=\"\" ;;")
+	fmt.Fprintln(w, "\tesac")
+	fmt.Fprintln(w, `	if [[ "$cur" == -* ]]; then`)
+	fmt.Fprintf(w, "\t\t[[ ${#words[@]} -lt 2 ]] && opts=%q\n", strings.Join(globalFlags, " "))
+	fmt.Fprintln(w, `		COMPREPLY=($(compgen -W "$opts" -- "$cur"))`)
+	fmt.Fprintln(w, `	elif [[ ${#words[@]} -lt 2 ]]; then`)
+	fmt.Fprintln(w, `		COMPREPLY=($(compgen -W "$opts" -- "$cur"))`)
+	fmt.Fprintln(w, `	else`)
+	fmt.Fprintln(w, `		COMPREPLY=($(compgen -f -- "$cur"))`)
+	fmt.Fprintln(w, `	fi`)
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w, "complete -o default -F _ivcap ivcap")
+	return nil
+}
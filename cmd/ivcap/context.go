@@ -0,0 +1,173 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ivcap-works/ivcap-core-api/ivcap"
+	"gopkg.in/yaml.v3"
+)
+
+// loadConfig reads the configuration file and returns it with its path.
+func loadConfig() (*ivcap.Config, string, error) {
+	path, err := ivcap.DefaultConfigPath()
+	if err != nil {
+		return nil, "", err
+	}
+	cfg, err := ivcap.LoadConfig(path)
+	return cfg, path, err
+}
+
+// contextCommand runs the "ivcap context" commands managing the contexts of
+// the configuration file.
+func contextCommand(stdout, stderr io.Writer, args []string) error {
+	usage := func() {
+		fmt.Fprintln(stderr, "Usage: ivcap context list")
+		fmt.Fprintln(stderr, "       ivcap context use NAME")
+		fmt.Fprintln(stderr, "       ivcap context show [NAME]")
+		fmt.Fprintln(stderr, "       ivcap context set NAME [flags]")
+		fmt.Fprintln(stderr, "       ivcap context delete NAME")
+	}
+	if len(args) == 0 {
+		usage()
+		return flag.ErrHelp
+	}
+	cfg, path, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	name := func() (string, error) {
+		if len(args) != 2 {
+			usage()
+			return "", fmt.Errorf("context %s expects a context name", args[0])
+		}
+		return args[1], nil
+	}
+	switch args[0] {
+	case "list":
+		tw := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "CURRENT\tNAME\tURL")
+		for _, cx := range cfg.Contexts {
+			current := ""
+			if cx.Name == cfg.CurrentContext {
+				current = "*"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", current, cx.Name, cx.URL)
+		}
+		return tw.Flush()
+	case "use":
+		n, err := name()
+		if err != nil {
+			return err
+		}
+		if err := cfg.Use(n); err != nil {
+			return err
+		}
+		return cfg.Save(path)
+	case "show":
+		var n string
+		if len(args) > 1 {
+			if n, err = name(); err != nil {
+				return err
+			}
+		}
+		cx, err := cfg.Context(n)
+		if err != nil {
+			return err
+		}
+		enc := yaml.NewEncoder(stdout)
+		enc.SetIndent(2)
+		if err := enc.Encode(cx); err != nil {
+			return err
+		}
+		return enc.Close()
+	case "set":
+		if len(args) < 2 {
+			usage()
+			return errors.New("context set expects a context name")
+		}
+		cx, err := cfg.Context(args[1])
+		if errors.Is(err, ivcap.ErrNoContext) {
+			cx, err = &ivcap.Context{Name: args[1]}, nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := setContext(cx, args[2:], stderr); err != nil {
+			return err
+		}
+		cfg.Set(cx)
+		if cfg.CurrentContext == "" {
+			cfg.CurrentContext = cx.Name
+		}
+		return cfg.Save(path)
+	case "delete":
+		n, err := name()
+		if err != nil {
+			return err
+		}
+		if err := cfg.Delete(n); err != nil {
+			return err
+		}
+		return cfg.Save(path)
+	}
+	usage()
+	return fmt.Errorf("unknown context command %q", args[0])
+}
+
+// setContext updates the settings of cx given as flags in args. Settings
+// without a flag are left unchanged.
+func setContext(cx *ivcap.Context, args []string, stderr io.Writer) error {
+	fs := flag.NewFlagSet("context set", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	url := fs.String("url", "", "URL of the IVCAP deployment")
+	jwt := fs.String("jwt", "", "fixed JWT authorizing the requests")
+	tokenFile := fs.String("token-file", "", "file holding the token authorizing the requests")
+	tokenEnv := fs.String("token-env", "", "environment variable holding the token authorizing the requests")
+	project := fs.String("default-project", "", "URN of the project read or listed when none is given")
+	account := fs.String("default-account", "", "URN of the account new projects are billed to when none is given")
+	timeout := fs.Duration("timeout", 0, "timeout of each request")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "url":
+			cx.URL = *url
+		case "jwt":
+			cx.Token = ivcap.TokenConfig{JWT: *jwt}
+		case "token-file":
+			cx.Token = ivcap.TokenConfig{File: *tokenFile}
+		case "token-env":
+			cx.Token = ivcap.TokenConfig{Env: *tokenEnv}
+		case "default-project":
+			cx.DefaultProject = *project
+		case "default-account":
+			cx.DefaultAccount = *account
+		case "timeout":
+			cx.Timeout = *timeout
+		}
+	})
+	return nil
+}
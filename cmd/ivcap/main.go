@@ -0,0 +1,202 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command ivcap is a command line client for the services of an IVCAP
+// deployment. Commands are named after the service and the method they call:
+//
+//	ivcap [global flags] SERVICE METHOD [flags]
+//
+// such as "ivcap order create -body @order.json" or "ivcap aspect list
+// -schema urn:example:schema". Payloads are built with the Build*Payload
+// functions of the generated http/* packages, so flags and bodies take the
+// same values as the API.
+//
+// The deployment URL and token are taken from the current context of the
+// configuration file (see ivcap.Config), managed with "ivcap context".
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/ivcap-works/ivcap-core-api/ivcap"
+)
+
+// globals holds the flags accepted before and after the command name.
+type globals struct {
+	context   string
+	url       string
+	jwt       string
+	tokenFile string
+	output    string
+	timeout   time.Duration
+}
+
+// register defines the global flags on fs.
+func (g *globals) register(fs *flag.FlagSet) {
+	fs.StringVar(&g.context, "context", g.context, "name of the context to use instead of the current one [$IVCAP_CONTEXT]")
+	fs.StringVar(&g.url, "url", g.url, "URL of the IVCAP deployment [$IVCAP_URL]")
+	fs.StringVar(&g.jwt, "jwt", g.jwt, "JWT authorizing the requests [$IVCAP_JWT]")
+	fs.StringVar(&g.tokenFile, "token-file", g.tokenFile, "file holding the token authorizing the requests")
+	fs.StringVar(&g.output, "output", g.output, "output format: json, yaml or table")
+	fs.StringVar(&g.output, "o", g.output, "shorthand for -output")
+	fs.DurationVar(&g.timeout, "timeout", g.timeout, "timeout of each request (default 30s)")
+}
+
+// client returns the client configured by the selected context. Flags and
+// environment variables take precedence over the settings of the context.
+func (g *globals) client() (*ivcap.Client, error) {
+	cx, err := g.selectedContext()
+	if err != nil {
+		return nil, err
+	}
+	var opts []ivcap.Option
+	url := g.url
+	if cx != nil {
+		opts = cx.Options()
+		if url == "" {
+			url = cx.URL
+		}
+	}
+	if url == "" {
+		return nil, errors.New("missing deployment URL, set -url, $IVCAP_URL or a context")
+	}
+	switch {
+	case g.jwt != "":
+		opts = append(opts, ivcap.WithJWT(g.jwt))
+	case g.tokenFile != "":
+		opts = append(opts, ivcap.WithTokenSource(ivcap.FileTokenSource(g.tokenFile)))
+	case os.Getenv("IVCAP_JWT") != "" || cx == nil || cx.Token.TokenSource() == nil:
+		opts = append(opts, ivcap.WithTokenSource(ivcap.EnvTokenSource("IVCAP_JWT")))
+	}
+	if g.timeout > 0 {
+		opts = append(opts, ivcap.WithTimeout(g.timeout))
+	}
+	return ivcap.New(url, opts...)
+}
+
+// selectedContext returns the context named by -context, or the current
+// context of the configuration. It returns nil if neither is set.
+func (g *globals) selectedContext() (*ivcap.Context, error) {
+	cfg, _, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	if g.context == "" && cfg.CurrentContext == "" {
+		return nil, nil
+	}
+	return cfg.Context(g.context)
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	err := run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	stop()
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		msg := err.Error()
+		if !strings.HasPrefix(msg, "ivcap: ") {
+			msg = "ivcap: " + msg
+		}
+		fmt.Fprintln(os.Stderr, msg)
+		os.Exit(1)
+	}
+}
+
+// run executes the command line args, writing results to stdout and usage to
+// stderr.
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	g := &globals{
+		context: os.Getenv("IVCAP_CONTEXT"),
+		url:     os.Getenv("IVCAP_URL"),
+		output:  "json",
+	}
+	fs := flag.NewFlagSet("ivcap", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() { usage(stderr, fs) }
+	g.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	args = fs.Args()
+	if len(args) == 0 || args[0] == "help" {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+	switch args[0] {
+	case "completion":
+		return completion(stdout, args[1:])
+	case "context":
+		return contextCommand(stdout, stderr, args[1:])
+	}
+	grp := findGroup(args[0])
+	if grp == nil {
+		fs.Usage()
+		return fmt.Errorf("unknown service %q", args[0])
+	}
+	if len(args) < 2 {
+		grp.usage(stderr)
+		return flag.ErrHelp
+	}
+	cmd := grp.find(args[1])
+	if cmd == nil {
+		grp.usage(stderr)
+		return fmt.Errorf("unknown %s command %q", grp.name, args[1])
+	}
+	f := newFlags(grp, cmd, stderr)
+	g.register(f.fs)
+	if err := f.fs.Parse(args[2:]); err != nil {
+		return err
+	}
+	if f.fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %s", strings.Join(f.fs.Args(), " "))
+	}
+	format, err := outputFormat(g.output)
+	if err != nil {
+		return err
+	}
+	c, err := g.client()
+	if err != nil {
+		return err
+	}
+	if p := c.DefaultProject(); p != "" && cmd.project != "" {
+		f.setDefault(cmd.project, p)
+	}
+	res, err := cmd.run(ctx, c, f)
+	if err != nil {
+		return err
+	}
+	return write(stdout, format, res)
+}
+
+// usage writes the usage of the command to w.
+func usage(w io.Writer, fs *flag.FlagSet) {
+	fmt.Fprintln(w, "Usage: ivcap [global flags] SERVICE COMMAND [flags]")
+	fmt.Fprintln(w, "       ivcap context list|use|show|set|delete")
+	fmt.Fprintln(w, "       ivcap completion bash|zsh")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Services:")
+	for _, grp := range groups {
+		fmt.Fprintf(w, "  %-10s %s\n", grp.name, grp.description)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Global flags:")
+	fs.PrintDefaults()
+}
@@ -0,0 +1,255 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats selected with -output.
+const (
+	formatJSON  = "json"
+	formatYAML  = "yaml"
+	formatTable = "table"
+)
+
+// columns lists the table columns shown before all others, in order.
+var columns = []string{"id", "urn", "name", "status"}
+
+// outputFormat validates the format selected with -output.
+func outputFormat(s string) (string, error) {
+	switch s {
+	case formatJSON, formatYAML, formatTable:
+		return s, nil
+	}
+	return "", fmt.Errorf("unknown output format %q, must be json, yaml or table", s)
+}
+
+// write writes the result res of a command to w in format. Streamed results
+// are copied as is.
+func write(w io.Writer, format string, res any) error {
+	if rc, ok := res.(io.ReadCloser); ok {
+		defer rc.Close()
+		_, err := io.Copy(w, rc)
+		return err
+	}
+	v := plain(reflect.ValueOf(res))
+	if v == nil {
+		return nil
+	}
+	switch format {
+	case formatYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+		return enc.Close()
+	case formatTable:
+		return table(w, v)
+	default:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+}
+
+// plain converts v into maps, slices and scalars keyed by the kebab-case
+// field names, as used by the API. Nil fields are dropped.
+func plain(v reflect.Value) any {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Struct:
+		if t, ok := v.Interface().(encoding.TextMarshaler); ok {
+			// Such as time.Time.
+			if b, err := t.MarshalText(); err == nil {
+				return string(b)
+			}
+		}
+		m := make(map[string]any, v.NumField())
+		for i := 0; i < v.NumField(); i++ {
+			sf := v.Type().Field(i)
+			if !sf.IsExported() {
+				continue
+			}
+			if fv := plain(v.Field(i)); fv != nil {
+				m[kebab(sf.Name)] = fv
+			}
+		}
+		return m
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return string(v.Bytes())
+		}
+		s := make([]any, v.Len())
+		for i := range s {
+			s[i] = plain(v.Index(i))
+		}
+		return s
+	case reflect.Map:
+		m := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			m[fmt.Sprint(iter.Key().Interface())] = plain(iter.Value())
+		}
+		return m
+	}
+	return v.Interface()
+}
+
+// kebab converts a Go field name such as "AtTime" or "AccountURN" into
+// "at-time" or "account-urn".
+func kebab(name string) string {
+	r := []rune(name)
+	var b strings.Builder
+	for i, c := range r {
+		if i > 0 && unicode.IsUpper(c) {
+			prev := r[i-1]
+			next := i+1 < len(r) && unicode.IsLower(r[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && next) {
+				b.WriteByte('-')
+			}
+		}
+		b.WriteRune(unicode.ToLower(c))
+	}
+	return b.String()
+}
+
+// table writes v as a table. Lists, and results holding a single list such
+// as the pages returned by list commands, are written one item per row.
+// Other results are written as one row per field.
+func table(w io.Writer, v any) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	if rows, ok := rowsOf(v); ok {
+		cols := columnsOf(rows)
+		fmt.Fprintln(tw, strings.ToUpper(strings.Join(cols, "\t")))
+		for _, row := range rows {
+			cells := make([]string, len(cols))
+			for i, col := range cols {
+				cells[i] = cell(row[col])
+			}
+			fmt.Fprintln(tw, strings.Join(cells, "\t"))
+		}
+		return tw.Flush()
+	}
+	m, ok := v.(map[string]any)
+	if !ok {
+		fmt.Fprintln(tw, cell(v))
+		return tw.Flush()
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(tw, "%s\t%s\n", k, cell(m[k]))
+	}
+	return tw.Flush()
+}
+
+// rowsOf returns the items of v if v is a list of objects or an object with
+// a single list field, ignoring "links".
+func rowsOf(v any) ([]map[string]any, bool) {
+	if m, ok := v.(map[string]any); ok {
+		var list any
+		for k, fv := range m {
+			if _, ok := fv.([]any); !ok || k == "links" {
+				continue
+			}
+			if list != nil {
+				return nil, false
+			}
+			list = fv
+		}
+		v = list
+	}
+	items, ok := v.([]any)
+	if !ok {
+		return nil, false
+	}
+	rows := make([]map[string]any, 0, len(items))
+	for _, item := range items {
+		row, ok := item.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		rows = append(rows, row)
+	}
+	return rows, true
+}
+
+// columnsOf returns the names of the scalar fields of rows, starting with
+// the preferred columns.
+func columnsOf(rows []map[string]any) []string {
+	seen := map[string]bool{}
+	var rest []string
+	for _, row := range rows {
+		for k, v := range row {
+			switch v.(type) {
+			case map[string]any, []any:
+				continue
+			}
+			if !seen[k] {
+				seen[k] = true
+				rest = append(rest, k)
+			}
+		}
+	}
+	var cols []string
+	for _, col := range columns {
+		if seen[col] {
+			cols = append(cols, col)
+			delete(seen, col)
+		}
+	}
+	others := rest[:0]
+	for _, k := range rest {
+		if seen[k] {
+			others = append(others, k)
+		}
+	}
+	sort.Strings(others)
+	return append(cols, others...)
+}
+
+// cell formats v for a table cell. Nested values are written as JSON.
+func cell(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case map[string]any, []any:
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
@@ -2,11 +2,16 @@ module github.com/ivcap-works/ivcap-core-api
 
 go 1.19
 
-require goa.design/goa/v3 v3.11.0
+require (
+	github.com/google/uuid v1.3.0
+	github.com/klauspost/compress v1.17.6
+	github.com/santhosh-tekuri/jsonschema/v5 v5.3.1
+	goa.design/goa/v3 v3.11.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/dimfeld/httptreemux/v5 v5.5.0 // indirect
-	github.com/google/uuid v1.3.0 // indirect
 	github.com/gorilla/websocket v1.5.0 // indirect
 	github.com/hashicorp/errwrap v1.1.0 // indirect
 	github.com/hashicorp/go-multierror v1.1.1 // indirect
@@ -0,0 +1,69 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	goahttp "goa.design/goa/v3/http"
+)
+
+// Blob is the content of an artifact.
+type Blob struct {
+	// Content of the artifact.
+	Content io.ReadSeeker
+	// Etag of the content, sent in the ETag header and matched against
+	// If-Range.
+	Etag string
+	// ContentType is the MIME type of the content.
+	ContentType string
+	// ContentEncoding is the encoding the content is stored with, such as
+	// "gzip".
+	ContentEncoding string
+	// ModTime is the time the content was last modified.
+	ModTime time.Time
+}
+
+// Blobber is implemented by artifact services serving the content of the
+// artifacts at their data link.
+type Blobber interface {
+	// Blob returns the content of artifact id.
+	Blob(ctx context.Context, jwt, id string) (*Blob, error)
+}
+
+// MountBlob configures the mux to serve the content of artifacts at
+// /1/artifacts/{id}/blob with b. Range requests are supported.
+func MountBlob(mux goahttp.Muxer, b Blobber) {
+	serve := func(w http.ResponseWriter, req *http.Request) {
+		blob, err := b.Blob(req.Context(), bearer(req), mux.Vars(req)["id"])
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		w.Header().Set("Etag", blob.Etag)
+		if blob.ContentType != "" {
+			w.Header().Set("Content-Type", blob.ContentType)
+		}
+		if blob.ContentEncoding != "" {
+			w.Header().Set("Content-Encoding", blob.ContentEncoding)
+		}
+		http.ServeContent(w, req, "", blob.ModTime, blob.Content)
+	}
+	mux.Handle("GET", "/1/artifacts/{id}/blob", serve)
+	mux.Handle("HEAD", "/1/artifacts/{id}/blob", serve)
+}
@@ -0,0 +1,120 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	artifact "github.com/ivcap-works/ivcap-core-api/gen/artifact"
+	client "github.com/ivcap-works/ivcap-core-api/http/artifact"
+	"github.com/ivcap-works/ivcap-core-api/http/internal/wire"
+	goahttp "goa.design/goa/v3/http"
+	goa "goa.design/goa/v3/pkg"
+)
+
+// DecodeListRequest returns a decoder for requests sent to the artifact list
+// endpoint.
+func DecodeListRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &artifact.ListPayload{
+			Limit:     q.Limit("limit"),
+			Page:      q.Query("page"),
+			Filter:    q.Query("filter"),
+			OrderBy:   q.Query("order-by"),
+			OrderDesc: q.QueryBoolDefault("order-desc", true),
+			AtTime:    q.Query("at-time"),
+			JWT:       q.JWT(),
+		}
+		if p.AtTime != nil {
+			err := goa.ValidateFormat("at-time", *p.AtTime, goa.FormatDateTime)
+			return p, goa.MergeErrors(q.Err(), err)
+		}
+		return p, q.Err()
+	}
+}
+
+// EncodeListResponse returns an encoder for responses returned by the
+// artifact list endpoint.
+func EncodeListResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusOK, (*client.ListResponseBody)(nil))
+}
+
+// DecodeReadRequest returns a decoder for requests sent to the artifact read
+// endpoint.
+func DecodeReadRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &artifact.ReadPayload{ID: q.Path("id"), JWT: q.JWT()}
+		return p, q.Err()
+	}
+}
+
+// EncodeReadResponse returns an encoder for responses returned by the
+// artifact read endpoint.
+func EncodeReadResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusOK, (*client.ReadResponseBody)(nil))
+}
+
+// DecodeUploadRequest returns a decoder for requests sent to the artifact
+// upload endpoint. The request body is passed on unread as the artifact
+// content.
+func DecodeUploadRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &artifact.UploadPayload{
+			ContentType:     q.Header("Content-Type"),
+			ContentEncoding: q.Header("Content-Encoding"),
+			ContentLength:   q.HeaderInt("Content-Length"),
+			Name:            q.Header("X-Name"),
+			Collection:      q.Header("X-Collection"),
+			Policy:          q.Header("X-Policy"),
+			XContentType:    q.Header("X-Content-Type"),
+			XContentLength:  q.HeaderInt("X-Content-Length"),
+			UploadLength:    q.HeaderInt("Upload-Length"),
+			TusResumable:    q.Header("Tus-Resumable"),
+			JWT:             q.JWT(),
+		}
+		if p.ContentLength == nil && r.ContentLength >= 0 {
+			n := int(r.ContentLength)
+			p.ContentLength = &n
+		}
+		if err := q.Err(); err != nil {
+			return nil, err
+		}
+		return &artifact.UploadRequestData{Payload: p, Body: r.Body}, nil
+	}
+}
+
+// EncodeUploadResponse returns an encoder for responses returned by the
+// artifact upload endpoint.
+func EncodeUploadResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	encode := wire.Encode(encoder, http.StatusCreated, (*client.UploadResponseBody)(nil))
+	return func(ctx context.Context, w http.ResponseWriter, v any) error {
+		res, _ := v.(*artifact.ArtifactUploadRT)
+		if res != nil {
+			w.Header().Set("Location", res.Location)
+			if res.TusResumable != nil {
+				w.Header().Set("Tus-Resumable", *res.TusResumable)
+			}
+			if res.TusOffset != nil {
+				w.Header().Set("Upload-Offset", strconv.FormatInt(*res.TusOffset, 10))
+			}
+		}
+		return encode(ctx, w, v)
+	}
+}
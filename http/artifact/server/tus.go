@@ -0,0 +1,111 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	artifact "github.com/ivcap-works/ivcap-core-api/gen/artifact"
+	goahttp "goa.design/goa/v3/http"
+)
+
+// TusVersion is the version of the TUS resumable upload protocol served.
+const TusVersion = "1.0.0"
+
+// ErrOffsetMismatch is returned by a Resumer when the offset of a PATCH
+// request does not match the content received so far.
+var ErrOffsetMismatch = errors.New("upload offset does not match")
+
+// Resumer is implemented by artifact services accepting the rest of the
+// content of an upload in TUS PATCH requests sent to the Location returned by
+// the upload method.
+type Resumer interface {
+	// UploadOffset returns the number of bytes received for artifact id and
+	// its announced length, or -1 if unknown.
+	UploadOffset(ctx context.Context, jwt, id string) (offset, length int64, err error)
+	// AppendUpload appends body to the content of artifact id, which must
+	// have received exactly offset bytes so far. It returns the new offset.
+	AppendUpload(ctx context.Context, jwt, id string, offset int64, body io.Reader) (int64, error)
+}
+
+// MountResumable configures the mux to serve the TUS HEAD and PATCH requests
+// sent to /1/artifacts/{id} with r.
+func MountResumable(mux goahttp.Muxer, r Resumer) {
+	mux.Handle("HEAD", "/1/artifacts/{id}", func(w http.ResponseWriter, req *http.Request) {
+		offset, length, err := r.UploadOffset(req.Context(), bearer(req), mux.Vars(req)["id"])
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		w.Header().Set("Tus-Resumable", TusVersion)
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Upload-Offset", strconv.FormatInt(offset, 10))
+		if length >= 0 {
+			w.Header().Set("Upload-Length", strconv.FormatInt(length, 10))
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.Handle("PATCH", "/1/artifacts/{id}", func(w http.ResponseWriter, req *http.Request) {
+		if ct := req.Header.Get("Content-Type"); ct != "application/offset+octet-stream" {
+			http.Error(w, "unsupported content type "+ct, http.StatusUnsupportedMediaType)
+			return
+		}
+		offset, err := strconv.ParseInt(req.Header.Get("Upload-Offset"), 10, 64)
+		if err != nil || offset < 0 {
+			http.Error(w, "invalid Upload-Offset header", http.StatusBadRequest)
+			return
+		}
+		next, err := r.AppendUpload(req.Context(), bearer(req), mux.Vars(req)["id"], offset, req.Body)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		w.Header().Set("Tus-Resumable", TusVersion)
+		w.Header().Set("Upload-Offset", strconv.FormatInt(next, 10))
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// bearer returns the token of the Authorization header of req.
+func bearer(req *http.Request) string {
+	return strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+}
+
+// writeError writes the status matching err, as returned by a Resumer or
+// a Blobber.
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	var (
+		notFound     *artifact.ResourceNotFoundT
+		unauthorized *artifact.UnauthorizedT
+		badRequest   *artifact.BadRequestT
+	)
+	switch {
+	case errors.Is(err, ErrOffsetMismatch):
+		status = http.StatusConflict
+	case errors.As(err, &notFound):
+		status = http.StatusNotFound
+	case errors.As(err, &unauthorized):
+		status = http.StatusUnauthorized
+	case errors.As(err, &badRequest):
+		status = http.StatusBadRequest
+	}
+	http.Error(w, err.Error(), status)
+}
@@ -0,0 +1,146 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"net/http"
+
+	aspect "github.com/ivcap-works/ivcap-core-api/gen/aspect"
+	client "github.com/ivcap-works/ivcap-core-api/http/aspect"
+	"github.com/ivcap-works/ivcap-core-api/http/internal/wire"
+	goahttp "goa.design/goa/v3/http"
+	goa "goa.design/goa/v3/pkg"
+)
+
+// DecodeReadRequest returns a decoder for requests sent to the aspect read
+// endpoint.
+func DecodeReadRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &aspect.ReadPayload{ID: q.Path("id"), JWT: q.JWT()}
+		return p, q.Err()
+	}
+}
+
+// EncodeReadResponse returns an encoder for responses returned by the aspect
+// read endpoint.
+func EncodeReadResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusOK, (*client.ReadResponseBody)(nil))
+}
+
+// DecodeListRequest returns a decoder for requests sent to the aspect list
+// endpoint.
+func DecodeListRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &aspect.ListPayload{
+			Entity:         q.Query("entity"),
+			Schema:         q.Query("schema"),
+			ContentPath:    q.Query("content-path"),
+			AtTime:         q.Query("at-time"),
+			Limit:          q.Limit("limit"),
+			Filter:         q.QueryString("filter", ""),
+			OrderBy:        q.QueryString("order-by", "valid_from"),
+			OrderDirection: q.QueryString("order-direction", "DESC"),
+			IncludeContent: q.QueryBool("include-content"),
+			Page:           q.Query("page"),
+			JWT:            q.JWT(),
+		}
+		if p.AtTime != nil {
+			err := goa.ValidateFormat("at-time", *p.AtTime, goa.FormatDateTime)
+			return p, goa.MergeErrors(q.Err(), err)
+		}
+		return p, q.Err()
+	}
+}
+
+// EncodeListResponse returns an encoder for responses returned by the aspect
+// list endpoint.
+func EncodeListResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusOK, (*client.ListResponseBody)(nil))
+}
+
+// DecodeCreateRequest returns a decoder for requests sent to the aspect
+// create endpoint.
+func DecodeCreateRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &aspect.CreatePayload{
+			Entity:      q.RequiredQuery("entity"),
+			Schema:      q.RequiredQuery("schema"),
+			ContentType: q.RequiredHeader("Content-Type"),
+			Policy:      q.Query("policy"),
+			JWT:         q.JWT(),
+		}
+		if err := q.Err(); err != nil {
+			return nil, err
+		}
+		if err := decoder(r).Decode(&p.Content); err != nil {
+			return nil, goa.DecodePayloadError(err.Error())
+		}
+		return p, nil
+	}
+}
+
+// EncodeCreateResponse returns an encoder for responses returned by the
+// aspect create endpoint.
+func EncodeCreateResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusOK, (*client.CreateResponseBody)(nil))
+}
+
+// DecodeUpdateRequest returns a decoder for requests sent to the aspect
+// update endpoint.
+func DecodeUpdateRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &aspect.UpdatePayload{
+			Entity:      q.RequiredQuery("entity"),
+			Schema:      q.RequiredQuery("schema"),
+			ContentType: q.RequiredHeader("Content-Type"),
+			Policy:      q.Query("policy"),
+			JWT:         q.JWT(),
+		}
+		if err := q.Err(); err != nil {
+			return nil, err
+		}
+		if err := decoder(r).Decode(&p.Content); err != nil {
+			return nil, goa.DecodePayloadError(err.Error())
+		}
+		return p, nil
+	}
+}
+
+// EncodeUpdateResponse returns an encoder for responses returned by the
+// aspect update endpoint.
+func EncodeUpdateResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusOK, (*client.UpdateResponseBody)(nil))
+}
+
+// DecodeRetractRequest returns a decoder for requests sent to the aspect
+// retract endpoint.
+func DecodeRetractRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &aspect.RetractPayload{ID: q.Path("id"), JWT: q.JWT()}
+		return p, q.Err()
+	}
+}
+
+// EncodeRetractResponse returns an encoder for responses returned by the
+// aspect retract endpoint.
+func EncodeRetractResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusNoContent, nil)
+}
@@ -0,0 +1,54 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"net/http"
+
+	dashboard "github.com/ivcap-works/ivcap-core-api/gen/dashboard"
+	client "github.com/ivcap-works/ivcap-core-api/http/dashboard"
+	"github.com/ivcap-works/ivcap-core-api/http/internal/wire"
+	goahttp "goa.design/goa/v3/http"
+	goa "goa.design/goa/v3/pkg"
+)
+
+// DecodeListRequest returns a decoder for requests sent to the dashboard list
+// endpoint.
+func DecodeListRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &dashboard.ListPayload{
+			Limit:     q.Limit("limit"),
+			Page:      q.Query("page"),
+			Filter:    q.Query("filter"),
+			OrderBy:   q.Query("order-by"),
+			OrderDesc: q.QueryBoolDefault("order-desc", true),
+			AtTime:    q.Query("at-time"),
+			JWT:       q.JWT(),
+		}
+		if p.AtTime != nil {
+			err := goa.ValidateFormat("at-time", *p.AtTime, goa.FormatDateTime)
+			return p, goa.MergeErrors(q.Err(), err)
+		}
+		return p, q.Err()
+	}
+}
+
+// EncodeListResponse returns an encoder for responses returned by the
+// dashboard list endpoint.
+func EncodeListResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusOK, (*client.ListResponseBody)(nil))
+}
@@ -0,0 +1,258 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wire
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/ivcap-works/ivcap-core-api/ivcaperr"
+	goahttp "goa.design/goa/v3/http"
+	goa "goa.design/goa/v3/pkg"
+)
+
+// Body returns the value to encode as response body for the result v. The
+// members of the encoded objects are named after the JSON names of the
+// matching fields of shape, a nil pointer to the client response body type.
+// Required fields of v are always present, optional ones only when set.
+// Viewed results are encoded by their projected view.
+func Body(v, shape any) any {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() == reflect.Struct {
+		if p, view := rv.FieldByName("Projected"), rv.FieldByName("View"); p.IsValid() && view.Kind() == reflect.String {
+			rv = p
+		}
+	}
+	return body(rv, reflect.TypeOf(shape))
+}
+
+func body(v reflect.Value, shape reflect.Type) any {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	for shape != nil && shape.Kind() == reflect.Ptr {
+		shape = shape.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return v.Interface()
+	case reflect.Struct:
+		if shape == nil || shape.Kind() != reflect.Struct {
+			return v.Interface()
+		}
+		obj := map[string]any{}
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			sf, ok := shape.FieldByName(f.Name)
+			if !ok || !f.IsExported() {
+				continue
+			}
+			fv := v.Field(i)
+			switch fv.Kind() {
+			case reflect.Ptr, reflect.Interface, reflect.Map:
+				if fv.IsNil() {
+					continue
+				}
+			}
+			obj[jsonName(sf)] = body(fv, sf.Type)
+		}
+		return obj
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Interface()
+		}
+		var elem reflect.Type
+		if shape != nil && shape.Kind() == reflect.Slice {
+			elem = shape.Elem()
+		}
+		arr := make([]any, v.Len())
+		for i := range arr {
+			arr[i] = body(v.Index(i), elem)
+		}
+		return arr
+	case reflect.Map:
+		var elem reflect.Type
+		if shape != nil && shape.Kind() == reflect.Map {
+			elem = shape.Elem()
+		}
+		obj := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			obj[iter.Key().String()] = body(iter.Value(), elem)
+		}
+		return obj
+	}
+	return v.Interface()
+}
+
+// jsonName returns the JSON name of field f.
+func jsonName(f reflect.StructField) string {
+	if name, _, _ := strings.Cut(f.Tag.Get("json"), ","); name != "" {
+		return name
+	}
+	return f.Name
+}
+
+// Decode decodes the body of r with decoder into shape, a pointer to the
+// client request body type, and copies the result into dst, a pointer to the
+// matching payload type.
+func Decode(r *http.Request, decoder func(*http.Request) goahttp.Decoder, shape, dst any) error {
+	if err := decoder(r).Decode(shape); err != nil {
+		if errors.Is(err, io.EOF) {
+			return goa.MissingPayloadError()
+		}
+		return goa.DecodePayloadError(err.Error())
+	}
+	Copy(dst, shape)
+	return nil
+}
+
+// Copy copies the fields of src into the fields of the same names of dst, a
+// pointer, converting between pointer and value fields as needed.
+func Copy(dst, src any) {
+	copyValue(reflect.ValueOf(dst).Elem(), reflect.ValueOf(src))
+}
+
+func copyValue(dst, src reflect.Value) {
+	for src.Kind() == reflect.Ptr || src.Kind() == reflect.Interface {
+		if src.IsNil() {
+			return
+		}
+		if src.Kind() == reflect.Interface && dst.Kind() == reflect.Interface {
+			dst.Set(src)
+			return
+		}
+		src = src.Elem()
+	}
+	switch dst.Kind() {
+	case reflect.Ptr:
+		v := reflect.New(dst.Type().Elem())
+		copyValue(v.Elem(), src)
+		dst.Set(v)
+	case reflect.Interface:
+		dst.Set(src)
+	case reflect.Struct:
+		if src.Kind() != reflect.Struct {
+			return
+		}
+		t := dst.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+			if sv := src.FieldByName(t.Field(i).Name); sv.IsValid() {
+				copyValue(dst.Field(i), sv)
+			}
+		}
+	case reflect.Slice:
+		if src.Kind() != reflect.Slice || src.IsNil() {
+			return
+		}
+		s := reflect.MakeSlice(dst.Type(), src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			copyValue(s.Index(i), src.Index(i))
+		}
+		dst.Set(s)
+	case reflect.Map:
+		if src.Kind() != reflect.Map || src.IsNil() {
+			return
+		}
+		m := reflect.MakeMapWithSize(dst.Type(), src.Len())
+		iter := src.MapRange()
+		for iter.Next() {
+			v := reflect.New(dst.Type().Elem()).Elem()
+			copyValue(v, iter.Value())
+			m.SetMapIndex(iter.Key().Convert(dst.Type().Key()), v)
+		}
+		dst.Set(m)
+	default:
+		if src.Type().ConvertibleTo(dst.Type()) {
+			dst.Set(src.Convert(dst.Type()))
+		}
+	}
+}
+
+// Encode returns a response encoder writing the result with the given status
+// and a body built by Body with shape. A nil shape encodes no body.
+func Encode(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder, status int, shape any) func(context.Context, http.ResponseWriter, any) error {
+	return func(ctx context.Context, w http.ResponseWriter, v any) error {
+		if shape == nil {
+			w.WriteHeader(status)
+			return nil
+		}
+		enc := encoder(ctx, w)
+		w.WriteHeader(status)
+		return enc.Encode(Body(v, shape))
+	}
+}
+
+// ErrorEncoder returns the encoder of the errors of a service. The errors
+// declared by the service are written with their documented status and a body
+// holding their fields, others are formatted with formatter.
+func ErrorEncoder(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder, formatter func(context.Context, error) goahttp.Statuser) func(context.Context, http.ResponseWriter, error) error {
+	fallback := goahttp.ErrorEncoder(encoder, formatter)
+	return func(ctx context.Context, w http.ResponseWriter, err error) error {
+		var named goa.GoaErrorNamer
+		var se *goa.ServiceError
+		if !errors.As(err, &named) || errors.As(err, &se) {
+			return fallback(ctx, w, err)
+		}
+		enc := encoder(ctx, w)
+		w.Header().Set("goa-error", named.GoaErrorName())
+		w.WriteHeader(ivcaperr.HTTPStatus(named.(error)))
+		if b := errorBody(named); b != nil {
+			return enc.Encode(b)
+		}
+		return nil
+	}
+}
+
+// errorBody returns the body of a declared error: its fields under their
+// lower case names, or nil if it has none.
+func errorBody(err any) map[string]any {
+	v := reflect.ValueOf(err)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct || v.NumField() == 0 {
+		return nil
+	}
+	b := map[string]any{}
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Field(i)
+		if f.Kind() == reflect.Ptr && f.IsNil() {
+			continue
+		}
+		b[strings.ToLower(v.Type().Field(i).Name)] = body(f, nil)
+	}
+	return b
+}
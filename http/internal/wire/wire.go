@@ -0,0 +1,236 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package wire holds the helpers shared by the HTTP servers of the services.
+// The servers speak the wire format of the clients in http/*: response and
+// request bodies are encoded with the JSON names of the client body types.
+package wire
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	goahttp "goa.design/goa/v3/http"
+	goa "goa.design/goa/v3/pkg"
+)
+
+// MountPoint holds information about a mounted endpoint.
+type MountPoint struct {
+	// Method is the name of the service method served by the mounted HTTP
+	// handler.
+	Method string
+	// Verb is the HTTP method used to match requests to the mounted handler.
+	Verb string
+	// Pattern is the HTTP request path pattern used to match requests to the
+	// mounted handler.
+	Pattern string
+}
+
+// Handler returns the HTTP handler of method of service svc. It decodes the
+// request with decode, calls endpoint and encodes the result with encode.
+// Errors are encoded with encodeError, falling back to errhandler if that
+// fails too.
+func Handler(
+	svc, method string,
+	endpoint goa.Endpoint,
+	decode func(*http.Request) (any, error),
+	encode func(context.Context, http.ResponseWriter, any) error,
+	encodeError func(context.Context, http.ResponseWriter, error) error,
+	errhandler func(context.Context, http.ResponseWriter, error),
+) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), goahttp.AcceptTypeKey, r.Header.Get("Accept"))
+		ctx = context.WithValue(ctx, goa.MethodKey, method)
+		ctx = context.WithValue(ctx, goa.ServiceKey, svc)
+		payload, err := decode(r)
+		if err == nil {
+			var res any
+			if res, err = endpoint(ctx, payload); err == nil {
+				if err := encode(ctx, w, res); err != nil {
+					errhandler(ctx, w, err)
+				}
+				return
+			}
+		}
+		if err := encodeError(ctx, w, err); err != nil {
+			errhandler(ctx, w, err)
+		}
+	})
+}
+
+// Params decodes the path, query and header parameters of a request,
+// collecting the errors until Err is called.
+type Params struct {
+	r    *http.Request
+	vars map[string]string
+	q    map[string][]string
+	err  error
+}
+
+// NewParams returns the parameters of r, with the path parameters captured by
+// mux.
+func NewParams(mux goahttp.Muxer, r *http.Request) *Params {
+	return &Params{r: r, vars: mux.Vars(r), q: r.URL.Query()}
+}
+
+// Err returns the errors met decoding the parameters.
+func (p *Params) Err() error {
+	return p.err
+}
+
+// Path returns the path parameter name.
+func (p *Params) Path(name string) string {
+	return p.vars[name]
+}
+
+// JWT returns the token of the required Authorization header, without its
+// scheme.
+func (p *Params) JWT() string {
+	jwt := p.r.Header.Get("Authorization")
+	if jwt == "" {
+		p.err = goa.MergeErrors(p.err, goa.MissingFieldError("Authorization", "header"))
+		return ""
+	}
+	if strings.Contains(jwt, " ") {
+		jwt = strings.SplitN(jwt, " ", 2)[1]
+	}
+	return jwt
+}
+
+// Query returns the optional query parameter name.
+func (p *Params) Query(name string) *string {
+	if v, ok := p.q[name]; ok && len(v) > 0 {
+		return &v[0]
+	}
+	return nil
+}
+
+// QueryString returns the query parameter name, or def if it is missing.
+func (p *Params) QueryString(name, def string) string {
+	if v := p.Query(name); v != nil {
+		return *v
+	}
+	return def
+}
+
+// RequiredQuery returns the query parameter name, which must be set.
+func (p *Params) RequiredQuery(name string) string {
+	v := p.Query(name)
+	if v == nil {
+		p.err = goa.MergeErrors(p.err, goa.MissingFieldError(name, "query string"))
+		return ""
+	}
+	return *v
+}
+
+// QueryInt returns the optional integer query parameter name.
+func (p *Params) QueryInt(name string) *int {
+	v := p.Query(name)
+	if v == nil {
+		return nil
+	}
+	i, err := strconv.Atoi(*v)
+	if err != nil {
+		p.err = goa.MergeErrors(p.err, goa.InvalidFieldTypeError(name, *v, "integer"))
+		return nil
+	}
+	return &i
+}
+
+// QueryInt64 returns the optional 64-bit integer query parameter name.
+func (p *Params) QueryInt64(name string) *int64 {
+	v := p.Query(name)
+	if v == nil {
+		return nil
+	}
+	i, err := strconv.ParseInt(*v, 10, 64)
+	if err != nil {
+		p.err = goa.MergeErrors(p.err, goa.InvalidFieldTypeError(name, *v, "integer"))
+		return nil
+	}
+	return &i
+}
+
+// Limit returns the page size query parameter name, which defaults to 10 and
+// must be between 1 and 50.
+func (p *Params) Limit(name string) int {
+	v := p.QueryInt(name)
+	if v == nil {
+		return 10
+	}
+	if *v < 1 {
+		p.err = goa.MergeErrors(p.err, goa.InvalidRangeError(name, *v, 1, true))
+	}
+	if *v > 50 {
+		p.err = goa.MergeErrors(p.err, goa.InvalidRangeError(name, *v, 50, false))
+	}
+	return *v
+}
+
+// QueryBool returns the optional boolean query parameter name.
+func (p *Params) QueryBool(name string) *bool {
+	v := p.Query(name)
+	if v == nil {
+		return nil
+	}
+	b, err := strconv.ParseBool(*v)
+	if err != nil {
+		p.err = goa.MergeErrors(p.err, goa.InvalidFieldTypeError(name, *v, "boolean"))
+		return nil
+	}
+	return &b
+}
+
+// QueryBoolDefault returns the boolean query parameter name, or def if it is
+// missing.
+func (p *Params) QueryBoolDefault(name string, def bool) bool {
+	if v := p.QueryBool(name); v != nil {
+		return *v
+	}
+	return def
+}
+
+// Header returns the optional header name.
+func (p *Params) Header(name string) *string {
+	if v := p.r.Header.Get(name); v != "" {
+		return &v
+	}
+	return nil
+}
+
+// RequiredHeader returns the header name, which must be set.
+func (p *Params) RequiredHeader(name string) string {
+	v := p.Header(name)
+	if v == nil {
+		p.err = goa.MergeErrors(p.err, goa.MissingFieldError(name, "header"))
+		return ""
+	}
+	return *v
+}
+
+// HeaderInt returns the optional integer header name.
+func (p *Params) HeaderInt(name string) *int {
+	v := p.Header(name)
+	if v == nil {
+		return nil
+	}
+	i, err := strconv.Atoi(*v)
+	if err != nil {
+		p.err = goa.MergeErrors(p.err, goa.InvalidFieldTypeError(name, *v, "integer"))
+		return nil
+	}
+	return &i
+}
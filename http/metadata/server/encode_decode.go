@@ -0,0 +1,148 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"net/http"
+
+	metadata "github.com/ivcap-works/ivcap-core-api/gen/metadata"
+	"github.com/ivcap-works/ivcap-core-api/http/internal/wire"
+	client "github.com/ivcap-works/ivcap-core-api/http/metadata"
+	goahttp "goa.design/goa/v3/http"
+	goa "goa.design/goa/v3/pkg"
+)
+
+// DecodeReadRequest returns a decoder for requests sent to the metadata read
+// endpoint.
+func DecodeReadRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &metadata.ReadPayload{ID: q.Path("id"), JWT: q.JWT()}
+		return p, q.Err()
+	}
+}
+
+// EncodeReadResponse returns an encoder for responses returned by the
+// metadata read endpoint.
+func EncodeReadResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusOK, (*client.ReadResponseBody)(nil))
+}
+
+// DecodeListRequest returns a decoder for requests sent to the metadata list
+// endpoint.
+func DecodeListRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &metadata.ListPayload{
+			EntityID:       q.Query("entity-id"),
+			Schema:         q.Query("schema"),
+			AspectPath:     q.Query("aspect-path"),
+			AtTime:         q.Query("at-time"),
+			Limit:          q.Limit("limit"),
+			Filter:         q.QueryString("filter", ""),
+			OrderBy:        q.QueryString("order-by", ""),
+			OrderDesc:      q.QueryBool("order-desc"),
+			IncludeContent: q.QueryBoolDefault("include-content", true),
+			Page:           q.Query("page"),
+			JWT:            q.JWT(),
+		}
+		if p.AtTime != nil {
+			err := goa.ValidateFormat("at-time", *p.AtTime, goa.FormatDateTime)
+			return p, goa.MergeErrors(q.Err(), err)
+		}
+		return p, q.Err()
+	}
+}
+
+// EncodeListResponse returns an encoder for responses returned by the
+// metadata list endpoint.
+func EncodeListResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusOK, (*client.ListResponseBody)(nil))
+}
+
+// DecodeAddRequest returns a decoder for requests sent to the metadata add
+// endpoint.
+func DecodeAddRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &metadata.AddPayload{
+			EntityID:    q.RequiredQuery("entity-id"),
+			Schema:      q.RequiredQuery("schema"),
+			ContentType: q.RequiredHeader("Content-Type"),
+			PolicyID:    q.Query("policy-id"),
+			JWT:         q.JWT(),
+		}
+		if err := q.Err(); err != nil {
+			return nil, err
+		}
+		if err := decoder(r).Decode(&p.Aspect); err != nil {
+			return nil, goa.DecodePayloadError(err.Error())
+		}
+		return p, nil
+	}
+}
+
+// EncodeAddResponse returns an encoder for responses returned by the metadata
+// add endpoint.
+func EncodeAddResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusOK, (*client.AddResponseBody)(nil))
+}
+
+// DecodeUpdateRecordRequest returns a decoder for requests sent to the
+// metadata update_record endpoint.
+func DecodeUpdateRecordRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &metadata.UpdateRecordPayload{
+			ID:          q.Path("id"),
+			EntityID:    q.Query("entity-id"),
+			Schema:      q.Query("schema"),
+			ContentType: q.Header("Content-Type"),
+			PolicyID:    q.Query("policy-id"),
+			JWT:         q.JWT(),
+		}
+		if err := q.Err(); err != nil {
+			return nil, err
+		}
+		if err := decoder(r).Decode(&p.Aspect); err != nil {
+			return nil, goa.DecodePayloadError(err.Error())
+		}
+		return p, nil
+	}
+}
+
+// EncodeUpdateRecordResponse returns an encoder for responses returned by the
+// metadata update_record endpoint.
+func EncodeUpdateRecordResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusOK, (*client.UpdateRecordResponseBody)(nil))
+}
+
+// DecodeRevokeRequest returns a decoder for requests sent to the metadata
+// revoke endpoint.
+func DecodeRevokeRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		id := q.Path("id")
+		p := &metadata.RevokePayload{ID: &id, JWT: q.JWT()}
+		return p, q.Err()
+	}
+}
+
+// EncodeRevokeResponse returns an encoder for responses returned by the
+// metadata revoke endpoint.
+func EncodeRevokeResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusNoContent, nil)
+}
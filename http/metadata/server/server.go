@@ -0,0 +1,99 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package server provides the HTTP server of the metadata service. It speaks
+// the wire format of the client in http/metadata.
+package server
+
+import (
+	"context"
+	"net/http"
+
+	metadata "github.com/ivcap-works/ivcap-core-api/gen/metadata"
+	"github.com/ivcap-works/ivcap-core-api/http/internal/wire"
+	goahttp "goa.design/goa/v3/http"
+)
+
+// Server lists the metadata service endpoint HTTP handlers.
+type Server struct {
+	Mounts       []*MountPoint
+	Read         http.Handler
+	List         http.Handler
+	Add          http.Handler
+	UpdateRecord http.Handler
+	Revoke       http.Handler
+}
+
+// MountPoint holds information about the mounted endpoints.
+type MountPoint = wire.MountPoint
+
+// New instantiates HTTP handlers for all the metadata service endpoints using
+// the provided encoder and decoder. The handlers are mounted on the given mux
+// using the HTTP verb and path defined in openapi3.json. errhandler is called
+// whenever a response fails to be encoded. formatter is used to format errors
+// returned by the service methods prior to encoding, it defaults to
+// goahttp.NewErrorResponse.
+func New(
+	e *metadata.Endpoints,
+	mux goahttp.Muxer,
+	decoder func(*http.Request) goahttp.Decoder,
+	encoder func(context.Context, http.ResponseWriter) goahttp.Encoder,
+	errhandler func(context.Context, http.ResponseWriter, error),
+	formatter func(ctx context.Context, err error) goahttp.Statuser,
+) *Server {
+	encodeError := wire.ErrorEncoder(encoder, formatter)
+	handler := func(method string, ep func(context.Context, any) (any, error), dec func(*http.Request) (any, error), enc func(context.Context, http.ResponseWriter, any) error) http.Handler {
+		return wire.Handler(metadata.ServiceName, method, ep, dec, enc, encodeError, errhandler)
+	}
+	return &Server{
+		Mounts: []*MountPoint{
+			{Method: "Read", Verb: "GET", Pattern: "/1/metadata/{id}"},
+			{Method: "List", Verb: "GET", Pattern: "/1/metadata"},
+			{Method: "Add", Verb: "POST", Pattern: "/1/metadata"},
+			{Method: "UpdateRecord", Verb: "PUT", Pattern: "/1/metadata/{id}"},
+			{Method: "Revoke", Verb: "DELETE", Pattern: "/1/metadata/{id}"},
+		},
+		Read:         handler("read", e.Read, DecodeReadRequest(mux, decoder), EncodeReadResponse(encoder)),
+		List:         handler("list", e.List, DecodeListRequest(mux, decoder), EncodeListResponse(encoder)),
+		Add:          handler("add", e.Add, DecodeAddRequest(mux, decoder), EncodeAddResponse(encoder)),
+		UpdateRecord: handler("update_record", e.UpdateRecord, DecodeUpdateRecordRequest(mux, decoder), EncodeUpdateRecordResponse(encoder)),
+		Revoke:       handler("revoke", e.Revoke, DecodeRevokeRequest(mux, decoder), EncodeRevokeResponse(encoder)),
+	}
+}
+
+// Service returns the name of the service served.
+func (s *Server) Service() string { return "metadata" }
+
+// Use wraps the server handlers with the given middleware.
+func (s *Server) Use(m func(http.Handler) http.Handler) {
+	s.Read = m(s.Read)
+	s.List = m(s.List)
+	s.Add = m(s.Add)
+	s.UpdateRecord = m(s.UpdateRecord)
+	s.Revoke = m(s.Revoke)
+}
+
+// Mount configures the mux to serve the metadata endpoints.
+func Mount(mux goahttp.Muxer, h *Server) {
+	mux.Handle("GET", "/1/metadata/{id}", h.Read.ServeHTTP)
+	mux.Handle("GET", "/1/metadata", h.List.ServeHTTP)
+	mux.Handle("POST", "/1/metadata", h.Add.ServeHTTP)
+	mux.Handle("PUT", "/1/metadata/{id}", h.UpdateRecord.ServeHTTP)
+	mux.Handle("DELETE", "/1/metadata/{id}", h.Revoke.ServeHTTP)
+}
+
+// Mount configures the mux to serve the metadata endpoints.
+func (s *Server) Mount(mux goahttp.Muxer) {
+	Mount(mux, s)
+}
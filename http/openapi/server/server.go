@@ -0,0 +1,65 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package server provides the HTTP server of the openapi service. It serves
+// the OpenAPI document fetched by the client in http/openapi.
+package server
+
+import (
+	"net/http"
+
+	"github.com/ivcap-works/ivcap-core-api/http/internal/wire"
+	goahttp "goa.design/goa/v3/http"
+)
+
+// Server lists the openapi service endpoint HTTP handlers.
+type Server struct {
+	Mounts    []*MountPoint
+	FetchSpec http.Handler
+}
+
+// MountPoint holds information about the mounted endpoints.
+type MountPoint = wire.MountPoint
+
+// New instantiates the HTTP handler serving the OpenAPI document spec, a
+// JSON encoded OpenAPI 3 document.
+func New(spec []byte) *Server {
+	return &Server{
+		Mounts: []*MountPoint{
+			{Method: "FetchSpec", Verb: "GET", Pattern: "/1/openapi/openapi3.json"},
+		},
+		FetchSpec: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(spec)
+		}),
+	}
+}
+
+// Service returns the name of the service served.
+func (s *Server) Service() string { return "openapi" }
+
+// Use wraps the server handlers with the given middleware.
+func (s *Server) Use(m func(http.Handler) http.Handler) {
+	s.FetchSpec = m(s.FetchSpec)
+}
+
+// Mount configures the mux to serve the openapi endpoints.
+func Mount(mux goahttp.Muxer, h *Server) {
+	mux.Handle("GET", "/1/openapi/openapi3.json", h.FetchSpec.ServeHTTP)
+}
+
+// Mount configures the mux to serve the openapi endpoints.
+func (s *Server) Mount(mux goahttp.Muxer) {
+	Mount(mux, s)
+}
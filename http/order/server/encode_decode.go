@@ -0,0 +1,182 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	order "github.com/ivcap-works/ivcap-core-api/gen/order"
+	"github.com/ivcap-works/ivcap-core-api/http/internal/wire"
+	client "github.com/ivcap-works/ivcap-core-api/http/order"
+	goahttp "goa.design/goa/v3/http"
+	goa "goa.design/goa/v3/pkg"
+)
+
+// DecodeListRequest returns a decoder for requests sent to the order list
+// endpoint.
+func DecodeListRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &order.ListPayload{
+			Limit:     q.Limit("limit"),
+			Page:      q.Query("page"),
+			Filter:    q.Query("filter"),
+			OrderBy:   q.Query("order-by"),
+			OrderDesc: q.QueryBoolDefault("order-desc", true),
+			AtTime:    q.Query("at-time"),
+			JWT:       q.JWT(),
+		}
+		if p.AtTime != nil {
+			err := goa.ValidateFormat("at-time", *p.AtTime, goa.FormatDateTime)
+			return p, goa.MergeErrors(q.Err(), err)
+		}
+		return p, q.Err()
+	}
+}
+
+// EncodeListResponse returns an encoder for responses returned by the order
+// list endpoint.
+func EncodeListResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusOK, (*client.ListResponseBody)(nil))
+}
+
+// DecodeReadRequest returns a decoder for requests sent to the order read
+// endpoint.
+func DecodeReadRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &order.ReadPayload{ID: q.Path("id"), JWT: q.JWT()}
+		return p, q.Err()
+	}
+}
+
+// EncodeReadResponse returns an encoder for responses returned by the order
+// read endpoint.
+func EncodeReadResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusOK, (*client.ReadResponseBody)(nil))
+}
+
+// DecodeProductsRequest returns a decoder for requests sent to the order
+// products endpoint.
+func DecodeProductsRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &order.ProductsPayload{
+			OrderID:   q.Path("orderID"),
+			Limit:     q.Limit("limit"),
+			OrderBy:   q.Query("order-by"),
+			OrderDesc: q.QueryBoolDefault("order-desc", true),
+			Page:      q.Query("page"),
+			JWT:       q.JWT(),
+		}
+		return p, q.Err()
+	}
+}
+
+// EncodeProductsResponse returns an encoder for responses returned by the
+// order products endpoint.
+func EncodeProductsResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusOK, (*client.ProductsResponseBody)(nil))
+}
+
+// DecodeMetadataRequest returns a decoder for requests sent to the order
+// metadata endpoint.
+func DecodeMetadataRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &order.MetadataPayload{
+			OrderID:   q.Path("orderID"),
+			Limit:     q.Limit("limit"),
+			OrderBy:   q.Query("order-by"),
+			OrderDesc: q.QueryBoolDefault("order-desc", true),
+			Page:      q.Query("page"),
+			JWT:       q.JWT(),
+		}
+		return p, q.Err()
+	}
+}
+
+// EncodeMetadataResponse returns an encoder for responses returned by the
+// order metadata endpoint.
+func EncodeMetadataResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusOK, (*client.MetadataResponseBody)(nil))
+}
+
+// DecodeCreateRequest returns a decoder for requests sent to the order create
+// endpoint.
+func DecodeCreateRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &order.CreatePayload{JWT: q.JWT()}
+		if err := q.Err(); err != nil {
+			return nil, err
+		}
+		if err := wire.Decode(r, decoder, &client.CreateRequestBody{}, &p.Orders); err != nil {
+			return nil, err
+		}
+		return p, nil
+	}
+}
+
+// EncodeCreateResponse returns an encoder for responses returned by the order
+// create endpoint.
+func EncodeCreateResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusOK, (*client.CreateResponseBody)(nil))
+}
+
+// DecodeLogsRequest returns a decoder for requests sent to the order logs
+// endpoint.
+func DecodeLogsRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &order.LogsPayload{
+			From:    q.QueryInt64("from"),
+			To:      q.QueryInt64("to"),
+			OrderID: q.Path("orderID"),
+			JWT:     q.JWT(),
+		}
+		return p, q.Err()
+	}
+}
+
+// EncodeLogsResponse returns an encoder for responses returned by the order
+// logs endpoint. The logs are streamed as the response body.
+func EncodeLogsResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return func(ctx context.Context, w http.ResponseWriter, v any) error {
+		res := v.(*order.LogsResponseData)
+		defer res.Body.Close()
+		w.WriteHeader(http.StatusOK)
+		_, err := io.Copy(w, res.Body)
+		return err
+	}
+}
+
+// DecodeTopRequest returns a decoder for requests sent to the order top
+// endpoint.
+func DecodeTopRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &order.TopPayload{OrderID: q.Path("orderID"), JWT: q.JWT()}
+		return p, q.Err()
+	}
+}
+
+// EncodeTopResponse returns an encoder for responses returned by the order top
+// endpoint.
+func EncodeTopResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusOK, (client.TopResponseBody)(nil))
+}
@@ -0,0 +1,142 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+
+	package_ "github.com/ivcap-works/ivcap-core-api/gen/package_"
+	"github.com/ivcap-works/ivcap-core-api/http/internal/wire"
+	client "github.com/ivcap-works/ivcap-core-api/http/package_"
+	goahttp "goa.design/goa/v3/http"
+)
+
+// DecodeListRequest returns a decoder for requests sent to the package list
+// endpoint.
+func DecodeListRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &package_.ListPayload{
+			Tag:   q.Query("tag"),
+			Limit: q.QueryInt("limit"),
+			Page:  q.Query("page"),
+			JWT:   q.JWT(),
+		}
+		return p, q.Err()
+	}
+}
+
+// EncodeListResponse returns an encoder for responses returned by the package
+// list endpoint.
+func EncodeListResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusOK, (*client.ListResponseBody)(nil))
+}
+
+// DecodePullRequest returns a decoder for requests sent to the package pull
+// endpoint.
+func DecodePullRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &package_.PullPayload{
+			Ref:    q.RequiredQuery("ref"),
+			Type:   q.RequiredQuery("type"),
+			Offset: q.QueryInt("offset"),
+			JWT:    q.JWT(),
+		}
+		return p, q.Err()
+	}
+}
+
+// EncodePullResponse returns an encoder for responses returned by the package
+// pull endpoint. The sizes are sent as headers and the content is streamed as
+// the response body.
+func EncodePullResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return func(ctx context.Context, w http.ResponseWriter, v any) error {
+		res := v.(*package_.PullResponseData)
+		defer res.Body.Close()
+		w.Header().Set("Total", strconv.Itoa(res.Result.Total))
+		w.Header().Set("Available", strconv.Itoa(res.Result.Available))
+		w.WriteHeader(http.StatusOK)
+		_, err := io.Copy(w, res.Body)
+		return err
+	}
+}
+
+// DecodePushRequest returns a decoder for requests sent to the package push
+// endpoint. The request body is passed on unread to the service.
+func DecodePushRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &package_.PushPayload{
+			Tag:    q.RequiredQuery("tag"),
+			Force:  q.QueryBool("force"),
+			Type:   q.RequiredQuery("type"),
+			Digest: q.RequiredQuery("digest"),
+			Start:  q.QueryInt("start"),
+			End:    q.QueryInt("end"),
+			Total:  q.QueryInt("total"),
+			JWT:    q.JWT(),
+		}
+		if err := q.Err(); err != nil {
+			return nil, err
+		}
+		return &package_.PushRequestData{Payload: p, Body: r.Body}, nil
+	}
+}
+
+// EncodePushResponse returns an encoder for responses returned by the package
+// push endpoint.
+func EncodePushResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusCreated, (*client.PushResponseBody)(nil))
+}
+
+// DecodeStatusRequest returns a decoder for requests sent to the package
+// status endpoint.
+func DecodeStatusRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &package_.StatusPayload{
+			Tag:    q.RequiredQuery("tag"),
+			Digest: q.RequiredQuery("digest"),
+			JWT:    q.JWT(),
+		}
+		return p, q.Err()
+	}
+}
+
+// EncodeStatusResponse returns an encoder for responses returned by the
+// package status endpoint.
+func EncodeStatusResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusOK, (*client.StatusResponseBody)(nil))
+}
+
+// DecodeRemoveRequest returns a decoder for requests sent to the package
+// remove endpoint.
+func DecodeRemoveRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &package_.RemovePayload{Tag: q.RequiredQuery("tag"), JWT: q.JWT()}
+		return p, q.Err()
+	}
+}
+
+// EncodeRemoveResponse returns an encoder for responses returned by the
+// package remove endpoint.
+func EncodeRemoveResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusNoContent, nil)
+}
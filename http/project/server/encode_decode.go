@@ -0,0 +1,252 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"net/http"
+
+	project "github.com/ivcap-works/ivcap-core-api/gen/project"
+	"github.com/ivcap-works/ivcap-core-api/http/internal/wire"
+	client "github.com/ivcap-works/ivcap-core-api/http/project"
+	goahttp "goa.design/goa/v3/http"
+	goa "goa.design/goa/v3/pkg"
+)
+
+// DecodeListRequest returns a decoder for requests sent to the project list
+// endpoint.
+func DecodeListRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &project.ListPayload{
+			Limit:     q.Limit("limit"),
+			Page:      q.Query("page"),
+			Filter:    q.Query("filter"),
+			OrderBy:   q.Query("order-by"),
+			OrderDesc: q.QueryBoolDefault("order-desc", true),
+			AtTime:    q.Query("at-time"),
+			JWT:       q.JWT(),
+		}
+		if p.AtTime != nil {
+			err := goa.ValidateFormat("at-time", *p.AtTime, goa.FormatDateTime)
+			return p, goa.MergeErrors(q.Err(), err)
+		}
+		return p, q.Err()
+	}
+}
+
+// EncodeListResponse returns an encoder for responses returned by the project
+// list endpoint.
+func EncodeListResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusOK, (*client.ListResponseBody)(nil))
+}
+
+// DecodeCreateProjectRequest returns a decoder for requests sent to the
+// project CreateProject endpoint.
+func DecodeCreateProjectRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &project.CreateProjectPayload{JWT: q.JWT()}
+		if err := q.Err(); err != nil {
+			return nil, err
+		}
+		if err := wire.Decode(r, decoder, &client.CreateProjectRequestBody{}, &p.Project); err != nil {
+			return nil, err
+		}
+		return p, nil
+	}
+}
+
+// EncodeCreateProjectResponse returns an encoder for responses returned by the
+// project CreateProject endpoint.
+func EncodeCreateProjectResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusOK, (*client.CreateProjectResponseBody)(nil))
+}
+
+// DecodeDeleteRequest returns a decoder for requests sent to the project
+// delete endpoint.
+func DecodeDeleteRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &project.DeletePayload{ID: q.Path("id"), JWT: q.JWT()}
+		return p, q.Err()
+	}
+}
+
+// EncodeDeleteResponse returns an encoder for responses returned by the
+// project delete endpoint.
+func EncodeDeleteResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusNoContent, nil)
+}
+
+// DecodeReadRequest returns a decoder for requests sent to the project read
+// endpoint.
+func DecodeReadRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &project.ReadPayload{ID: q.Path("id"), JWT: q.JWT()}
+		return p, q.Err()
+	}
+}
+
+// EncodeReadResponse returns an encoder for responses returned by the project
+// read endpoint.
+func EncodeReadResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusOK, (*client.ReadResponseBody)(nil))
+}
+
+// DecodeListProjectMembersRequest returns a decoder for requests sent to the
+// project ListProjectMembers endpoint.
+func DecodeListProjectMembersRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &project.ListProjectMembersPayload{
+			Urn:   q.Path("urn"),
+			Role:  q.Query("role"),
+			Limit: q.Limit("limit"),
+			Page:  q.Query("page"),
+			JWT:   q.JWT(),
+		}
+		return p, q.Err()
+	}
+}
+
+// EncodeListProjectMembersResponse returns an encoder for responses returned
+// by the project ListProjectMembers endpoint.
+func EncodeListProjectMembersResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusOK, (*client.ListProjectMembersResponseBody)(nil))
+}
+
+// DecodeUpdateMembershipRequest returns a decoder for requests sent to the
+// project UpdateMembership endpoint.
+func DecodeUpdateMembershipRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &project.UpdateMembershipPayload{
+			ProjectUrn: q.Path("project_urn"),
+			UserUrn:    q.Path("user_urn"),
+			JWT:        q.JWT(),
+		}
+		if err := q.Err(); err != nil {
+			return nil, err
+		}
+		if err := wire.Decode(r, decoder, &client.UpdateMembershipRequestBody{}, p); err != nil {
+			return nil, err
+		}
+		return p, nil
+	}
+}
+
+// EncodeUpdateMembershipResponse returns an encoder for responses returned by
+// the project UpdateMembership endpoint.
+func EncodeUpdateMembershipResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusNoContent, nil)
+}
+
+// DecodeRemoveMembershipRequest returns a decoder for requests sent to the
+// project RemoveMembership endpoint.
+func DecodeRemoveMembershipRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &project.RemoveMembershipPayload{
+			ProjectUrn: q.Path("project_urn"),
+			UserUrn:    q.Path("user_urn"),
+			JWT:        q.JWT(),
+		}
+		return p, q.Err()
+	}
+}
+
+// EncodeRemoveMembershipResponse returns an encoder for responses returned by
+// the project RemoveMembership endpoint.
+func EncodeRemoveMembershipResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusNoContent, nil)
+}
+
+// DecodeDefaultProjectRequest returns a decoder for requests sent to the
+// project DefaultProject endpoint.
+func DecodeDefaultProjectRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &project.DefaultProjectPayload{JWT: q.JWT()}
+		return p, q.Err()
+	}
+}
+
+// EncodeDefaultProjectResponse returns an encoder for responses returned by
+// the project DefaultProject endpoint.
+func EncodeDefaultProjectResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusOK, (*client.DefaultProjectResponseBody)(nil))
+}
+
+// DecodeSetDefaultProjectRequest returns a decoder for requests sent to the
+// project SetDefaultProject endpoint.
+func DecodeSetDefaultProjectRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &project.SetDefaultProjectPayload{JWT: q.JWT()}
+		if err := q.Err(); err != nil {
+			return nil, err
+		}
+		if err := wire.Decode(r, decoder, &client.SetDefaultProjectRequestBody{}, p); err != nil {
+			return nil, err
+		}
+		return p, nil
+	}
+}
+
+// EncodeSetDefaultProjectResponse returns an encoder for responses returned
+// by the project SetDefaultProject endpoint.
+func EncodeSetDefaultProjectResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusNoContent, nil)
+}
+
+// DecodeProjectAccountRequest returns a decoder for requests sent to the
+// project ProjectAccount endpoint.
+func DecodeProjectAccountRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &project.ProjectAccountPayload{ProjectUrn: q.Path("project_urn"), JWT: q.JWT()}
+		return p, q.Err()
+	}
+}
+
+// EncodeProjectAccountResponse returns an encoder for responses returned by
+// the project ProjectAccount endpoint.
+func EncodeProjectAccountResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusOK, (*client.ProjectAccountResponseBody)(nil))
+}
+
+// DecodeSetProjectAccountRequest returns a decoder for requests sent to the
+// project SetProjectAccount endpoint.
+func DecodeSetProjectAccountRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &project.SetProjectAccountPayload{ProjectUrn: q.Path("project_urn"), JWT: q.JWT()}
+		if err := q.Err(); err != nil {
+			return nil, err
+		}
+		if err := wire.Decode(r, decoder, &client.SetProjectAccountRequestBody{}, p); err != nil {
+			return nil, err
+		}
+		return p, nil
+	}
+}
+
+// EncodeSetProjectAccountResponse returns an encoder for responses returned
+// by the project SetProjectAccount endpoint.
+func EncodeSetProjectAccountResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusNoContent, nil)
+}
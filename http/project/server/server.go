@@ -0,0 +1,129 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package server provides the HTTP server of the project service. It speaks
+// the wire format of the client in http/project.
+package server
+
+import (
+	"context"
+	"net/http"
+
+	project "github.com/ivcap-works/ivcap-core-api/gen/project"
+	"github.com/ivcap-works/ivcap-core-api/http/internal/wire"
+	goahttp "goa.design/goa/v3/http"
+)
+
+// Server lists the project service endpoint HTTP handlers.
+type Server struct {
+	Mounts             []*MountPoint
+	List               http.Handler
+	CreateProject      http.Handler
+	Delete             http.Handler
+	Read               http.Handler
+	ListProjectMembers http.Handler
+	UpdateMembership   http.Handler
+	RemoveMembership   http.Handler
+	DefaultProject     http.Handler
+	SetDefaultProject  http.Handler
+	ProjectAccount     http.Handler
+	SetProjectAccount  http.Handler
+}
+
+// MountPoint holds information about the mounted endpoints.
+type MountPoint = wire.MountPoint
+
+// New instantiates HTTP handlers for all the project service endpoints using
+// the provided encoder and decoder. The handlers are mounted on the given mux
+// using the HTTP verb and path defined in openapi3.json. errhandler is called
+// whenever a response fails to be encoded. formatter is used to format errors
+// returned by the service methods prior to encoding, it defaults to
+// goahttp.NewErrorResponse.
+func New(
+	e *project.Endpoints,
+	mux goahttp.Muxer,
+	decoder func(*http.Request) goahttp.Decoder,
+	encoder func(context.Context, http.ResponseWriter) goahttp.Encoder,
+	errhandler func(context.Context, http.ResponseWriter, error),
+	formatter func(ctx context.Context, err error) goahttp.Statuser,
+) *Server {
+	encodeError := wire.ErrorEncoder(encoder, formatter)
+	handler := func(method string, ep func(context.Context, any) (any, error), dec func(*http.Request) (any, error), enc func(context.Context, http.ResponseWriter, any) error) http.Handler {
+		return wire.Handler(project.ServiceName, method, ep, dec, enc, encodeError, errhandler)
+	}
+	return &Server{
+		Mounts: []*MountPoint{
+			{Method: "List", Verb: "GET", Pattern: "/1/project"},
+			{Method: "CreateProject", Verb: "POST", Pattern: "/1/project"},
+			{Method: "Delete", Verb: "DELETE", Pattern: "/1/project/{id}"},
+			{Method: "Read", Verb: "GET", Pattern: "/1/project/{id}"},
+			{Method: "ListProjectMembers", Verb: "GET", Pattern: "/1/project/{urn}/members"},
+			{Method: "UpdateMembership", Verb: "PUT", Pattern: "/1/project/{project_urn}/memberships/{user_urn}"},
+			{Method: "RemoveMembership", Verb: "DELETE", Pattern: "/1/project/{project_urn}/memberships/{user_urn}"},
+			{Method: "DefaultProject", Verb: "GET", Pattern: "/1/project/default"},
+			{Method: "SetDefaultProject", Verb: "PUT", Pattern: "/1/project/default"},
+			{Method: "ProjectAccount", Verb: "GET", Pattern: "/1/project/{project_urn}/account"},
+			{Method: "SetProjectAccount", Verb: "PUT", Pattern: "/1/project/{project_urn}/account"},
+		},
+		List:               handler("list", e.List, DecodeListRequest(mux, decoder), EncodeListResponse(encoder)),
+		CreateProject:      handler("CreateProject", e.CreateProject, DecodeCreateProjectRequest(mux, decoder), EncodeCreateProjectResponse(encoder)),
+		Delete:             handler("delete", e.Delete, DecodeDeleteRequest(mux, decoder), EncodeDeleteResponse(encoder)),
+		Read:               handler("read", e.Read, DecodeReadRequest(mux, decoder), EncodeReadResponse(encoder)),
+		ListProjectMembers: handler("ListProjectMembers", e.ListProjectMembers, DecodeListProjectMembersRequest(mux, decoder), EncodeListProjectMembersResponse(encoder)),
+		UpdateMembership:   handler("UpdateMembership", e.UpdateMembership, DecodeUpdateMembershipRequest(mux, decoder), EncodeUpdateMembershipResponse(encoder)),
+		RemoveMembership:   handler("RemoveMembership", e.RemoveMembership, DecodeRemoveMembershipRequest(mux, decoder), EncodeRemoveMembershipResponse(encoder)),
+		DefaultProject:     handler("DefaultProject", e.DefaultProject, DecodeDefaultProjectRequest(mux, decoder), EncodeDefaultProjectResponse(encoder)),
+		SetDefaultProject:  handler("SetDefaultProject", e.SetDefaultProject, DecodeSetDefaultProjectRequest(mux, decoder), EncodeSetDefaultProjectResponse(encoder)),
+		ProjectAccount:     handler("ProjectAccount", e.ProjectAccount, DecodeProjectAccountRequest(mux, decoder), EncodeProjectAccountResponse(encoder)),
+		SetProjectAccount:  handler("SetProjectAccount", e.SetProjectAccount, DecodeSetProjectAccountRequest(mux, decoder), EncodeSetProjectAccountResponse(encoder)),
+	}
+}
+
+// Service returns the name of the service served.
+func (s *Server) Service() string { return "project" }
+
+// Use wraps the server handlers with the given middleware.
+func (s *Server) Use(m func(http.Handler) http.Handler) {
+	s.List = m(s.List)
+	s.CreateProject = m(s.CreateProject)
+	s.Delete = m(s.Delete)
+	s.Read = m(s.Read)
+	s.ListProjectMembers = m(s.ListProjectMembers)
+	s.UpdateMembership = m(s.UpdateMembership)
+	s.RemoveMembership = m(s.RemoveMembership)
+	s.DefaultProject = m(s.DefaultProject)
+	s.SetDefaultProject = m(s.SetDefaultProject)
+	s.ProjectAccount = m(s.ProjectAccount)
+	s.SetProjectAccount = m(s.SetProjectAccount)
+}
+
+// Mount configures the mux to serve the project endpoints.
+func Mount(mux goahttp.Muxer, h *Server) {
+	mux.Handle("GET", "/1/project", h.List.ServeHTTP)
+	mux.Handle("POST", "/1/project", h.CreateProject.ServeHTTP)
+	mux.Handle("DELETE", "/1/project/{id}", h.Delete.ServeHTTP)
+	mux.Handle("GET", "/1/project/{id}", h.Read.ServeHTTP)
+	mux.Handle("GET", "/1/project/{urn}/members", h.ListProjectMembers.ServeHTTP)
+	mux.Handle("PUT", "/1/project/{project_urn}/memberships/{user_urn}", h.UpdateMembership.ServeHTTP)
+	mux.Handle("DELETE", "/1/project/{project_urn}/memberships/{user_urn}", h.RemoveMembership.ServeHTTP)
+	mux.Handle("GET", "/1/project/default", h.DefaultProject.ServeHTTP)
+	mux.Handle("PUT", "/1/project/default", h.SetDefaultProject.ServeHTTP)
+	mux.Handle("GET", "/1/project/{project_urn}/account", h.ProjectAccount.ServeHTTP)
+	mux.Handle("PUT", "/1/project/{project_urn}/account", h.SetProjectAccount.ServeHTTP)
+}
+
+// Mount configures the mux to serve the project endpoints.
+func (s *Server) Mount(mux goahttp.Muxer) {
+	Mount(mux, s)
+}
@@ -0,0 +1,155 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"net/http"
+
+	queue "github.com/ivcap-works/ivcap-core-api/gen/queue"
+	"github.com/ivcap-works/ivcap-core-api/http/internal/wire"
+	client "github.com/ivcap-works/ivcap-core-api/http/queue"
+	goahttp "goa.design/goa/v3/http"
+	goa "goa.design/goa/v3/pkg"
+)
+
+// DecodeCreateRequest returns a decoder for requests sent to the queue create
+// endpoint.
+func DecodeCreateRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &queue.CreatePayload{JWT: q.JWT()}
+		if err := q.Err(); err != nil {
+			return nil, err
+		}
+		if err := wire.Decode(r, decoder, &client.CreateRequestBody{}, &p.Queues); err != nil {
+			return nil, err
+		}
+		return p, nil
+	}
+}
+
+// EncodeCreateResponse returns an encoder for responses returned by the queue
+// create endpoint.
+func EncodeCreateResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusCreated, (*client.CreateResponseBody)(nil))
+}
+
+// DecodeReadRequest returns a decoder for requests sent to the queue read
+// endpoint.
+func DecodeReadRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &queue.ReadPayload{ID: q.Path("id"), JWT: q.JWT()}
+		return p, q.Err()
+	}
+}
+
+// EncodeReadResponse returns an encoder for responses returned by the queue
+// read endpoint.
+func EncodeReadResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusOK, (*client.ReadResponseBody)(nil))
+}
+
+// DecodeDeleteRequest returns a decoder for requests sent to the queue delete
+// endpoint.
+func DecodeDeleteRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &queue.DeletePayload{ID: q.Path("id"), JWT: q.JWT()}
+		return p, q.Err()
+	}
+}
+
+// EncodeDeleteResponse returns an encoder for responses returned by the queue
+// delete endpoint.
+func EncodeDeleteResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusNoContent, nil)
+}
+
+// DecodeListRequest returns a decoder for requests sent to the queue list
+// endpoint.
+func DecodeListRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &queue.ListPayload{
+			Limit:     q.Limit("limit"),
+			Page:      q.Query("page"),
+			Filter:    q.Query("filter"),
+			OrderBy:   q.Query("order-by"),
+			OrderDesc: q.QueryBoolDefault("order-desc", true),
+			AtTime:    q.Query("at-time"),
+			JWT:       q.JWT(),
+		}
+		if p.AtTime != nil {
+			err := goa.ValidateFormat("at-time", *p.AtTime, goa.FormatDateTime)
+			return p, goa.MergeErrors(q.Err(), err)
+		}
+		return p, q.Err()
+	}
+}
+
+// EncodeListResponse returns an encoder for responses returned by the queue
+// list endpoint.
+func EncodeListResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusOK, (*client.ListResponseBody)(nil))
+}
+
+// DecodeEnqueueRequest returns a decoder for requests sent to the queue
+// enqueue endpoint. The request body is the message content.
+func DecodeEnqueueRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &queue.EnqueuePayload{
+			ID:          q.Path("id"),
+			ContentType: q.Header("Content-Type"),
+			Schema:      q.Query("schema"),
+			JWT:         q.JWT(),
+		}
+		if err := q.Err(); err != nil {
+			return nil, err
+		}
+		if err := decoder(r).Decode(&p.Content); err != nil {
+			return nil, goa.DecodePayloadError(err.Error())
+		}
+		return p, nil
+	}
+}
+
+// EncodeEnqueueResponse returns an encoder for responses returned by the
+// queue enqueue endpoint.
+func EncodeEnqueueResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusOK, (*client.EnqueueResponseBody)(nil))
+}
+
+// DecodeDequeueRequest returns a decoder for requests sent to the queue
+// dequeue endpoint.
+func DecodeDequeueRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &queue.DequeuePayload{
+			ID:    q.Path("id"),
+			Limit: q.QueryInt("limit"),
+			JWT:   q.JWT(),
+		}
+		return p, q.Err()
+	}
+}
+
+// EncodeDequeueResponse returns an encoder for responses returned by the
+// queue dequeue endpoint.
+func EncodeDequeueResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusOK, (*client.DequeueResponseBody)(nil))
+}
@@ -0,0 +1,61 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"net/http"
+
+	search "github.com/ivcap-works/ivcap-core-api/gen/search"
+	"github.com/ivcap-works/ivcap-core-api/http/internal/wire"
+	client "github.com/ivcap-works/ivcap-core-api/http/search"
+	goahttp "goa.design/goa/v3/http"
+	goa "goa.design/goa/v3/pkg"
+)
+
+// DecodeSearchRequest returns a decoder for requests sent to the search
+// search endpoint. The request body is the query. The client formats a
+// missing page token as "<nil>", which is decoded as no page token.
+func DecodeSearchRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &search.SearchPayload{
+			ContentType: q.RequiredHeader("Content-Type"),
+			AtTime:      q.Query("at-time"),
+			Limit:       q.Limit("limit"),
+			JWT:         q.JWT(),
+		}
+		if page := q.Query("page"); page != nil && *page != "<nil>" {
+			p.Page = *page
+		}
+		err := q.Err()
+		if p.AtTime != nil {
+			err = goa.MergeErrors(err, goa.ValidateFormat("at-time", *p.AtTime, goa.FormatDateTime))
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := decoder(r).Decode(&p.Query); err != nil {
+			return nil, goa.DecodePayloadError(err.Error())
+		}
+		return p, nil
+	}
+}
+
+// EncodeSearchResponse returns an encoder for responses returned by the
+// search search endpoint.
+func EncodeSearchResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusOK, (*client.SearchResponseBody)(nil))
+}
@@ -0,0 +1,89 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"net/http"
+
+	secret "github.com/ivcap-works/ivcap-core-api/gen/secret"
+	"github.com/ivcap-works/ivcap-core-api/http/internal/wire"
+	client "github.com/ivcap-works/ivcap-core-api/http/secret"
+	goahttp "goa.design/goa/v3/http"
+)
+
+// DecodeListRequest returns a decoder for requests sent to the secret list
+// endpoint.
+func DecodeListRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &secret.ListPayload{
+			Page:   q.Query("page"),
+			Filter: q.Query("filter"),
+			Limit:  q.QueryInt("limit"),
+			Offset: q.Query("offset"),
+			JWT:    q.JWT(),
+		}
+		return p, q.Err()
+	}
+}
+
+// EncodeListResponse returns an encoder for responses returned by the secret
+// list endpoint.
+func EncodeListResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusOK, (*client.ListResponseBody)(nil))
+}
+
+// DecodeGetRequest returns a decoder for requests sent to the secret get
+// endpoint.
+func DecodeGetRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &secret.GetPayload{
+			SecretName: q.RequiredQuery("secret-name"),
+			SecretType: q.Query("secret-type"),
+			JWT:        q.JWT(),
+		}
+		return p, q.Err()
+	}
+}
+
+// EncodeGetResponse returns an encoder for responses returned by the secret
+// get endpoint.
+func EncodeGetResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusOK, (*client.GetResponseBody)(nil))
+}
+
+// DecodeSetRequest returns a decoder for requests sent to the secret set
+// endpoint.
+func DecodeSetRequest(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) func(*http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		q := wire.NewParams(mux, r)
+		p := &secret.SetPayload{JWT: q.JWT()}
+		if err := q.Err(); err != nil {
+			return nil, err
+		}
+		if err := wire.Decode(r, decoder, &client.SetRequestBody{}, &p.Secrets); err != nil {
+			return nil, err
+		}
+		return p, nil
+	}
+}
+
+// EncodeSetResponse returns an encoder for responses returned by the secret
+// set endpoint.
+func EncodeSetResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, any) error {
+	return wire.Encode(encoder, http.StatusNoContent, nil)
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package server provides the HTTP server of the service service. It speaks
+// the wire format of the client in http/service.
+package server
+
+import (
+	"context"
+	"net/http"
+
+	service "github.com/ivcap-works/ivcap-core-api/gen/service"
+	"github.com/ivcap-works/ivcap-core-api/http/internal/wire"
+	goahttp "goa.design/goa/v3/http"
+)
+
+// Server lists the service service endpoint HTTP handlers.
+type Server struct {
+	Mounts        []*MountPoint
+	List          http.Handler
+	CreateService http.Handler
+	Read          http.Handler
+	Update        http.Handler
+	Delete        http.Handler
+}
+
+// MountPoint holds information about the mounted endpoints.
+type MountPoint = wire.MountPoint
+
+// New instantiates HTTP handlers for all the service service endpoints using
+// the provided encoder and decoder. The handlers are mounted on the given mux
+// using the HTTP verb and path defined in openapi3.json. errhandler is called
+// whenever a response fails to be encoded. formatter is used to format errors
+// returned by the service methods prior to encoding, it defaults to
+// goahttp.NewErrorResponse.
+func New(
+	e *service.Endpoints,
+	mux goahttp.Muxer,
+	decoder func(*http.Request) goahttp.Decoder,
+	encoder func(context.Context, http.ResponseWriter) goahttp.Encoder,
+	errhandler func(context.Context, http.ResponseWriter, error),
+	formatter func(ctx context.Context, err error) goahttp.Statuser,
+) *Server {
+	encodeError := wire.ErrorEncoder(encoder, formatter)
+	handler := func(method string, ep func(context.Context, any) (any, error), dec func(*http.Request) (any, error), enc func(context.Context, http.ResponseWriter, any) error) http.Handler {
+		return wire.Handler(service.ServiceName, method, ep, dec, enc, encodeError, errhandler)
+	}
+	return &Server{
+		Mounts: []*MountPoint{
+			{Method: "List", Verb: "GET", Pattern: "/1/services"},
+			{Method: "CreateService", Verb: "POST", Pattern: "/1/services"},
+			{Method: "Read", Verb: "GET", Pattern: "/1/services/{id}"},
+			{Method: "Update", Verb: "PUT", Pattern: "/1/services/{id}"},
+			{Method: "Delete", Verb: "DELETE", Pattern: "/1/services/{id}"},
+		},
+		List:          handler("list", e.List, DecodeListRequest(mux, decoder), EncodeListResponse(encoder)),
+		CreateService: handler("create_service", e.CreateService, DecodeCreateServiceRequest(mux, decoder), EncodeCreateServiceResponse(encoder)),
+		Read:          handler("read", e.Read, DecodeReadRequest(mux, decoder), EncodeReadResponse(encoder)),
+		Update:        handler("update", e.Update, DecodeUpdateRequest(mux, decoder), EncodeUpdateResponse(encoder)),
+		Delete:        handler("delete", e.Delete, DecodeDeleteRequest(mux, decoder), EncodeDeleteResponse(encoder)),
+	}
+}
+
+// Service returns the name of the service served.
+func (s *Server) Service() string { return "service" }
+
+// Use wraps the server handlers with the given middleware.
+func (s *Server) Use(m func(http.Handler) http.Handler) {
+	s.List = m(s.List)
+	s.CreateService = m(s.CreateService)
+	s.Read = m(s.Read)
+	s.Update = m(s.Update)
+	s.Delete = m(s.Delete)
+}
+
+// Mount configures the mux to serve the service endpoints.
+func Mount(mux goahttp.Muxer, h *Server) {
+	mux.Handle("GET", "/1/services", h.List.ServeHTTP)
+	mux.Handle("POST", "/1/services", h.CreateService.ServeHTTP)
+	mux.Handle("GET", "/1/services/{id}", h.Read.ServeHTTP)
+	mux.Handle("PUT", "/1/services/{id}", h.Update.ServeHTTP)
+	mux.Handle("DELETE", "/1/services/{id}", h.Delete.ServeHTTP)
+}
+
+// Mount configures the mux to serve the service endpoints.
+func (s *Server) Mount(mux goahttp.Muxer) {
+	Mount(mux, s)
+}
@@ -0,0 +1,110 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	artifact "github.com/ivcap-works/ivcap-core-api/gen/artifact"
+	artifactc "github.com/ivcap-works/ivcap-core-api/http/artifact"
+	goahttp "goa.design/goa/v3/http"
+	goa "goa.design/goa/v3/pkg"
+)
+
+// ArtifactsClient gives typed access to the artifact service.
+type ArtifactsClient struct {
+	c      *Client
+	list   goa.Endpoint
+	read   goa.Endpoint
+	upload goa.Endpoint
+
+	tusHead  goa.Endpoint
+	tusPatch goa.Endpoint
+	blob     goa.Endpoint
+}
+
+func newArtifactsClient(c *Client, enc func(*http.Request) goahttp.Encoder, dec func(*http.Response) goahttp.Decoder, restoreBody bool) *ArtifactsClient {
+	hc := artifactc.NewClient(c.scheme, c.host, c.doer, enc, dec, restoreBody)
+	s := &ArtifactsClient{
+		c:      c,
+		list:   c.endpoint(artifact.ServiceName, "list", hc.List()),
+		read:   c.endpoint(artifact.ServiceName, "read", hc.Read()),
+		upload: c.endpoint(artifact.ServiceName, "upload", hc.Upload()),
+	}
+	s.tusHead = c.endpoint(artifact.ServiceName, "tus-head", s.tusHeadEndpoint())
+	s.tusPatch = c.endpoint(artifact.ServiceName, "tus-patch", s.tusPatchEndpoint())
+	s.blob = c.endpoint(artifact.ServiceName, "download", s.blobEndpoint())
+	return s
+}
+
+// List returns a page of artifacts.
+func (s *ArtifactsClient) List(ctx context.Context, p *artifact.ListPayload) (*artifact.ArtifactListRT, error) {
+	var q artifact.ListPayload
+	if p != nil {
+		q = *p
+	}
+	return invoke[*artifact.ArtifactListRT](ctx, s.list, &q)
+}
+
+// Read returns the status of the artifact with the given ID.
+func (s *ArtifactsClient) Read(ctx context.Context, id string) (*artifact.ArtifactStatusRT, error) {
+	p := &artifact.ReadPayload{ID: id}
+	return invoke[*artifact.ArtifactStatusRT](ctx, s.read, p)
+}
+
+// Upload creates a new artifact with the content read from body.
+func (s *ArtifactsClient) Upload(ctx context.Context, p *artifact.UploadPayload, body io.Reader) (*artifact.ArtifactUploadRT, error) {
+	var q artifact.UploadPayload
+	if p != nil {
+		q = *p
+	}
+	rc, ok := body.(io.ReadCloser)
+	if !ok {
+		rc = io.NopCloser(body)
+	}
+	return invoke[*artifact.ArtifactUploadRT](ctx, s.upload, &artifact.UploadRequestData{Payload: &q, Body: rc})
+}
+
+// contentLength returns size as the int the upload payload carries lengths
+// as, failing where it does not fit rather than truncating it.
+func contentLength(size int64) (*int, error) {
+	n := int(size)
+	if int64(n) != size {
+		return nil, fmt.Errorf("ivcap: content length %d too large for this platform", size)
+	}
+	return &n, nil
+}
+
+// ListIter returns an iterator over all artifacts matching p, following the next page links.
+func (s *ArtifactsClient) ListIter(ctx context.Context, p *artifact.ListPayload) *Iterator[*artifact.ArtifactListItem] {
+	var q artifact.ListPayload
+	if p != nil {
+		q = *p
+	}
+	q.Limit = pageLimit(q.Limit)
+	return newIterator(ctx, func(ctx context.Context, page string) ([]*artifact.ArtifactListItem, string, error) {
+		if page != "" {
+			q.Page = &page
+		}
+		res, err := s.List(ctx, &q)
+		if err != nil {
+			return nil, "", err
+		}
+		return res.Items, nextPage(res.Links, func(l *artifact.LinkT) (string, string) { return l.Rel, l.Href }), nil
+	})
+}
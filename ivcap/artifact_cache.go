@@ -0,0 +1,317 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	artifact "github.com/ivcap-works/ivcap-core-api/gen/artifact"
+)
+
+// ArtifactCache is a disk-backed cache of the content of artifacts, keyed by
+// artifact ID and etag. It is safe for concurrent use, and the content of an
+// artifact is only downloaded once at a time. The least recently used
+// content is evicted when the total size exceeds the limit. Content is kept
+// decoded and decrypted, so a cache holding sensitive artifacts belongs on
+// protected storage.
+type ArtifactCache struct {
+	dir     string
+	maxSize int64
+
+	mu       sync.Mutex
+	size     int64
+	lru      *list.List               // of *cacheEntry, most recently used first
+	entries  map[string]*list.Element // by artifact ID
+	inflight map[string]chan struct{} // closed when the download ends
+}
+
+// cacheEntry describes the content of an artifact held by the cache. It is
+// saved next to the content.
+type cacheEntry struct {
+	ID   string `json:"id"`
+	Etag string `json:"etag"`
+	Size int64  `json:"size"`
+}
+
+// NewArtifactCache returns a cache keeping at most maxSize bytes of content
+// in dir, or any amount if maxSize is not positive. The content cached in dir
+// by earlier processes is reused.
+func NewArtifactCache(dir string, maxSize int64) (*ArtifactCache, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("ivcap: creating cache: %w", err)
+	}
+	c := &ArtifactCache{
+		dir:      dir,
+		maxSize:  maxSize,
+		lru:      list.New(),
+		entries:  map[string]*list.Element{},
+		inflight: map[string]chan struct{}{},
+	}
+	metas, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	type found struct {
+		e    *cacheEntry
+		used time.Time
+	}
+	var all []found
+	for _, m := range metas {
+		b, err := os.ReadFile(m)
+		var e cacheEntry
+		if err != nil || json.Unmarshal(b, &e) != nil {
+			continue
+		}
+		fi, err := os.Stat(c.path(e.ID, ".data"))
+		if err != nil || fi.Size() != e.Size {
+			os.Remove(m)
+			continue
+		}
+		all = append(all, found{&e, fi.ModTime()})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].used.After(all[j].used) })
+	for _, f := range all {
+		c.entries[f.e.ID] = c.lru.PushBack(f.e)
+		c.size += f.e.Size
+	}
+	c.mu.Lock()
+	c.evict("")
+	c.mu.Unlock()
+	return c, nil
+}
+
+// WithArtifactCache makes ArtifactsClient.Open read the content of artifacts
+// through cache.
+func WithArtifactCache(cache *ArtifactCache) Option {
+	return func(o *options) {
+		o.cache = cache
+	}
+}
+
+// Size returns the total size of the content held by the cache.
+func (c *ArtifactCache) Size() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size
+}
+
+// Open returns the content of artifact id together with its status. With a
+// cache set by WithArtifactCache, the content is served from the cache if
+// its etag matches the one returned by Read, and downloaded to the cache
+// first otherwise; the result is then an *os.File. Without a cache, the
+// content is streamed as by Download.
+func (s *ArtifactsClient) Open(ctx context.Context, id string) (io.ReadCloser, *artifact.ArtifactStatusRT, error) {
+	st, err := s.Read(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if s.c.cache == nil {
+		k, err := s.contentKey(ctx, st)
+		if err != nil {
+			return nil, nil, err
+		}
+		r, w := io.Pipe()
+		go func() {
+			var enc string
+			pw, finish := plainWriter(w, &enc, k)
+			w.CloseWithError(finish(s.download(ctx, st, pw, 0, newEtagHash(st.Etag), nil, &enc)))
+		}()
+		return r, st, nil
+	}
+	f, err := s.c.cache.open(ctx, s, st)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, st, nil
+}
+
+// open returns the cached content of the artifact with status st, filling
+// the cache first if needed.
+func (c *ArtifactCache) open(ctx context.Context, s *ArtifactsClient, st *artifact.ArtifactStatusRT) (*os.File, error) {
+	etag := stringValue(st.Etag)
+	for {
+		c.mu.Lock()
+		if done, ok := c.inflight[st.ID]; ok {
+			c.mu.Unlock()
+			select {
+			case <-done:
+				continue
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		var stale *cacheEntry
+		if el, ok := c.entries[st.ID]; ok {
+			e := el.Value.(*cacheEntry)
+			if etag != "" && e.Etag == etag {
+				if f, err := c.use(el); err == nil {
+					c.mu.Unlock()
+					return f, nil
+				}
+				// The content was removed, and the entry with it.
+			} else {
+				stale = e
+			}
+		}
+		done := make(chan struct{})
+		c.inflight[st.ID] = done
+		c.mu.Unlock()
+
+		err := c.fill(ctx, s, st, stale)
+		c.mu.Lock()
+		delete(c.inflight, st.ID)
+		close(done)
+		var f *os.File
+		if err == nil {
+			if el, ok := c.entries[st.ID]; ok {
+				f, err = c.use(el)
+			}
+		}
+		c.mu.Unlock()
+		if err != nil || f != nil {
+			return f, err
+		}
+	}
+}
+
+// use opens the content of the entry in el and marks it as the most recently
+// used. The entry is dropped if its content is gone. c.mu must be held.
+func (c *ArtifactCache) use(el *list.Element) (*os.File, error) {
+	e := el.Value.(*cacheEntry)
+	data := c.path(e.ID, ".data")
+	f, err := os.Open(data)
+	if err != nil {
+		c.remove(el)
+		return nil, err
+	}
+	c.lru.MoveToFront(el)
+	now := time.Now()
+	os.Chtimes(data, now, now)
+	return f, nil
+}
+
+// fill downloads the content of the artifact with status st to the cache.
+// A stale entry of the artifact is revalidated with its etag and kept if the
+// content did not change.
+func (c *ArtifactCache) fill(ctx context.Context, s *ArtifactsClient, st *artifact.ArtifactStatusRT, stale *cacheEntry) error {
+	tmp, err := os.CreateTemp(c.dir, "*.tmp")
+	if err != nil {
+		return fmt.Errorf("ivcap: writing cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+	opts := &ResumableDownload{}
+	if stale != nil {
+		opts.ifNoneMatch = stale.Etag
+	}
+	e := &cacheEntry{ID: st.ID, Etag: stringValue(st.Etag)}
+	k, err := s.contentKey(ctx, st)
+	if err != nil {
+		return err
+	}
+	var enc string
+	pw, finish := plainWriter(tmp, &enc, k)
+	err = finish(s.download(ctx, st, pw, 0, newEtagHash(st.Etag), opts, &enc))
+	switch {
+	case err == errNotModified:
+		e.Size = stale.Size
+	case err != nil:
+		return err
+	default:
+		if err := tmp.Close(); err != nil {
+			return fmt.Errorf("ivcap: writing cache: %w", err)
+		}
+		fi, err := os.Stat(tmp.Name())
+		if err != nil {
+			return err
+		}
+		e.Size = fi.Size()
+		// Drop the entry first so a crash never pairs the new content with
+		// the etag of the old one.
+		os.Remove(c.path(st.ID, ".json"))
+		if err := os.Rename(tmp.Name(), c.path(st.ID, ".data")); err != nil {
+			return fmt.Errorf("ivcap: writing cache: %w", err)
+		}
+	}
+	if err := c.saveEntry(e); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[e.ID]; ok {
+		c.size -= el.Value.(*cacheEntry).Size
+		el.Value = e
+		c.lru.MoveToFront(el)
+	} else {
+		c.entries[e.ID] = c.lru.PushFront(e)
+	}
+	c.size += e.Size
+	c.evict(e.ID)
+	return nil
+}
+
+// saveEntry writes e next to its content.
+func (c *ArtifactCache) saveEntry(e *cacheEntry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(c.path(e.ID, ".json"), b, 0600); err != nil {
+		return fmt.Errorf("ivcap: writing cache: %w", err)
+	}
+	return nil
+}
+
+// evict removes the least recently used entries, other than the one of
+// artifact keep, until the cache fits its limit. c.mu must be held.
+func (c *ArtifactCache) evict(keep string) {
+	for el := c.lru.Back(); el != nil && c.maxSize > 0 && c.size > c.maxSize; {
+		prev := el.Prev()
+		if e := el.Value.(*cacheEntry); e.ID != keep {
+			if _, busy := c.inflight[e.ID]; !busy {
+				c.remove(el)
+			}
+		}
+		el = prev
+	}
+}
+
+// remove drops the entry in el and its files. c.mu must be held.
+func (c *ArtifactCache) remove(el *list.Element) {
+	e := el.Value.(*cacheEntry)
+	os.Remove(c.path(e.ID, ".json"))
+	os.Remove(c.path(e.ID, ".data"))
+	c.lru.Remove(el)
+	delete(c.entries, e.ID)
+	c.size -= e.Size
+}
+
+// path returns the path of the file of artifact id with the given suffix.
+func (c *ArtifactCache) path(id, suffix string) string {
+	sum := sha256.Sum256([]byte(id))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+suffix)
+}
@@ -0,0 +1,175 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	artifact "github.com/ivcap-works/ivcap-core-api/gen/artifact"
+	"github.com/ivcap-works/ivcap-core-api/ivcap"
+	"github.com/ivcap-works/ivcap-core-api/ivcaptest"
+)
+
+// blobCounter serves h, counting the requests for the content of artifacts.
+type blobCounter struct {
+	h     http.Handler
+	blobs int32
+}
+
+func (b *blobCounter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.HasSuffix(r.URL.Path, "/blob") {
+		atomic.AddInt32(&b.blobs, 1)
+	}
+	b.h.ServeHTTP(w, r)
+}
+
+func TestArtifactCache(t *testing.T) {
+	const size = 1000
+	tests := []struct {
+		name    string
+		maxSize int64
+		// steps opens the artifacts named "a", "b" and "c" in turn. "reopen"
+		// creates a new cache on the same directory, and "wipe" removes the
+		// files of the cache behind its back.
+		steps []string
+		// blobs is the number of downloads, and cached the size of the
+		// cache at the end.
+		blobs  int32
+		cached int64
+	}{
+		{name: "hit", steps: []string{"a", "a", "a"}, blobs: 1, cached: size},
+		{name: "several", steps: []string{"a", "b", "a", "b"}, blobs: 2, cached: 2 * size},
+		{name: "evicted", maxSize: size, steps: []string{"a", "b", "a"}, blobs: 3, cached: size},
+		{name: "least recently used", maxSize: 2 * size, steps: []string{"a", "b", "a", "c", "a", "b"}, blobs: 4, cached: 2 * size},
+		{name: "reopened", steps: []string{"a", "reopen", "a"}, blobs: 1, cached: size},
+		{name: "reopened over limit", maxSize: 2 * size, steps: []string{"a", "b", "reopen", "c", "b"}, blobs: 3, cached: 2 * size},
+		{name: "wiped", steps: []string{"a", "wipe", "a"}, blobs: 2, cached: size},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := ivcaptest.New()
+			bc := &blobCounter{h: d.Handler()}
+			srv := httptest.NewServer(bc)
+			defer srv.Close()
+			dir := t.TempDir()
+			cache, err := ivcap.NewArtifactCache(dir, tt.maxSize)
+			if err != nil {
+				t.Fatal(err)
+			}
+			newClient := func() *ivcap.Client {
+				c, err := ivcap.New(srv.URL, ivcap.WithJWT(ivcaptest.Token("urn:ivcap:user:alice")), ivcap.WithArtifactCache(cache))
+				if err != nil {
+					t.Fatal(err)
+				}
+				return c
+			}
+			c := newClient()
+			ctx := context.Background()
+			ids, contents := map[string]string{}, map[string]string{}
+			for _, name := range []string{"a", "b", "c"} {
+				content := strings.Repeat(name, size)
+				up, err := c.Artifacts.Upload(ctx, &artifact.UploadPayload{}, strings.NewReader(content))
+				if err != nil {
+					t.Fatal(err)
+				}
+				ids[name], contents[name] = up.ID, content
+			}
+
+			for _, step := range tt.steps {
+				switch step {
+				case "reopen":
+					if cache, err = ivcap.NewArtifactCache(dir, tt.maxSize); err != nil {
+						t.Fatal(err)
+					}
+					c = newClient()
+				case "wipe":
+					files, _ := filepath.Glob(filepath.Join(dir, "*"))
+					for _, f := range files {
+						os.Remove(f)
+					}
+				default:
+					r, _, err := c.Artifacts.Open(ctx, ids[step])
+					if err != nil {
+						t.Fatal(err)
+					}
+					b, err := io.ReadAll(r)
+					r.Close()
+					if err != nil {
+						t.Fatal(err)
+					}
+					if string(b) != contents[step] {
+						t.Fatalf("%s: read %d bytes differing from the %d uploaded", step, len(b), size)
+					}
+				}
+			}
+			if bc.blobs != tt.blobs {
+				t.Errorf("downloaded %d times, want %d", bc.blobs, tt.blobs)
+			}
+			if got := cache.Size(); got != tt.cached {
+				t.Errorf("cached %d bytes, want %d", got, tt.cached)
+			}
+		})
+	}
+}
+
+func TestArtifactCacheConcurrent(t *testing.T) {
+	d := ivcaptest.New()
+	bc := &blobCounter{h: d.Handler()}
+	srv := httptest.NewServer(bc)
+	defer srv.Close()
+	cache, err := ivcap.NewArtifactCache(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := ivcap.New(srv.URL, ivcap.WithJWT(ivcaptest.Token("urn:ivcap:user:alice")), ivcap.WithArtifactCache(cache))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	content := strings.Repeat("content", 10000)
+	up, err := c.Artifacts.Upload(ctx, &artifact.UploadPayload{}, strings.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r, _, err := c.Artifacts.Open(ctx, up.ID)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer r.Close()
+			if b, err := io.ReadAll(r); err != nil || string(b) != content {
+				t.Errorf("read %d bytes, %v", len(b), err)
+			}
+		}()
+	}
+	wg.Wait()
+	if bc.blobs != 1 {
+		t.Errorf("downloaded %d times, want once", bc.blobs)
+	}
+}
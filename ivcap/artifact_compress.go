@@ -0,0 +1,223 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"sort"
+	"strings"
+	"sync"
+
+	artifact "github.com/ivcap-works/ivcap-core-api/gen/artifact"
+	"github.com/klauspost/compress/zstd"
+)
+
+// encoding compresses and decompresses content for a Content-Encoding.
+type encoding struct {
+	newWriter func(w io.Writer) (io.WriteCloser, error)
+	newReader func(r io.Reader) (io.ReadCloser, error)
+}
+
+var (
+	encodingsMu sync.RWMutex
+	encodings   = map[string]*encoding{
+		"gzip": {
+			newWriter: func(w io.Writer) (io.WriteCloser, error) { return gzip.NewWriter(w), nil },
+			newReader: func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) },
+		},
+		"zstd": {
+			newWriter: func(w io.Writer) (io.WriteCloser, error) { return zstd.NewWriter(w) },
+			newReader: func(r io.Reader) (io.ReadCloser, error) {
+				d, err := zstd.NewReader(r)
+				if err != nil {
+					return nil, err
+				}
+				return d.IOReadCloser(), nil
+			},
+		},
+	}
+)
+
+// RegisterEncoding makes the Content-Encoding name, such as "br", available
+// to UploadCompressed and the download methods. gzip and zstd are built in.
+// newWriter returns a writer compressing to w, and newReader a reader
+// decompressing r.
+func RegisterEncoding(name string, newWriter func(w io.Writer) (io.WriteCloser, error), newReader func(r io.Reader) (io.ReadCloser, error)) {
+	encodingsMu.Lock()
+	defer encodingsMu.Unlock()
+	encodings[strings.ToLower(name)] = &encoding{newWriter: newWriter, newReader: newReader}
+}
+
+// lookupEncoding returns the registered encoding name.
+func lookupEncoding(name string) (*encoding, error) {
+	encodingsMu.RLock()
+	defer encodingsMu.RUnlock()
+	if e, ok := encodings[strings.ToLower(strings.TrimSpace(name))]; ok {
+		return e, nil
+	}
+	return nil, fmt.Errorf("ivcap: unsupported content encoding %q", name)
+}
+
+// acceptEncoding returns the value of the Accept-Encoding header listing the
+// registered encodings.
+func acceptEncoding() string {
+	encodingsMu.RLock()
+	defer encodingsMu.RUnlock()
+	names := make([]string, 0, len(encodings)+1)
+	for n := range encodings {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return strings.Join(append(names, "identity"), ", ")
+}
+
+// identity reports whether the Content-Encoding enc leaves content as is.
+func identity(enc string) bool {
+	return enc == "" || strings.EqualFold(enc, "identity")
+}
+
+// compressedTypes lists the MIME types of formats which are compressed
+// already. All audio and video formats are too.
+var compressedTypes = map[string]bool{
+	"application/gzip":               true,
+	"application/x-gzip":             true,
+	"application/zip":                true,
+	"application/zstd":               true,
+	"application/x-bzip2":            true,
+	"application/x-xz":               true,
+	"application/x-7z-compressed":    true,
+	"application/vnd.rar":            true,
+	"application/x-rar-compressed":   true,
+	"application/vnd.apache.parquet": true,
+	"image/jpeg":                     true,
+	"image/png":                      true,
+	"image/gif":                      true,
+	"image/webp":                     true,
+	"image/avif":                     true,
+	"image/heic":                     true,
+}
+
+// Compressible reports whether content of the MIME type contentType is
+// worth compressing, that is unless the format is compressed already.
+func Compressible(contentType string) bool {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mt = strings.ToLower(contentType)
+	}
+	if strings.HasPrefix(mt, "audio/") || strings.HasPrefix(mt, "video/") {
+		return false
+	}
+	return !compressedTypes[mt]
+}
+
+// UploadCompressed uploads body as a new artifact like Upload, compressing
+// it on the fly with the Content-Encoding enc, such as "gzip". Content whose
+// type is compressed already, as reported by Compressible, or which has a
+// Content-Encoding set in p is uploaded as is. As the compressed size is not
+// known in advance, the Content-Length of p is ignored.
+func (s *ArtifactsClient) UploadCompressed(ctx context.Context, p *artifact.UploadPayload, body io.Reader, enc string) (*artifact.ArtifactUploadRT, error) {
+	var q artifact.UploadPayload
+	if p != nil {
+		q = *p
+	}
+	if identity(enc) || q.ContentEncoding != nil || !Compressible(stringValue(q.ContentType)) {
+		return s.Upload(ctx, &q, body)
+	}
+	e, err := lookupEncoding(enc)
+	if err != nil {
+		return nil, err
+	}
+	pr, pw := io.Pipe()
+	zw, err := e.newWriter(pw)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		_, err := io.Copy(zw, body)
+		if cerr := zw.Close(); err == nil {
+			err = cerr
+		}
+		pw.CloseWithError(err)
+	}()
+	q.ContentEncoding = &enc
+	q.ContentLength = nil
+	res, err := s.Upload(ctx, &q, pr)
+	pr.CloseWithError(io.ErrClosedPipe)
+	return res, err
+}
+
+// decoder writes the content written to it to w, decoded with the
+// Content-Encoding *enc as known at the first write.
+type decoder struct {
+	w       io.Writer
+	enc     *string
+	started bool
+	pw      *io.PipeWriter
+	done    chan error
+}
+
+func (d *decoder) Write(b []byte) (int, error) {
+	if !d.started {
+		d.started = true
+		if !identity(*d.enc) {
+			e, err := lookupEncoding(*d.enc)
+			if err != nil {
+				return 0, err
+			}
+			d.start(e)
+		}
+	}
+	if d.pw == nil {
+		return d.w.Write(b)
+	}
+	return d.pw.Write(b)
+}
+
+// start decodes the content written to d with e in a goroutine.
+func (d *decoder) start(e *encoding) {
+	pr, pw := io.Pipe()
+	d.pw, d.done = pw, make(chan error, 1)
+	go func() {
+		r, err := e.newReader(pr)
+		if err == nil {
+			_, err = io.Copy(d.w, r)
+			r.Close()
+		}
+		pr.CloseWithError(err)
+		d.done <- err
+	}()
+}
+
+// finish ends the decoding of the content written to d, which stopped with
+// err, and returns the first error met.
+func (d *decoder) finish(err error) error {
+	if d.pw == nil {
+		return err
+	}
+	if err != nil {
+		d.pw.CloseWithError(err)
+		<-d.done
+		return err
+	}
+	d.pw.Close()
+	if err := <-d.done; err != nil {
+		return fmt.Errorf("ivcap: decoding %s content: %w", *d.enc, err)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	artifact "github.com/ivcap-works/ivcap-core-api/gen/artifact"
+)
+
+func TestUploadCompressed(t *testing.T) {
+	content := strings.Repeat("some compressible content ", 1000)
+	tests := []struct {
+		name        string
+		contentType string
+		enc         string
+		// stored is whether the content is stored compressed.
+		stored bool
+	}{
+		{name: "gzip", contentType: "text/plain", enc: "gzip", stored: true},
+		{name: "zstd", contentType: "text/plain", enc: "zstd", stored: true},
+		{name: "upper case", contentType: "text/plain", enc: "ZSTD", stored: true},
+		{name: "identity", contentType: "text/plain", enc: "identity"},
+		{name: "compressed already", contentType: "image/png", enc: "zstd"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, d := newTestClient(t, "urn:ivcap:user:alice")
+			ctx := context.Background()
+			ct := tt.contentType
+			up, err := c.Artifacts.UploadCompressed(ctx, &artifact.UploadPayload{ContentType: &ct}, strings.NewReader(content), tt.enc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			raw, err := d.Artifacts.Content(up.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored := len(raw) < len(content); stored != tt.stored {
+				t.Errorf("stored %d of %d bytes, want compressed: %v", len(raw), len(content), tt.stored)
+			}
+			var b bytes.Buffer
+			if _, err := c.Artifacts.Download(ctx, up.ID, &b, nil); err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != content {
+				t.Errorf("downloaded %d bytes differing from the %d uploaded", b.Len(), len(content))
+			}
+		})
+	}
+}
+
+func TestUploadCompressedUnknown(t *testing.T) {
+	c, _ := newTestClient(t, "urn:ivcap:user:alice")
+	ct := "text/plain"
+	if _, err := c.Artifacts.UploadCompressed(context.Background(), &artifact.UploadPayload{ContentType: &ct}, strings.NewReader("content"), "lzma"); err == nil {
+		t.Error("uploaded with an unknown encoding")
+	}
+}
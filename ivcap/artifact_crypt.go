@@ -0,0 +1,342 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+
+	artifact "github.com/ivcap-works/ivcap-core-api/gen/artifact"
+	aspect "github.com/ivcap-works/ivcap-core-api/gen/aspect"
+	secret "github.com/ivcap-works/ivcap-core-api/gen/secret"
+	"github.com/ivcap-works/ivcap-core-api/ivcaperr"
+)
+
+// EncryptionSchema is the schema of the aspects describing how the content
+// of an artifact is encrypted, as EncryptionInfo.
+const EncryptionSchema = "urn:ivcap:schema:artifact-encryption.1"
+
+// EncryptionAlgorithm is the algorithm content is encrypted with: AES-256-GCM
+// applied to segments of SegmentSize bytes, each sealed with a nonce made of
+// a random prefix, the index of the segment and a flag marking the last one,
+// so that segments cannot be reordered or dropped.
+const EncryptionAlgorithm = "AES-256-GCM-SEGMENTED"
+
+// EncryptedMimeType is the mime type of the artifacts uploaded with
+// UploadEncrypted. It tells the download methods to look for the
+// EncryptionSchema aspect of the artifact.
+const EncryptedMimeType = "application/vnd.ivcap.encrypted"
+
+// keySecretType is the type of the secrets holding keys created by
+// SecretsClient.CreateKey.
+const keySecretType = "aes-256-key"
+
+// segmentSize is the size of the plaintext segments.
+const segmentSize = 64 << 10
+
+// ErrInvalidKey is returned when a secret does not hold a key created by
+// SecretsClient.CreateKey, or a wrapped key cannot be opened with it.
+var ErrInvalidKey = errors.New("ivcap: invalid encryption key")
+
+// EncryptionInfo is the content of the EncryptionSchema aspects.
+type EncryptionInfo struct {
+	Schema      string `json:"$schema"`
+	Algorithm   string `json:"algorithm"`
+	SegmentSize int    `json:"segment-size"`
+	// NoncePrefix is the random prefix of the nonces of the segments.
+	NoncePrefix []byte `json:"nonce-prefix"`
+	// KeySecret is the name of the secret holding the key the data key is
+	// wrapped with.
+	KeySecret string `json:"key-secret"`
+	// WrappedKey is the data key the content is encrypted with, sealed with
+	// AES-256-GCM and prefixed with the nonce used.
+	WrappedKey []byte `json:"wrapped-key"`
+	// ContentType is the mime type of the plain content, if known.
+	ContentType string `json:"content-type,omitempty"`
+}
+
+// contentKey is the data key of an encrypted artifact.
+type contentKey struct {
+	aead cipher.AEAD
+	info *EncryptionInfo
+}
+
+// CreateKey creates a random AES-256 key in the secret name, for use with
+// ArtifactsClient.UploadEncrypted. It fails with ivcaperr.ErrAlreadyExists
+// rather than replace the key of existing artifacts.
+func (s *SecretsClient) CreateKey(ctx context.Context, name string) error {
+	_, err := s.Get(ctx, &secret.GetPayload{SecretName: name})
+	if err == nil {
+		return fmt.Errorf("ivcap: secret %s: %w", name, ivcaperr.ErrAlreadyExists)
+	}
+	if ivcaperr.KindOf(err) != ivcaperr.ErrNotFound {
+		return err
+	}
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return err
+	}
+	typ := keySecretType
+	return s.Set(ctx, &secret.SetSecretRequestT{
+		SecretName:  name,
+		SecretType:  &typ,
+		SecretValue: base64.StdEncoding.EncodeToString(key),
+	})
+}
+
+// key returns the AEAD of the key held in the secret name.
+func (s *SecretsClient) key(ctx context.Context, name string) (cipher.AEAD, error) {
+	res, err := s.Get(ctx, &secret.GetPayload{SecretName: name})
+	if err != nil {
+		return nil, err
+	}
+	key, err := base64.StdEncoding.DecodeString(res.SecretValue)
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("%w: secret %s", ErrInvalidKey, name)
+	}
+	return newGCM(key)
+}
+
+// UploadEncrypted uploads body as a new artifact like Upload, encrypted with
+// a fresh data key. The data key is wrapped with the key held in the secret
+// keySecret, created with SecretsClient.CreateKey, and recorded with the
+// algorithm in an EncryptionSchema aspect of the artifact, so only users who
+// can read that secret can decrypt the content. The artifact is stored with
+// the mime type EncryptedMimeType, the one of p being recorded in the aspect.
+// The download methods decrypt it transparently, and refuse to return the
+// content of such an artifact whose aspect is missing.
+func (s *ArtifactsClient) UploadEncrypted(ctx context.Context, p *artifact.UploadPayload, body io.Reader, keySecret string) (*artifact.ArtifactUploadRT, error) {
+	kek, err := s.c.Secrets.key(ctx, keySecret)
+	if err != nil {
+		return nil, err
+	}
+	dek := make([]byte, 32)
+	prefix := make([]byte, 7)
+	nonce := make([]byte, kek.NonceSize())
+	for _, b := range [][]byte{dek, prefix, nonce} {
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+	}
+	aead, err := newGCM(dek)
+	if err != nil {
+		return nil, err
+	}
+	info := &EncryptionInfo{
+		Schema:      EncryptionSchema,
+		Algorithm:   EncryptionAlgorithm,
+		SegmentSize: segmentSize,
+		NoncePrefix: prefix,
+		KeySecret:   keySecret,
+		WrappedKey:  kek.Seal(nonce, nonce, dek, nil),
+	}
+
+	var q artifact.UploadPayload
+	if p != nil {
+		q = *p
+	}
+	if q.ContentType != nil {
+		info.ContentType = *q.ContentType
+	}
+	mt := EncryptedMimeType
+	q.ContentType = &mt
+	if q.ContentLength != nil {
+		n := ciphertextSize(int64(*q.ContentLength), aead.Overhead())
+		if q.ContentLength, err = contentLength(n); err != nil {
+			return nil, err
+		}
+	}
+	pr, pw := io.Pipe()
+	go func() {
+		e := encrypter(pw, &contentKey{aead: aead, info: info})
+		_, err := io.Copy(e, body)
+		if err == nil {
+			err = e.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+	res, err := s.Upload(ctx, &q, pr)
+	pr.CloseWithError(io.ErrClosedPipe)
+	if err != nil {
+		return nil, err
+	}
+	_, err = s.c.Aspects.Create(ctx, &aspect.CreatePayload{
+		Entity:      res.ID,
+		Schema:      EncryptionSchema,
+		Content:     info,
+		ContentType: "application/json",
+		Policy:      q.Policy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ivcap: recording encryption of %s: %w", res.ID, err)
+	}
+	return res, nil
+}
+
+// encrypted reports whether the artifact with status st was uploaded with
+// UploadEncrypted.
+func encrypted(st *artifact.ArtifactStatusRT) bool {
+	mt, _, err := mime.ParseMediaType(stringValue(st.MimeType))
+	return err == nil && mt == EncryptedMimeType
+}
+
+// contentKey returns the data key of the artifact with status st, or nil if
+// its content is not encrypted.
+func (s *ArtifactsClient) contentKey(ctx context.Context, st *artifact.ArtifactStatusRT) (*contentKey, error) {
+	if !encrypted(st) {
+		return nil, nil
+	}
+	id := st.ID
+	schema, include, limit := EncryptionSchema, true, 1
+	res, err := s.c.Aspects.List(ctx, &aspect.ListPayload{Entity: &id, Schema: &schema, IncludeContent: &include, Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Items) == 0 {
+		return nil, fmt.Errorf("ivcap: encryption of %s: %w", id, ivcaperr.ErrNotFound)
+	}
+	b, err := json.Marshal(res.Items[0].Content)
+	if err != nil {
+		return nil, err
+	}
+	var info EncryptionInfo
+	if err := json.Unmarshal(b, &info); err != nil {
+		return nil, fmt.Errorf("ivcap: decoding encryption of %s: %w", id, err)
+	}
+	if info.Algorithm != EncryptionAlgorithm || info.SegmentSize <= 0 {
+		return nil, fmt.Errorf("ivcap: unsupported encryption %q of %s", info.Algorithm, id)
+	}
+	kek, err := s.c.Secrets.key(ctx, info.KeySecret)
+	if err != nil {
+		return nil, err
+	}
+	ns := kek.NonceSize()
+	if len(info.WrappedKey) < ns {
+		return nil, fmt.Errorf("%w: wrapped key of %s", ErrInvalidKey, id)
+	}
+	dek, err := kek.Open(nil, info.WrappedKey[:ns], info.WrappedKey[ns:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: wrapped key of %s", ErrInvalidKey, id)
+	}
+	aead, err := newGCM(dek)
+	if err != nil {
+		return nil, err
+	}
+	return &contentKey{aead: aead, info: &info}, nil
+}
+
+// segmenter applies seal to the content written to it in segments of size
+// bytes, writing the results to w. Only at Close is the last segment known.
+type segmenter struct {
+	w    io.Writer
+	size int
+	seal func(index uint32, last bool, segment []byte) ([]byte, error)
+	buf  []byte
+	n    uint32
+}
+
+// encrypter returns a segmenter encrypting content with k.
+func encrypter(w io.Writer, k *contentKey) *segmenter {
+	return &segmenter{w: w, size: k.info.SegmentSize, seal: func(i uint32, last bool, seg []byte) ([]byte, error) {
+		return k.aead.Seal(nil, k.nonce(i, last), seg, nil), nil
+	}}
+}
+
+// decrypter returns a segmenter decrypting content encrypted with k.
+func decrypter(w io.Writer, k *contentKey) *segmenter {
+	return &segmenter{w: w, size: k.info.SegmentSize + k.aead.Overhead(), seal: func(i uint32, last bool, seg []byte) ([]byte, error) {
+		b, err := k.aead.Open(nil, k.nonce(i, last), seg, nil)
+		if err != nil {
+			return nil, fmt.Errorf("%w: segment %d cannot be decrypted", ErrIntegrity, i)
+		}
+		return b, nil
+	}}
+}
+
+func (s *segmenter) Write(p []byte) (int, error) {
+	if s.buf == nil {
+		s.buf = make([]byte, 0, s.size)
+	}
+	written := 0
+	for len(p) > 0 {
+		if len(s.buf) == s.size {
+			if err := s.flush(false); err != nil {
+				return written, err
+			}
+		}
+		k := copy(s.buf[len(s.buf):s.size], p)
+		s.buf = s.buf[:len(s.buf)+k]
+		p = p[k:]
+		written += k
+	}
+	return written, nil
+}
+
+// Close seals the last segment.
+func (s *segmenter) Close() error {
+	return s.flush(true)
+}
+
+func (s *segmenter) flush(last bool) error {
+	if s.n == math.MaxUint32 {
+		return errors.New("ivcap: content too large to encrypt")
+	}
+	b, err := s.seal(s.n, last, s.buf)
+	if err != nil {
+		return err
+	}
+	s.n++
+	s.buf = s.buf[:0]
+	_, err = s.w.Write(b)
+	return err
+}
+
+// nonce returns the nonce of segment i.
+func (k *contentKey) nonce(i uint32, last bool) []byte {
+	nonce := make([]byte, k.aead.NonceSize())
+	copy(nonce, k.info.NoncePrefix)
+	binary.BigEndian.PutUint32(nonce[len(nonce)-5:], i)
+	if last {
+		nonce[len(nonce)-1] = 1
+	}
+	return nonce
+}
+
+// ciphertextSize returns the size of size bytes of content once encrypted.
+func ciphertextSize(size int64, overhead int) int64 {
+	segments := (size + segmentSize - 1) / segmentSize
+	if segments == 0 {
+		segments = 1
+	}
+	return size + segments*int64(overhead)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	b, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(b)
+}
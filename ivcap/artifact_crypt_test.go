@@ -0,0 +1,167 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	artifact "github.com/ivcap-works/ivcap-core-api/gen/artifact"
+	aspect "github.com/ivcap-works/ivcap-core-api/gen/aspect"
+	"github.com/ivcap-works/ivcap-core-api/ivcap"
+	"github.com/ivcap-works/ivcap-core-api/ivcaperr"
+	"github.com/ivcap-works/ivcap-core-api/ivcaptest"
+)
+
+func TestUploadEncrypted(t *testing.T) {
+	tests := []struct {
+		name string
+		size int
+	}{
+		{name: "empty", size: 0},
+		{name: "short", size: 100},
+		{name: "one segment", size: 64 << 10},
+		{name: "several segments", size: 200 << 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, d := newTestClient(t, "urn:ivcap:user:alice")
+			ctx := context.Background()
+			if err := c.Secrets.CreateKey(ctx, "key"); err != nil {
+				t.Fatal(err)
+			}
+			content := bytes.Repeat([]byte("secret!"), tt.size/7+1)[:tt.size]
+			ct, n := "text/plain", len(content)
+			res, err := c.Artifacts.UploadEncrypted(ctx, &artifact.UploadPayload{ContentType: &ct, ContentLength: &n}, bytes.NewReader(content), "key")
+			if err != nil {
+				t.Fatal(err)
+			}
+			stored, err := d.Artifacts.Content(res.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(content) > 0 && bytes.Contains(stored, content) {
+				t.Error("content stored in plain")
+			}
+			st, err := c.Artifacts.Read(ctx, res.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := *st.MimeType; got != ivcap.EncryptedMimeType {
+				t.Errorf("mime type %q, want %q", got, ivcap.EncryptedMimeType)
+			}
+			if *st.Size != int64(len(stored)) {
+				t.Errorf("size %d, want %d", *st.Size, len(stored))
+			}
+
+			var buf bytes.Buffer
+			if _, err := c.Artifacts.Download(ctx, res.ID, &buf, nil); err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(buf.Bytes(), content) {
+				t.Errorf("Download returned %d bytes, want %d", buf.Len(), len(content))
+			}
+			r, _, err := c.Artifacts.Open(ctx, res.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := io.ReadAll(r)
+			r.Close()
+			if err != nil || !bytes.Equal(b, content) {
+				t.Errorf("Open returned %d bytes, %v", len(b), err)
+			}
+			path := filepath.Join(t.TempDir(), "out")
+			if _, err := c.Artifacts.DownloadFile(ctx, res.ID, path, nil); err != nil {
+				t.Fatal(err)
+			}
+			if b, _ := os.ReadFile(path); !bytes.Equal(b, content) {
+				t.Errorf("DownloadFile wrote %d bytes, want %d", len(b), len(content))
+			}
+		})
+	}
+}
+
+func TestUploadEncryptedMissingKey(t *testing.T) {
+	c, _ := newTestClient(t, "urn:ivcap:user:alice")
+	ctx := context.Background()
+	if err := c.Secrets.CreateKey(ctx, "key"); err != nil {
+		t.Fatal(err)
+	}
+	res, err := c.Artifacts.UploadEncrypted(ctx, &artifact.UploadPayload{}, strings.NewReader("content"), "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := ivcap.EncryptionSchema
+	it := c.Aspects.ListIter(ctx, &aspect.ListPayload{Entity: &res.ID, Schema: &schema})
+	for it.Next() {
+		if err := c.Aspects.Retract(ctx, it.Item().ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	_, err = c.Artifacts.Download(ctx, res.ID, &buf, nil)
+	if !errors.Is(err, ivcaperr.ErrNotFound) {
+		t.Errorf("got %v, want ErrNotFound", err)
+	}
+	if buf.Len() > 0 {
+		t.Errorf("%d bytes of ciphertext written", buf.Len())
+	}
+}
+
+// TestDownloadPlain checks that the content of artifacts not uploaded
+// encrypted is downloaded without looking for an encryption aspect.
+func TestDownloadPlain(t *testing.T) {
+	d := ivcaptest.New()
+	h := d.Handler()
+	var aspectCalls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/1/aspects") {
+			atomic.AddInt32(&aspectCalls, 1)
+		}
+		h.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+	c, err := ivcap.New(srv.URL, ivcap.WithJWT(ivcaptest.Token("urn:ivcap:user:alice")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	res, err := c.Artifacts.Upload(ctx, &artifact.UploadPayload{}, strings.NewReader("plain"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if _, err := c.Artifacts.Download(ctx, res.ID, &buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "plain" {
+		t.Errorf("Download returned %q", buf.String())
+	}
+	if n := atomic.LoadInt32(&aspectCalls); n != 0 {
+		t.Errorf("%d aspect requests made", n)
+	}
+}
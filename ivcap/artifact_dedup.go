@@ -0,0 +1,162 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+
+	artifact "github.com/ivcap-works/ivcap-core-api/gen/artifact"
+	aspect "github.com/ivcap-works/ivcap-core-api/gen/aspect"
+	"github.com/ivcap-works/ivcap-core-api/ivcaperr"
+)
+
+// DigestSchema is the schema of the aspects recording the digest of the
+// content of an artifact, as ContentDigest.
+const DigestSchema = "urn:ivcap:schema:artifact-digest.1"
+
+// ContentDigest is the content of the DigestSchema aspects.
+type ContentDigest struct {
+	Schema    string `json:"$schema"`
+	Algorithm string `json:"algorithm"`
+	Digest    string `json:"digest"`
+	Size      int64  `json:"size"`
+
+	// md5 is the MD5 of the content when known, to check it against etags.
+	md5 string
+}
+
+// UploadDeduplicated uploads content as a new artifact unless an artifact
+// with the same content already exists, in which case the status of that
+// artifact is returned unchanged, keeping its name, collection and policy.
+// Artifacts are matched by the SHA-256 digest of their content, which is
+// recorded as a DigestSchema aspect on the artifacts uploaded with this
+// method. The second result reports whether an existing artifact was
+// returned.
+func (s *ArtifactsClient) UploadDeduplicated(ctx context.Context, p *artifact.UploadPayload, content io.ReadSeeker) (*artifact.ArtifactStatusRT, bool, error) {
+	d, err := digest(content)
+	if err != nil {
+		return nil, false, err
+	}
+	st, err := s.FindByDigest(ctx, d)
+	if err != nil || st != nil {
+		return st, st != nil, err
+	}
+	var q artifact.UploadPayload
+	if p != nil {
+		q = *p
+	}
+	if q.ContentLength, err = contentLength(d.Size); err != nil {
+		return nil, false, err
+	}
+	res, err := s.Upload(ctx, &q, io.NopCloser(content))
+	if err != nil {
+		return nil, false, err
+	}
+	_, err = s.c.Aspects.Create(ctx, &aspect.CreatePayload{
+		Entity:      res.ID,
+		Schema:      DigestSchema,
+		Content:     d,
+		ContentType: "application/json",
+		Policy:      q.Policy,
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("ivcap: recording digest of %s: %w", res.ID, err)
+	}
+	st, err = s.Read(ctx, res.ID)
+	return st, false, err
+}
+
+// FindByDigest returns the status of a ready artifact whose content has the
+// digest d, or nil if there is none. As anyone may record a DigestSchema
+// aspect, the content of the artifacts it points to is checked before one is
+// returned: by its etag when it is the MD5 of the content and d comes from
+// UploadDeduplicated, and by downloading and hashing it otherwise.
+func (s *ArtifactsClient) FindByDigest(ctx context.Context, d *ContentDigest) (*artifact.ArtifactStatusRT, error) {
+	schema := DigestSchema
+	path := fmt.Sprintf(`$ ? (@.digest == %q)`, d.Digest)
+	it := s.c.Aspects.ListIter(ctx, &aspect.ListPayload{Schema: &schema, ContentPath: &path})
+	for it.Next() {
+		st, err := s.Read(ctx, it.Item().Entity)
+		if ivcaperr.KindOf(err) == ivcaperr.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if st.Status != "ready" || st.Size == nil || *st.Size != d.Size {
+			continue
+		}
+		ok, err := s.hasDigest(ctx, st, d)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return st, nil
+		}
+	}
+	return nil, it.Err()
+}
+
+// hasDigest reports whether the content of the artifact with status st has
+// the digest d.
+func (s *ArtifactsClient) hasDigest(ctx context.Context, st *artifact.ArtifactStatusRT, d *ContentDigest) (bool, error) {
+	if m := md5Etag.FindStringSubmatch(stringValue(st.Etag)); m != nil && d.md5 != "" {
+		return strings.EqualFold(m[1], d.md5), nil
+	}
+	if d.Algorithm != "sha256" {
+		return false, nil
+	}
+	h := sha256.New()
+	var enc string
+	w, finish := plainWriter(h, &enc, nil)
+	err := finish(s.download(ctx, st, w, 0, newEtagHash(st.Etag), nil, &enc))
+	switch ivcaperr.KindOf(err) {
+	case nil:
+		return hex.EncodeToString(h.Sum(nil)) == d.Digest, nil
+	case ivcaperr.ErrNotFound, ivcaperr.ErrForbidden:
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// digest returns the SHA-256 digest of content, which is rewound, noting its
+// MD5 as well.
+func digest(content io.ReadSeeker) (*ContentDigest, error) {
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	h, m := sha256.New(), md5.New()
+	n, err := io.Copy(io.MultiWriter(h, m), content)
+	if err != nil {
+		return nil, fmt.Errorf("ivcap: reading content: %w", err)
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return &ContentDigest{
+		Schema:    DigestSchema,
+		Algorithm: "sha256",
+		Digest:    hex.EncodeToString(h.Sum(nil)),
+		Size:      n,
+		md5:       hex.EncodeToString(m.Sum(nil)),
+	}, nil
+}
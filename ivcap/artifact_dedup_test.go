@@ -0,0 +1,97 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	artifact "github.com/ivcap-works/ivcap-core-api/gen/artifact"
+	aspect "github.com/ivcap-works/ivcap-core-api/gen/aspect"
+	"github.com/ivcap-works/ivcap-core-api/ivcap"
+	"github.com/ivcap-works/ivcap-core-api/ivcaptest"
+)
+
+func TestUploadDeduplicated(t *testing.T) {
+	const content = "the same content"
+	tests := []struct {
+		name string
+		// existing is the content of an artifact uploaded by another user
+		// with a digest aspect claiming it is content.
+		existing string
+		dedup    bool
+	}{
+		{name: "same content", existing: content, dedup: true},
+		{name: "forged digest", existing: strings.ToUpper(content)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := ivcaptest.New()
+			c := newDeploymentClient(t, d, "urn:ivcap:user:alice")
+			other := newDeploymentClient(t, d, "urn:ivcap:user:mallory")
+			ctx := context.Background()
+			up, err := other.Artifacts.Upload(ctx, &artifact.UploadPayload{}, strings.NewReader(tt.existing))
+			if err != nil {
+				t.Fatal(err)
+			}
+			sum := sha256.Sum256([]byte(content))
+			_, err = other.Aspects.Create(ctx, &aspect.CreatePayload{
+				Entity: up.ID,
+				Schema: ivcap.DigestSchema,
+				Content: &ivcap.ContentDigest{
+					Schema:    ivcap.DigestSchema,
+					Algorithm: "sha256",
+					Digest:    hex.EncodeToString(sum[:]),
+					Size:      int64(len(content)),
+				},
+				ContentType: "application/json",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			st, found, err := c.Artifacts.UploadDeduplicated(ctx, &artifact.UploadPayload{}, strings.NewReader(content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if found != tt.dedup || (st.ID == up.ID) != tt.dedup {
+				t.Errorf("got %s, found %v; want existing %s: %v", st.ID, found, up.ID, tt.dedup)
+			}
+			got, err := d.Artifacts.Content(st.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != content {
+				t.Errorf("content = %q, want %q", got, content)
+			}
+
+			// Without the MD5 noted by UploadDeduplicated, the content is
+			// hashed again.
+			st, err = c.Artifacts.FindByDigest(ctx, &ivcap.ContentDigest{Algorithm: "sha256", Digest: hex.EncodeToString(sum[:]), Size: int64(len(content))})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if st == nil {
+				t.Fatal("FindByDigest found nothing")
+			}
+			if got, _ := d.Artifacts.Content(st.ID); string(got) != content {
+				t.Errorf("FindByDigest returned %s holding %q", st.ID, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,452 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	artifact "github.com/ivcap-works/ivcap-core-api/gen/artifact"
+	"github.com/ivcap-works/ivcap-core-api/ivcaperr"
+	goahttp "goa.design/goa/v3/http"
+	goa "goa.design/goa/v3/pkg"
+)
+
+// ErrIntegrity is returned when downloaded content does not match the size
+// or etag of the artifact.
+var ErrIntegrity = errors.New("ivcap: downloaded content does not match the artifact")
+
+// errNotModified is returned by a download made with an ifNoneMatch etag
+// which still matches the content.
+var errNotModified = errors.New("ivcap: content not modified")
+
+// md5Etag matches the etags which are the MD5 of the content, as returned for
+// objects uploaded in one part.
+var md5Etag = regexp.MustCompile(`^"?([0-9a-fA-F]{32})"?$`)
+
+// ResumableDownload configures ArtifactsClient.Download and DownloadFile.
+type ResumableDownload struct {
+	// MaxRetries is the number of times in a row an interrupted download is
+	// resumed. It defaults to 5.
+	MaxRetries int
+	// Progress is called as content is received with the number of bytes
+	// downloaded so far and the total size, or -1 if it is not known.
+	Progress func(offset, size int64)
+
+	// ifNoneMatch is an etag of content held by the caller. The download
+	// fails with errNotModified if it still matches.
+	ifNoneMatch string
+}
+
+// blobRange is the payload of the download endpoint.
+type blobRange struct {
+	href        string
+	etag        string
+	offset      int64
+	ifNoneMatch string
+}
+
+// blobResponse is the result of the download endpoint.
+type blobResponse struct {
+	body        io.ReadCloser
+	etag        string
+	encoding    string
+	partial     bool
+	notModified bool
+}
+
+// Download writes the content of artifact id to w and returns the status of
+// the artifact. The content is fetched from the data link of the artifact,
+// with the credentials of the client only if the link is on the host of the
+// deployment. Interrupted transfers, including those cut by the client
+// timeout, are resumed with range requests. The number of bytes received is
+// checked against the size of the artifact, and so is their MD5 against the
+// etag when the etag is one. Content stored with a Content-Encoding, such as
+// "gzip", is decoded, and content uploaded with UploadEncrypted decrypted.
+// opts may be nil.
+func (s *ArtifactsClient) Download(ctx context.Context, id string, w io.Writer, opts *ResumableDownload) (*artifact.ArtifactStatusRT, error) {
+	st, err := s.Read(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	k, err := s.contentKey(ctx, st)
+	if err != nil {
+		return nil, err
+	}
+	var enc string
+	pw, finish := plainWriter(w, &enc, k)
+	return st, finish(s.download(ctx, st, pw, 0, newEtagHash(st.Etag), opts, &enc))
+}
+
+// plainWriter returns the writer turning the content of an artifact, as
+// stored, into the plain content written to w, and the function ending the
+// transfer of the content, which stopped with the error given. The content is
+// decrypted with k, unless nil, and decoded with the Content-Encoding *enc,
+// as known at the first write.
+func plainWriter(w io.Writer, enc *string, k *contentKey) (io.Writer, func(error) error) {
+	d := &decoder{w: w, enc: enc}
+	if k == nil {
+		return d, d.finish
+	}
+	e := decrypter(d, k)
+	return e, func(err error) error {
+		if err == nil {
+			err = e.Close()
+		}
+		return d.finish(err)
+	}
+}
+
+// DownloadFile downloads the content of artifact id to the file at path and
+// returns the status of the artifact. The content is written to path+".part"
+// first, which is renamed to path once complete and verified. A ".part" file
+// left by an interrupted call is resumed rather than downloaded again, as the
+// content of an artifact never changes, but only if the ".part.id" file next
+// to it names the same artifact and etag; otherwise it is discarded. It holds
+// the content as stored, which is decrypted and decoded to path at the end.
+// opts may be nil.
+func (s *ArtifactsClient) DownloadFile(ctx context.Context, id, path string, opts *ResumableDownload) (*artifact.ArtifactStatusRT, error) {
+	st, err := s.Read(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	k, err := s.contentKey(ctx, st)
+	if err != nil {
+		return nil, err
+	}
+	part := path + ".part"
+	if err := stampPart(part, st); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(part, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	h := newEtagHash(st.Etag)
+	offset, err := resumePart(f, st.Size, h)
+	if err != nil {
+		return nil, err
+	}
+	var enc string
+	if err := s.download(ctx, st, f, offset, h, opts, &enc); err != nil {
+		if errors.Is(err, ErrIntegrity) {
+			removePart(part)
+		}
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+	if identity(enc) && k == nil {
+		if err := os.Rename(part, path); err != nil {
+			return nil, err
+		}
+		return st, os.Remove(part + ".id")
+	}
+	if err := decodeFile(part, path, enc, k); err != nil {
+		if errors.Is(err, ErrIntegrity) {
+			removePart(part)
+		}
+		return nil, err
+	}
+	return st, removePart(part)
+}
+
+// stampPart makes sure the file part holds nothing but content of the
+// artifact with status st, as recorded in the file part+".id". A part file
+// recorded for another artifact or etag, or not recorded at all, is removed.
+func stampPart(part string, st *artifact.ArtifactStatusRT) error {
+	id := []byte(st.ID + "\n" + stringValue(st.Etag) + "\n")
+	if b, err := os.ReadFile(part + ".id"); err == nil && bytes.Equal(b, id) {
+		return nil
+	}
+	if err := os.Remove(part); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return writeFileAtomic(part+".id", id, 0644)
+}
+
+// removePart removes the file part and the record of its artifact.
+func removePart(part string) error {
+	err := os.Remove(part)
+	if ierr := os.Remove(part + ".id"); err == nil {
+		err = ierr
+	}
+	return err
+}
+
+// decodeFile writes the plain content of the file src, encrypted with k and
+// encoded with enc, to the file dst.
+func decodeFile(src, dst, enc string, k *contentKey) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	tmp := dst + ".tmp"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+	pw, finish := plainWriter(out, &enc, k)
+	_, err = io.Copy(pw, in)
+	if err = finish(err); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, dst)
+}
+
+// resumePart positions f after the content already downloaded to it and
+// feeds that content to h. A file longer than size is truncated, and a
+// complete one resumed from its last byte so that the encoding of the
+// content is learnt from the response.
+func resumePart(f *os.File, size *int64, h hash.Hash) (int64, error) {
+	fi, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	offset := fi.Size()
+	if size != nil && offset > *size {
+		if err := f.Truncate(0); err != nil {
+			return 0, err
+		}
+		offset = 0
+	} else if size != nil && offset == *size && offset > 0 {
+		if err := f.Truncate(offset - 1); err != nil {
+			return 0, err
+		}
+		offset--
+	}
+	if h != nil {
+		if _, err := io.CopyN(h, f, offset); err != nil {
+			return 0, fmt.Errorf("ivcap: reading partial download: %w", err)
+		}
+	}
+	_, err = f.Seek(offset, io.SeekStart)
+	return offset, err
+}
+
+// download writes the content of the artifact with status st to w as
+// stored, starting at offset. h, if not nil, holds the MD5 of the content
+// before offset. enc is set to the Content-Encoding of the content before
+// anything is written to w.
+func (s *ArtifactsClient) download(ctx context.Context, st *artifact.ArtifactStatusRT, w io.Writer, offset int64, h hash.Hash, opts *ResumableDownload, enc *string) error {
+	var o ResumableDownload
+	if opts != nil {
+		o = *opts
+	}
+	if o.MaxRetries <= 0 {
+		o.MaxRetries = 5
+	}
+	if st.DataHref == nil {
+		return fmt.Errorf("ivcap: artifact %s has no data link", st.ID)
+	}
+	href, err := s.c.resolve(*st.DataHref)
+	if err != nil {
+		return err
+	}
+	size := int64(-1)
+	if st.Size != nil {
+		size = *st.Size
+	}
+	etag := stringValue(st.Etag)
+	if h != nil {
+		w = io.MultiWriter(w, h)
+	}
+
+	retry := DefaultRetryPolicy()
+	failures := 0
+	for size < 0 || offset < size {
+		n, err := s.fetch(ctx, &blobRange{href: href, etag: etag, offset: offset, ifNoneMatch: o.ifNoneMatch}, w, size, o.Progress, enc)
+		if err == errNotModified {
+			return err
+		}
+		o.ifNoneMatch = ""
+		offset += n
+		if err == nil {
+			break
+		}
+		if n > 0 {
+			failures = 0
+		}
+		failures++
+		if errors.Is(err, ErrIntegrity) || !resumable(err) || failures > o.MaxRetries {
+			return err
+		}
+		t := time.NewTimer(retry.backoff(failures))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return err
+		case <-t.C:
+		}
+	}
+	if size >= 0 && offset != size {
+		return fmt.Errorf("%w: received %d bytes, expected %d", ErrIntegrity, offset, size)
+	}
+	if h != nil {
+		want := md5Etag.FindStringSubmatch(etag)[1]
+		if got := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(got, want) {
+			return fmt.Errorf("%w: MD5 %s, expected etag %s", ErrIntegrity, got, etag)
+		}
+	}
+	return nil
+}
+
+// fetch requests the content of r and copies it to w. It returns the number
+// of bytes written, which may be positive even if the transfer failed.
+func (s *ArtifactsClient) fetch(ctx context.Context, r *blobRange, w io.Writer, size int64, progress func(offset, size int64), enc *string) (int64, error) {
+	res, err := invoke[*blobResponse](ctx, s.blob, r)
+	if err != nil {
+		return 0, err
+	}
+	defer res.body.Close()
+	if res.notModified {
+		return 0, errNotModified
+	}
+	*enc = res.encoding
+	body := &bodyReader{r: res.body}
+	if r.offset > 0 && !res.partial {
+		// The range was ignored: skip the content already received, unless
+		// it changed in between.
+		if r.etag != "" && res.etag != "" && res.etag != r.etag {
+			return 0, fmt.Errorf("%w: etag changed from %s to %s", ErrIntegrity, r.etag, res.etag)
+		}
+		if _, err := io.CopyN(io.Discard, body, r.offset); err != nil {
+			return 0, body.wrap(err)
+		}
+	}
+	if progress != nil {
+		w = &progressWriter{w: w, offset: r.offset, size: size, progress: progress}
+	}
+	n, err := io.Copy(w, body)
+	if err != nil {
+		return n, body.wrap(err)
+	}
+	return n, nil
+}
+
+// bodyReader records the errors reading a response body, to tell them apart
+// from the errors writing the content.
+type bodyReader struct {
+	r   io.Reader
+	err error
+}
+
+func (b *bodyReader) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	if err != nil && err != io.EOF {
+		b.err = err
+	}
+	return n, err
+}
+
+// wrap returns err as a transport error if it was returned reading the body.
+func (b *bodyReader) wrap(err error) error {
+	if b.err == nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		} else {
+			return err
+		}
+	}
+	return ivcaperr.Wrap(goahttp.ErrRequestError("artifact", "download", err), "artifact", "download", 0, "")
+}
+
+// blobEndpoint returns the endpoint requesting the content described by a
+// *blobRange.
+func (s *ArtifactsClient) blobEndpoint() goa.Endpoint {
+	return func(ctx context.Context, v any) (any, error) {
+		r := v.(*blobRange)
+		req, err := http.NewRequestWithContext(ctx, "GET", r.href, nil)
+		if err != nil {
+			return nil, goahttp.ErrInvalidURL("artifact", "download", r.href, err)
+		}
+		if r.offset > 0 {
+			req.Header.Set("Range", fmt.Sprintf("bytes=%d-", r.offset))
+			if r.etag != "" {
+				req.Header.Set("If-Range", r.etag)
+			}
+		}
+		// Asking for the encodings explicitly stops the transport from
+		// decoding gzip on its own, which would defeat the size and etag
+		// checks.
+		req.Header.Set("Accept-Encoding", acceptEncoding())
+		if r.ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", r.ifNoneMatch)
+		}
+		resp, err := s.c.doerFor(req.URL).Do(req)
+		if err != nil {
+			return nil, goahttp.ErrRequestError("artifact", "download", err)
+		}
+		switch resp.StatusCode {
+		case http.StatusNotModified:
+			return &blobResponse{body: resp.Body, notModified: true}, nil
+		case http.StatusOK, http.StatusPartialContent:
+			return &blobResponse{
+				body:     resp.Body,
+				etag:     resp.Header.Get("Etag"),
+				encoding: resp.Header.Get("Content-Encoding"),
+				partial:  resp.StatusCode == http.StatusPartialContent,
+			}, nil
+		default:
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			return nil, goahttp.ErrInvalidResponse("artifact", "download", resp.StatusCode, string(body))
+		}
+	}
+}
+
+// progressWriter reports the progress of the content written to w.
+type progressWriter struct {
+	w        io.Writer
+	offset   int64
+	size     int64
+	progress func(offset, size int64)
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.offset += int64(n)
+	p.progress(p.offset, p.size)
+	return n, err
+}
+
+// newEtagHash returns the hash verifying content against etag, or nil if the
+// etag is not the MD5 of the content.
+func newEtagHash(etag *string) hash.Hash {
+	if etag == nil || !md5Etag.MatchString(*etag) {
+		return nil
+	}
+	return md5.New()
+}
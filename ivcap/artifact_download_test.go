@@ -0,0 +1,268 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	artifact "github.com/ivcap-works/ivcap-core-api/gen/artifact"
+	"github.com/ivcap-works/ivcap-core-api/ivcap"
+	"github.com/ivcap-works/ivcap-core-api/ivcaptest"
+)
+
+// authRecorder serves h, recording the Authorization headers received. It
+// stands in for storage handing out presigned links when token is set,
+// authorizing the requests without a token with it.
+type authRecorder struct {
+	h     http.Handler
+	token string
+	mu    sync.Mutex
+	auth  []string
+}
+
+func (a *authRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	a.auth = append(a.auth, r.Header.Get("Authorization"))
+	a.mu.Unlock()
+	if a.token != "" && r.Header.Get("Authorization") == "" {
+		r.Header.Set("Authorization", "Bearer "+a.token)
+	}
+	a.h.ServeHTTP(w, r)
+}
+
+func TestDataLinkAuthorization(t *testing.T) {
+	tests := []struct {
+		name string
+		// storage puts the data and upload links on another host.
+		storage  bool
+		wantAuth bool
+	}{
+		{name: "deployment host", wantAuth: true},
+		{name: "other host", storage: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := "urn:ivcap:user:alice"
+			d := ivcaptest.New()
+			api := &authRecorder{h: d.Handler()}
+			apiSrv := httptest.NewServer(api)
+			defer apiSrv.Close()
+			links := api
+			d.BaseURL = apiSrv.URL
+			if tt.storage {
+				links = &authRecorder{h: d.Handler(), token: ivcaptest.Token(user)}
+				storageSrv := httptest.NewServer(links)
+				defer storageSrv.Close()
+				d.BaseURL = storageSrv.URL
+			}
+			c, err := ivcap.New(apiSrv.URL, ivcap.WithJWT(ivcaptest.Token(user)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := context.Background()
+			content := strings.Repeat("data", 1000)
+			st, err := c.Artifacts.UploadResumable(ctx, &artifact.UploadPayload{}, strings.NewReader(content), int64(len(content)), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var buf bytes.Buffer
+			if _, err := c.Artifacts.Download(ctx, st.ID, &buf, nil); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != content {
+				t.Errorf("downloaded %d bytes, want %d", buf.Len(), len(content))
+			}
+			if len(links.auth) == 0 {
+				t.Fatal("no requests on the links")
+			}
+			for _, a := range links.auth {
+				if got := a != ""; got != tt.wantAuth {
+					t.Errorf("Authorization %q sent on a link, want sent: %v", a, tt.wantAuth)
+				}
+			}
+		})
+	}
+}
+
+// blobFaults serves h, handling the requests for the content of artifacts
+// listed in faults by their number, counting from 1, and recording their
+// Range and If-Range headers.
+type blobFaults struct {
+	h      http.Handler
+	faults map[int]string
+	mu     sync.Mutex
+	ranges []string
+}
+
+func (b *blobFaults) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasSuffix(r.URL.Path, "/blob") {
+		b.h.ServeHTTP(w, r)
+		return
+	}
+	b.mu.Lock()
+	b.ranges = append(b.ranges, r.Header.Get("Range")+" "+r.Header.Get("If-Range"))
+	fault := b.faults[len(b.ranges)]
+	b.mu.Unlock()
+	switch fault {
+	case "cut":
+		// The connection drops half way through the content.
+		rec := httptest.NewRecorder()
+		b.h.ServeHTTP(rec, r)
+		for k, v := range rec.Header() {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(rec.Code)
+		w.Write(rec.Body.Bytes()[:rec.Body.Len()/2])
+		w.(http.Flusher).Flush()
+		panic(http.ErrAbortHandler)
+	case "error":
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	case "ignore range":
+		r.Header.Del("Range")
+		b.h.ServeHTTP(w, r)
+	case "changed":
+		// The content changed, so If-Range makes the range ignored.
+		r.Header.Del("Range")
+		rec := httptest.NewRecorder()
+		b.h.ServeHTTP(rec, r)
+		for k, v := range rec.Header() {
+			w.Header()[k] = v
+		}
+		w.Header().Set("Etag", `"changed"`)
+		w.WriteHeader(rec.Code)
+		w.Write(rec.Body.Bytes())
+	default:
+		b.h.ServeHTTP(w, r)
+	}
+}
+
+func TestDownloadResume(t *testing.T) {
+	content := strings.Repeat("0123456789", 1000)
+	tests := []struct {
+		name   string
+		faults map[int]string
+		// part is the content of a ".part" file left by an earlier call to
+		// DownloadFile, if not empty, and foreign whether it was left by
+		// the download of another artifact.
+		part    string
+		foreign bool
+		// failWrite makes writing the content fail.
+		failWrite bool
+		// ranges lists the Range headers sent, each with If-Range set to the
+		// etag, and wantErr the error the download fails with.
+		ranges  []string
+		wantErr error
+	}{
+		{name: "complete", ranges: []string{""}},
+		{name: "cut", faults: map[int]string{1: "cut"}, ranges: []string{"", "bytes=5000-"}},
+		{name: "cut twice", faults: map[int]string{1: "cut", 2: "cut"}, ranges: []string{"", "bytes=5000-", "bytes=7500-"}},
+		{name: "server error", faults: map[int]string{1: "error"}, ranges: []string{"", ""}},
+		{name: "range ignored", faults: map[int]string{1: "cut", 2: "ignore range"}, ranges: []string{"", "bytes=5000-"}},
+		{name: "content changed", faults: map[int]string{1: "cut", 2: "changed"}, ranges: []string{"", "bytes=5000-"}, wantErr: ivcap.ErrIntegrity},
+		{name: "write failed", failWrite: true, ranges: []string{""}, wantErr: errWrite},
+		{name: "part file", part: content[:3000], ranges: []string{"bytes=3000-"}},
+		{name: "complete part file", part: content, ranges: []string{"bytes=9999-"}},
+		{name: "corrupt part file", part: strings.Repeat("x", 3000), ranges: []string{"bytes=3000-"}, wantErr: ivcap.ErrIntegrity},
+		{name: "part file of another artifact", part: strings.Repeat("x", 3000), foreign: true, ranges: []string{""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := ivcaptest.New()
+			bf := &blobFaults{h: d.Handler(), faults: tt.faults}
+			srv := httptest.NewServer(bf)
+			defer srv.Close()
+			c, err := ivcap.New(srv.URL, ivcap.WithJWT(ivcaptest.Token("urn:ivcap:user:alice")))
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := context.Background()
+			up, err := c.Artifacts.Upload(ctx, &artifact.UploadPayload{}, strings.NewReader(content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			st, err := c.Artifacts.Read(ctx, up.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got string
+			switch {
+			case tt.failWrite:
+				_, err = c.Artifacts.Download(ctx, up.ID, failingWriter{}, nil)
+			case tt.part == "":
+				var buf bytes.Buffer
+				_, err = c.Artifacts.Download(ctx, up.ID, &buf, nil)
+				got = buf.String()
+			default:
+				path := filepath.Join(t.TempDir(), "content")
+				id := st.ID
+				if tt.foreign {
+					id = "urn:ivcap:artifact:other"
+				}
+				if err := os.WriteFile(path+".part.id", []byte(id+"\n"+*st.Etag+"\n"), 0644); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path+".part", []byte(tt.part), 0644); err != nil {
+					t.Fatal(err)
+				}
+				if _, err = c.Artifacts.DownloadFile(ctx, up.ID, path, nil); err == nil {
+					b, _ := os.ReadFile(path)
+					got = string(b)
+					if left, _ := filepath.Glob(path + ".part*"); len(left) > 0 {
+						t.Errorf("left %v behind", left)
+					}
+				}
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("error %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			} else if got != content {
+				t.Errorf("downloaded %d bytes, want %d", len(got), len(content))
+			}
+			want := make([]string, len(tt.ranges))
+			for i, r := range tt.ranges {
+				want[i] = " "
+				if r != "" {
+					want[i] = r + " " + *st.Etag
+				}
+			}
+			if !reflect.DeepEqual(bf.ranges, want) {
+				t.Errorf("sent Range and If-Range %q, want %q", bf.ranges, want)
+			}
+		})
+	}
+}
+
+var errWrite = errors.New("disk full")
+
+// failingWriter fails every write with errWrite.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errWrite
+}
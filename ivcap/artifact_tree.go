@@ -0,0 +1,326 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	artifact "github.com/ivcap-works/ivcap-core-api/gen/artifact"
+)
+
+// TreeUpload configures ArtifactsClient.UploadTree.
+type TreeUpload struct {
+	// Collection is the optional URN of the collection the artifacts are
+	// added to.
+	Collection string
+	// Policy is the optional URN of the policy controlling access to the
+	// artifacts.
+	Policy string
+	// Dedup uploads the files with UploadDeduplicated, reusing the artifacts
+	// with the same content.
+	Dedup bool
+	// Compression is the optional Content-Encoding, such as "gzip", the
+	// files are compressed with as by UploadCompressed. It is ignored with
+	// Dedup.
+	Compression string
+	// Concurrency is the number of files uploaded at the same time. It
+	// defaults to 4.
+	Concurrency int
+	// ManifestPath is the optional file the manifest is kept in. Files
+	// recorded in it with an unchanged size and modification time are not
+	// uploaded again, so an interrupted or repeated upload of the same tree
+	// only sends new and modified files.
+	ManifestPath string
+	// Progress is called after each file with its entry in the manifest, or
+	// the error uploading it.
+	Progress func(path string, e *ManifestEntry, err error)
+}
+
+// Manifest records the artifacts created for the files of a tree.
+type Manifest struct {
+	// Collection is the collection the artifacts were added to.
+	Collection string `json:"collection,omitempty"`
+	// Files maps the slash separated path of each file, relative to the root
+	// of the tree, to its artifact.
+	Files map[string]*ManifestEntry `json:"files"`
+}
+
+// ManifestEntry describes the artifact created for a file.
+type ManifestEntry struct {
+	ID       string    `json:"id"`
+	Size     int64     `json:"size"`
+	Etag     string    `json:"etag,omitempty"`
+	MimeType string    `json:"mime-type,omitempty"`
+	ModTime  time.Time `json:"mod-time"`
+}
+
+// treeFile is a regular file found in the tree.
+type treeFile struct {
+	path string
+	rel  string
+	info fs.FileInfo
+}
+
+// UploadTree uploads all regular files below the directory root as
+// artifacts, Concurrency at a time. Each artifact is named by the slash
+// separated path of its file relative to root, and its content type is
+// guessed from the file extension or, failing that, sniffed from its
+// content. The first failure stops the upload; the manifest returned, and
+// saved to ManifestPath, lists the files uploaded until then. opts may be
+// nil.
+func (s *ArtifactsClient) UploadTree(ctx context.Context, root string, opts *TreeUpload) (*Manifest, error) {
+	var o TreeUpload
+	if opts != nil {
+		o = *opts
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = 4
+	}
+	m, err := LoadManifest(o.ManifestPath)
+	if err != nil {
+		return nil, err
+	}
+	if m.Collection != o.Collection {
+		// Artifacts of another collection cannot be reused.
+		m = &Manifest{Files: map[string]*ManifestEntry{}}
+	}
+	m.Collection = o.Collection
+	files, err := walkTree(root, o.ManifestPath)
+	if err != nil {
+		return nil, err
+	}
+	// The files to send are picked before the workers update m.
+	var changed []*treeFile
+	for _, f := range files {
+		if e := m.Files[f.rel]; e == nil || e.Size != f.info.Size() || !e.ModTime.Equal(f.info.ModTime()) {
+			changed = append(changed, f)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		mu       sync.Mutex
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	work := make(chan *treeFile)
+	for i := 0; i < o.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for f := range work {
+				e, err := s.uploadFile(ctx, f, &o)
+				mu.Lock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = fmt.Errorf("ivcap: uploading %s: %w", f.rel, err)
+						cancel()
+					}
+				} else {
+					m.Files[f.rel] = e
+					if serr := m.Save(o.ManifestPath); serr != nil && firstErr == nil {
+						firstErr = serr
+						cancel()
+					}
+				}
+				mu.Unlock()
+				if o.Progress != nil {
+					o.Progress(f.rel, e, err)
+				}
+			}
+		}()
+	}
+feed:
+	for _, f := range changed {
+		select {
+		case work <- f:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(work)
+	wg.Wait()
+	if firstErr == nil {
+		firstErr = ctx.Err()
+	}
+	return m, firstErr
+}
+
+// uploadFile uploads f as a new artifact.
+func (s *ArtifactsClient) uploadFile(ctx context.Context, f *treeFile, o *TreeUpload) (*ManifestEntry, error) {
+	file, err := os.Open(f.path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	ct, err := sniffType(file, f.path)
+	if err != nil {
+		return nil, err
+	}
+	size, err := contentLength(f.info.Size())
+	if err != nil {
+		return nil, err
+	}
+	p := &artifact.UploadPayload{ContentType: &ct, ContentLength: size, Name: &f.rel}
+	if o.Collection != "" {
+		p.Collection = &o.Collection
+	}
+	if o.Policy != "" {
+		p.Policy = &o.Policy
+	}
+	var id string
+	var etag *string
+	if o.Dedup {
+		st, _, err := s.UploadDeduplicated(ctx, p, file)
+		if err != nil {
+			return nil, err
+		}
+		id, etag = st.ID, st.Etag
+	} else {
+		res, err := s.UploadCompressed(ctx, p, io.NopCloser(file), o.Compression)
+		if err != nil {
+			return nil, err
+		}
+		id, etag = res.ID, res.Etag
+	}
+	return &ManifestEntry{
+		ID:       id,
+		Size:     f.info.Size(),
+		Etag:     stringValue(etag),
+		MimeType: ct,
+		ModTime:  f.info.ModTime(),
+	}, nil
+}
+
+// sniffType returns the content type of file, guessed from the extension of
+// name or sniffed from its first 512 bytes. The file is rewound.
+func sniffType(file io.ReadSeeker, name string) (string, error) {
+	if ct := mime.TypeByExtension(filepath.Ext(name)); ct != "" {
+		return ct, nil
+	}
+	buf := make([]byte, 512)
+	n, err := io.ReadFull(file, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	return http.DetectContentType(buf[:n]), nil
+}
+
+// walkTree lists the regular files below root in lexical order, leaving out
+// the manifest itself.
+func walkTree(root, manifest string) ([]*treeFile, error) {
+	skip := ""
+	if manifest != "" {
+		skip, _ = filepath.Abs(manifest)
+	}
+	var files []*treeFile
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		if abs, _ := filepath.Abs(p); abs == skip {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		files = append(files, &treeFile{path: p, rel: filepath.ToSlash(rel), info: info})
+		return nil
+	})
+	return files, err
+}
+
+// LoadManifest reads the manifest at path. It returns an empty manifest if
+// path is empty or the file does not exist.
+func LoadManifest(path string) (*Manifest, error) {
+	m := &Manifest{Files: map[string]*ManifestEntry{}}
+	if path == "" {
+		return m, nil
+	}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ivcap: reading manifest: %w", err)
+	}
+	if err := json.Unmarshal(b, m); err != nil {
+		return nil, fmt.Errorf("ivcap: decoding manifest %s: %w", path, err)
+	}
+	if m.Files == nil {
+		m.Files = map[string]*ManifestEntry{}
+	}
+	return m, nil
+}
+
+// Save writes m to path, unless path is empty. The file is replaced
+// atomically.
+func (m *Manifest) Save(path string) error {
+	if path == "" {
+		return nil
+	}
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, append(b, '\n'), 0644); err != nil {
+		return fmt.Errorf("ivcap: writing manifest: %w", err)
+	}
+	return nil
+}
+
+// writeFileAtomic writes b with permissions perm to a temporary file next to
+// path, which is then renamed to path, so that path never holds partial
+// content, even with several writers.
+func writeFileAtomic(path string, b []byte, perm fs.FileMode) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(b)
+	if err == nil {
+		err = f.Chmod(perm)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
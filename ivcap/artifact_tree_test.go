@@ -0,0 +1,376 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	artifact "github.com/ivcap-works/ivcap-core-api/gen/artifact"
+	"github.com/ivcap-works/ivcap-core-api/ivcap"
+	"github.com/ivcap-works/ivcap-core-api/ivcaptest"
+)
+
+// uploadRecorder serves h, recording the names of the artifacts uploaded
+// and the largest number of uploads in flight at once. Uploads of the names
+// in fail are refused, and each upload takes at least delay.
+type uploadRecorder struct {
+	h     http.Handler
+	fail  map[string]bool
+	delay time.Duration
+
+	mu       sync.Mutex
+	names    []string
+	inFlight int
+	maxPar   int
+}
+
+func (u *uploadRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" || r.URL.Path != "/1/artifacts" {
+		u.h.ServeHTTP(w, r)
+		return
+	}
+	name := r.Header.Get("X-Name")
+	u.mu.Lock()
+	u.names = append(u.names, name)
+	u.inFlight++
+	if u.inFlight > u.maxPar {
+		u.maxPar = u.inFlight
+	}
+	u.mu.Unlock()
+	defer func() {
+		u.mu.Lock()
+		u.inFlight--
+		u.mu.Unlock()
+	}()
+	time.Sleep(u.delay)
+	if u.fail[name] {
+		http.Error(w, "refused", http.StatusBadRequest)
+		return
+	}
+	u.h.ServeHTTP(w, r)
+}
+
+// uploaded returns the sorted names uploaded since the last call.
+func (u *uploadRecorder) uploaded() []string {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	names := u.names
+	u.names = nil
+	sort.Strings(names)
+	return names
+}
+
+// newTreeClient returns a client for a new deployment served through u.
+func newTreeClient(t *testing.T, u *uploadRecorder) (*ivcap.Client, *ivcaptest.Deployment) {
+	t.Helper()
+	d := ivcaptest.New()
+	u.h = d.Handler()
+	srv := httptest.NewServer(u)
+	t.Cleanup(srv.Close)
+	c, err := ivcap.New(srv.URL, ivcap.WithJWT(ivcaptest.Token("urn:ivcap:user:alice")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, d
+}
+
+// writeTree creates the files at the slash separated paths of files below
+// a new directory, and returns it.
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for p, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(p))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+var testTree = map[string]string{
+	"a.txt":     "alpha",
+	"b.json":    `{"b": true}`,
+	"sub/c.bin": "\x00\x01\x02",
+}
+
+func TestUploadTree(t *testing.T) {
+	const coll = "urn:ivcap:collection:test"
+	tests := []struct {
+		name string
+		// change is applied to the tree between the first and the second
+		// upload, made to collection.
+		change     func(t *testing.T, root string)
+		collection string
+		// uploaded lists the files sent again by the second upload.
+		uploaded []string
+	}{
+		{name: "unchanged", collection: coll},
+		{
+			name: "content changed",
+			change: func(t *testing.T, root string) {
+				if err := os.WriteFile(filepath.Join(root, "b.json"), []byte(`{"b": false}`), 0644); err != nil {
+					t.Fatal(err)
+				}
+			},
+			collection: coll,
+			uploaded:   []string{"b.json"},
+		},
+		{
+			name: "touched",
+			change: func(t *testing.T, root string) {
+				later := time.Now().Add(time.Hour)
+				if err := os.Chtimes(filepath.Join(root, "sub", "c.bin"), later, later); err != nil {
+					t.Fatal(err)
+				}
+			},
+			collection: coll,
+			uploaded:   []string{"sub/c.bin"},
+		},
+		{
+			name: "file added",
+			change: func(t *testing.T, root string) {
+				if err := os.WriteFile(filepath.Join(root, "d.txt"), []byte("delta"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			},
+			collection: coll,
+			uploaded:   []string{"d.txt"},
+		},
+		{name: "other collection", collection: "urn:ivcap:collection:other", uploaded: []string{"a.txt", "b.json", "sub/c.bin"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &uploadRecorder{}
+			c, _ := newTreeClient(t, u)
+			ctx := context.Background()
+			root := writeTree(t, testTree)
+			manifest := filepath.Join(t.TempDir(), "manifest.json")
+
+			first, err := c.Artifacts.UploadTree(ctx, root, &ivcap.TreeUpload{Collection: coll, ManifestPath: manifest})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := u.uploaded(), []string{"a.txt", "b.json", "sub/c.bin"}; !reflect.DeepEqual(got, want) {
+				t.Fatalf("first upload sent %q, want %q", got, want)
+			}
+			checkCollection(t, c, coll, first)
+			if tt.change != nil {
+				tt.change(t, root)
+			}
+
+			second, err := c.Artifacts.UploadTree(ctx, root, &ivcap.TreeUpload{Collection: tt.collection, ManifestPath: manifest})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := u.uploaded(); !reflect.DeepEqual(got, tt.uploaded) {
+				t.Errorf("second upload sent %q, want %q", got, tt.uploaded)
+			}
+			for rel, e := range second.Files {
+				resent := false
+				for _, name := range tt.uploaded {
+					resent = resent || name == rel
+				}
+				if prev := first.Files[rel]; !resent && (prev == nil || prev.ID != e.ID) {
+					t.Errorf("%s: artifact %s, want %v kept", rel, e.ID, prev)
+				}
+			}
+			saved, err := ivcap.LoadManifest(manifest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !sameFiles(saved, second) || saved.Collection != tt.collection {
+				t.Errorf("saved manifest %+v differs from the one returned %+v", saved, second)
+			}
+			checkCollection(t, c, tt.collection, second)
+		})
+	}
+}
+
+// sameFiles reports whether a and b list the same files and artifacts, as
+// loaded manifests only keep the wall clock of the modification times.
+func sameFiles(a, b *ivcap.Manifest) bool {
+	if len(a.Files) != len(b.Files) {
+		return false
+	}
+	for rel, e := range a.Files {
+		f := b.Files[rel]
+		if f == nil || e.ID != f.ID || e.Size != f.Size || e.Etag != f.Etag || !e.ModTime.Equal(f.ModTime) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkCollection checks that the artifacts of m, and only those, are in
+// collection, named after their files.
+func checkCollection(t *testing.T, c *ivcap.Client, collection string, m *ivcap.Manifest) {
+	t.Helper()
+	filter := fmt.Sprintf("collection = '%s'", collection)
+	items, err := c.Artifacts.ListIter(context.Background(), &artifact.ListPayload{Filter: &filter}).Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := map[string]string{}
+	for _, a := range items {
+		if a.Name != nil {
+			names[a.ID] = *a.Name
+		}
+	}
+	for rel, e := range m.Files {
+		if names[e.ID] != rel {
+			t.Errorf("%s: artifact %s not in %s under its name: %q", rel, e.ID, collection, names[e.ID])
+		}
+	}
+	if len(items) < len(m.Files) {
+		t.Errorf("%d artifacts in %s, want at least %d", len(items), collection, len(m.Files))
+	}
+}
+
+func TestUploadTreeFailure(t *testing.T) {
+	u := &uploadRecorder{fail: map[string]bool{"b.json": true}}
+	c, _ := newTreeClient(t, u)
+	ctx := context.Background()
+	root := writeTree(t, testTree)
+	manifest := filepath.Join(t.TempDir(), "manifest.json")
+	var failed []string
+	opts := &ivcap.TreeUpload{
+		Concurrency:  1,
+		ManifestPath: manifest,
+		Progress: func(path string, e *ivcap.ManifestEntry, err error) {
+			if err != nil {
+				failed = append(failed, path)
+			}
+		},
+	}
+
+	m, err := c.Artifacts.UploadTree(ctx, root, opts)
+	if err == nil || !strings.Contains(err.Error(), "b.json") {
+		t.Fatalf("got %v, want the failure of b.json", err)
+	}
+	if !reflect.DeepEqual(failed, []string{"b.json"}) {
+		t.Errorf("Progress reported failures of %q, want b.json", failed)
+	}
+	// The files are uploaded in lexical order, one at a time, so the upload
+	// stops before sub/c.bin.
+	if _, ok := m.Files["a.txt"]; !ok || len(m.Files) != 1 {
+		t.Errorf("manifest lists %v, want a.txt only", m.Files)
+	}
+	saved, err := ivcap.LoadManifest(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sameFiles(saved, m) {
+		t.Errorf("saved manifest lists %v, want %v", saved.Files, m.Files)
+	}
+
+	// Once the failure is gone, the upload resumes with the files left.
+	u.uploaded()
+	u.mu.Lock()
+	u.fail = nil
+	u.mu.Unlock()
+	if _, err := c.Artifacts.UploadTree(ctx, root, opts); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := u.uploaded(), []string{"b.json", "sub/c.bin"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("resumed upload sent %q, want %q", got, want)
+	}
+}
+
+func TestUploadTreeConcurrency(t *testing.T) {
+	files := map[string]string{}
+	for i := 0; i < 12; i++ {
+		files[fmt.Sprintf("f%02d.txt", i)] = strings.Repeat("x", i)
+	}
+	for _, limit := range []int{1, 3} {
+		t.Run(fmt.Sprint(limit), func(t *testing.T) {
+			u := &uploadRecorder{delay: 20 * time.Millisecond}
+			c, _ := newTreeClient(t, u)
+			m, err := c.Artifacts.UploadTree(context.Background(), writeTree(t, files), &ivcap.TreeUpload{Concurrency: limit})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(m.Files) != len(files) {
+				t.Errorf("uploaded %d files, want %d", len(m.Files), len(files))
+			}
+			if u.maxPar != limit {
+				t.Errorf("%d uploads at once, want %d", u.maxPar, limit)
+			}
+		})
+	}
+}
+
+func TestManifestSave(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "manifest.json")
+	const writers = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			m := &ivcap.Manifest{Files: map[string]*ivcap.ManifestEntry{}}
+			for j := 0; j <= i*100; j++ {
+				m.Files[fmt.Sprintf("f%d", j)] = &ivcap.ManifestEntry{ID: fmt.Sprintf("urn:ivcap:artifact:%d", i)}
+			}
+			errs <- m.Save(path)
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The manifest saved last is complete: all its entries name the same
+	// artifact and their number matches it.
+	m, err := ivcap.LoadManifest(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var i int
+	if _, err := fmt.Sscanf(m.Files["f0"].ID, "urn:ivcap:artifact:%d", &i); err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Files) != i*100+1 {
+		t.Errorf("manifest of writer %d has %d files, want %d", i, len(m.Files), i*100+1)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("files left in the directory: %v", entries)
+	}
+	if fi, err := os.Stat(path); err != nil || fi.Mode().Perm() != 0644 {
+		t.Errorf("mode %v, %v, want 0644", fi.Mode(), err)
+	}
+}
@@ -0,0 +1,332 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	artifact "github.com/ivcap-works/ivcap-core-api/gen/artifact"
+	"github.com/ivcap-works/ivcap-core-api/ivcaperr"
+	goahttp "goa.design/goa/v3/http"
+	goa "goa.design/goa/v3/pkg"
+)
+
+// tusVersion is the version of the TUS resumable upload protocol spoken.
+const tusVersion = "1.0.0"
+
+// ResumableUpload configures ArtifactsClient.UploadResumable.
+type ResumableUpload struct {
+	// ChunkSize is the size of the content sent in each PATCH request. It
+	// defaults to 16 MiB.
+	ChunkSize int64
+	// StatePath is the optional file the state of the upload is kept in. A
+	// process uploading the same content with the same StatePath resumes the
+	// upload instead of starting over. The content is recognised by a hash
+	// of all of it, so it is read once more before the upload starts. The
+	// file is removed once the upload completes.
+	StatePath string
+	// MaxRetries is the number of times in a row a failed chunk is retried
+	// from the offset reported by the deployment. It defaults to 5.
+	MaxRetries int
+	// Progress is called after each chunk with the number of bytes uploaded
+	// so far and the total size.
+	Progress func(offset, size int64)
+}
+
+// uploadState is the state of a resumable upload persisted in
+// ResumableUpload.StatePath.
+type uploadState struct {
+	ID          string `json:"id"`
+	Location    string `json:"location"`
+	Size        int64  `json:"size"`
+	Fingerprint string `json:"fingerprint"`
+	Offset      int64  `json:"offset"`
+}
+
+// tusChunk is the payload of the TUS PATCH endpoint.
+type tusChunk struct {
+	location string
+	offset   int64
+	data     []byte
+}
+
+// UploadResumable creates a new artifact with size bytes read from content
+// using the TUS resumable upload protocol: the artifact is created empty and
+// its content sent in chunks to the returned Location. Chunks failing with a
+// transport error or a server error are sent again from the offset reported
+// by the deployment. opts may be nil.
+func (s *ArtifactsClient) UploadResumable(ctx context.Context, p *artifact.UploadPayload, content io.ReadSeeker, size int64, opts *ResumableUpload) (*artifact.ArtifactStatusRT, error) {
+	var o ResumableUpload
+	if opts != nil {
+		o = *opts
+	}
+	if o.ChunkSize <= 0 {
+		o.ChunkSize = 16 << 20
+	}
+	if o.MaxRetries <= 0 {
+		o.MaxRetries = 5
+	}
+	var fp string
+	if o.StatePath != "" {
+		var err error
+		if fp, err = fingerprint(content, size); err != nil {
+			return nil, err
+		}
+	}
+	st, err := loadUploadState(o.StatePath)
+	if err != nil {
+		return nil, err
+	}
+	var offset int64
+	if st != nil && st.Size == size && st.Fingerprint == fp {
+		offset, err = s.tusOffset(ctx, st.Location)
+		if errors.Is(err, ivcaperr.ErrNotFound) {
+			st = nil
+		} else if err != nil {
+			return nil, err
+		}
+	} else {
+		st = nil
+	}
+	if st == nil {
+		if st, err = s.createResumable(ctx, p, size, fp); err != nil {
+			return nil, err
+		}
+		if err := saveUploadState(o.StatePath, st); err != nil {
+			return nil, err
+		}
+	}
+
+	retry := DefaultRetryPolicy()
+	buf := make([]byte, o.ChunkSize)
+	failures := 0
+	for offset < size {
+		n := o.ChunkSize
+		if size-offset < n {
+			n = size - offset
+		}
+		if _, err := content.Seek(offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+		if _, err := io.ReadFull(content, buf[:n]); err != nil {
+			return nil, fmt.Errorf("ivcap: reading content at offset %d: %w", offset, err)
+		}
+		next, err := invoke[int64](ctx, s.tusPatch, &tusChunk{location: st.Location, offset: offset, data: buf[:n]})
+		if err != nil {
+			failures++
+			if !resumable(err) || failures > o.MaxRetries {
+				return nil, err
+			}
+			t := time.NewTimer(retry.backoff(failures))
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return nil, err
+			case <-t.C:
+			}
+			if off, err := s.tusOffset(ctx, st.Location); err == nil {
+				offset = off
+			}
+			continue
+		}
+		failures = 0
+		offset = next
+		st.Offset = offset
+		if err := saveUploadState(o.StatePath, st); err != nil {
+			return nil, err
+		}
+		if o.Progress != nil {
+			o.Progress(offset, size)
+		}
+	}
+	if o.StatePath != "" {
+		if err := os.Remove(o.StatePath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("ivcap: removing upload state: %w", err)
+		}
+	}
+	return s.Read(ctx, st.ID)
+}
+
+// createResumable creates the empty artifact the content of a resumable
+// upload is sent to.
+func (s *ArtifactsClient) createResumable(ctx context.Context, p *artifact.UploadPayload, size int64, fp string) (*uploadState, error) {
+	var q artifact.UploadPayload
+	if p != nil {
+		q = *p
+	}
+	length, err := contentLength(size)
+	if err != nil {
+		return nil, err
+	}
+	zero, version := 0, tusVersion
+	if q.XContentType == nil {
+		q.XContentType = q.ContentType
+	}
+	q.ContentType = nil
+	q.ContentLength = &zero
+	q.XContentLength = length
+	q.UploadLength = length
+	q.TusResumable = &version
+	res, err := s.Upload(ctx, &q, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := s.c.resolve(res.Location)
+	if err != nil {
+		return nil, err
+	}
+	return &uploadState{ID: res.ID, Location: loc, Size: size, Fingerprint: fp}, nil
+}
+
+// tusOffset returns the number of bytes the deployment received for the
+// upload at location.
+func (s *ArtifactsClient) tusOffset(ctx context.Context, location string) (int64, error) {
+	return invoke[int64](ctx, s.tusHead, location)
+}
+
+// tusHeadEndpoint returns the endpoint sending TUS HEAD requests for the
+// location given as payload.
+func (s *ArtifactsClient) tusHeadEndpoint() goa.Endpoint {
+	return func(ctx context.Context, v any) (any, error) {
+		req, err := http.NewRequestWithContext(ctx, "HEAD", v.(string), nil)
+		if err != nil {
+			return nil, goahttp.ErrInvalidURL("artifact", "tus-head", v.(string), err)
+		}
+		req.Header.Set("Tus-Resumable", tusVersion)
+		return s.tusDo(req, "tus-head", http.StatusOK)
+	}
+}
+
+// tusPatchEndpoint returns the endpoint sending a *tusChunk in a TUS PATCH
+// request.
+func (s *ArtifactsClient) tusPatchEndpoint() goa.Endpoint {
+	return func(ctx context.Context, v any) (any, error) {
+		c := v.(*tusChunk)
+		req, err := http.NewRequestWithContext(ctx, "PATCH", c.location, bytes.NewReader(c.data))
+		if err != nil {
+			return nil, goahttp.ErrInvalidURL("artifact", "tus-patch", c.location, err)
+		}
+		req.Header.Set("Tus-Resumable", tusVersion)
+		req.Header.Set("Content-Type", "application/offset+octet-stream")
+		req.Header.Set("Upload-Offset", strconv.FormatInt(c.offset, 10))
+		return s.tusDo(req, "tus-patch", http.StatusNoContent)
+	}
+}
+
+// tusDo sends req and returns the Upload-Offset of the response, which must
+// have the status want.
+func (s *ArtifactsClient) tusDo(req *http.Request, method string, want int) (any, error) {
+	resp, err := s.c.doerFor(req.URL).Do(req)
+	if err != nil {
+		return nil, goahttp.ErrRequestError("artifact", method, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != want {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, goahttp.ErrInvalidResponse("artifact", method, resp.StatusCode, string(body))
+	}
+	offset, err := strconv.ParseInt(resp.Header.Get("Upload-Offset"), 10, 64)
+	if err != nil {
+		return nil, goahttp.ErrDecodingError("artifact", method, err)
+	}
+	return offset, nil
+}
+
+// resumable reports whether a transfer which failed with err is worth
+// resuming: the transport failed, the service is unavailable or it responded
+// with a 5xx status. Local failures, such as writing the content to disk, are
+// of kind ErrInternal as well, but without a response.
+func resumable(err error) bool {
+	switch ivcaperr.KindOf(err) {
+	case ivcaperr.ErrTransport, ivcaperr.ErrUnavailable:
+		return true
+	}
+	var e *ivcaperr.Error
+	var ce *goahttp.ClientError
+	return errors.As(err, &e) && e.Status >= 500 && errors.As(err, &ce) && ce.Name == "invalid_response"
+}
+
+// resolve returns location as an absolute URL, resolving a relative one
+// against the base URL of the client.
+func (c *Client) resolve(location string) (string, error) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return "", fmt.Errorf("ivcap: invalid upload location %q: %w", location, err)
+	}
+	base := &url.URL{Scheme: c.scheme, Host: c.host}
+	return base.ResolveReference(u).String(), nil
+}
+
+// fingerprint returns a hash of the size and all size bytes of content.
+func fingerprint(content io.ReadSeeker, size int64) (string, error) {
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "%d:", size)
+	if _, err := io.CopyN(h, content, size); err != nil {
+		return "", fmt.Errorf("ivcap: reading content: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// loadUploadState reads the upload state at path. It returns nil if path is
+// empty or the file does not exist.
+func loadUploadState(path string) (*uploadState, error) {
+	if path == "" {
+		return nil, nil
+	}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ivcap: reading upload state: %w", err)
+	}
+	var st uploadState
+	if err := json.Unmarshal(b, &st); err != nil {
+		return nil, fmt.Errorf("ivcap: decoding upload state %s: %w", path, err)
+	}
+	return &st, nil
+}
+
+// saveUploadState writes st to path, unless path is empty. The file is
+// replaced atomically so a crash never leaves a truncated state behind.
+func saveUploadState(path string, st *uploadState) error {
+	if path == "" {
+		return nil
+	}
+	b, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, b, 0600); err != nil {
+		return fmt.Errorf("ivcap: writing upload state: %w", err)
+	}
+	return nil
+}
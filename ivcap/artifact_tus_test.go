@@ -0,0 +1,166 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	artifact "github.com/ivcap-works/ivcap-core-api/gen/artifact"
+	"github.com/ivcap-works/ivcap-core-api/ivcap"
+	"github.com/ivcap-works/ivcap-core-api/ivcaptest"
+)
+
+// patchFaults serves h, failing the PATCH requests listed in faults by their
+// number, counting from 1, and counting the bytes of the PATCH requests
+// reaching h.
+type patchFaults struct {
+	h       http.Handler
+	faults  map[int]string
+	mu      sync.Mutex
+	patches int
+	sent    int64
+}
+
+func (p *patchFaults) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "PATCH" {
+		p.h.ServeHTTP(w, r)
+		return
+	}
+	p.mu.Lock()
+	p.patches++
+	fault := p.faults[p.patches]
+	if fault != "error" {
+		p.sent += r.ContentLength
+	}
+	p.mu.Unlock()
+	switch fault {
+	case "error":
+		// The chunk is refused before it is stored.
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	case "lost":
+		// The chunk is stored but the response lost.
+		p.h.ServeHTTP(httptest.NewRecorder(), r)
+		http.Error(w, "bad gateway", http.StatusBadGateway)
+	default:
+		p.h.ServeHTTP(w, r)
+	}
+}
+
+func TestUploadResumable(t *testing.T) {
+	const chunk = 100
+	content := strings.Repeat("0123456789", 55)
+	tests := []struct {
+		name   string
+		faults map[int]string
+		// sent is the number of bytes expected to reach the deployment.
+		sent int64
+	}{
+		{name: "no fault", sent: int64(len(content))},
+		{name: "server error", faults: map[int]string{2: "error"}, sent: int64(len(content))},
+		{name: "lost response", faults: map[int]string{3: "lost"}, sent: int64(len(content))},
+		{name: "several faults", faults: map[int]string{1: "error", 2: "lost", 4: "error"}, sent: int64(len(content))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := ivcaptest.New()
+			pf := &patchFaults{h: d.Handler(), faults: tt.faults}
+			srv := httptest.NewServer(pf)
+			defer srv.Close()
+			c, err := ivcap.New(srv.URL, ivcap.WithJWT(ivcaptest.Token("urn:ivcap:user:alice")))
+			if err != nil {
+				t.Fatal(err)
+			}
+			st, err := c.Artifacts.UploadResumable(context.Background(), &artifact.UploadPayload{}, strings.NewReader(content), int64(len(content)), &ivcap.ResumableUpload{ChunkSize: chunk})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, _ := d.Artifacts.Content(st.ID); string(got) != content {
+				t.Errorf("content = %q, want %q", got, content)
+			}
+			if pf.sent != tt.sent {
+				t.Errorf("sent %d bytes, want %d", pf.sent, tt.sent)
+			}
+		})
+	}
+}
+
+func TestUploadResumableState(t *testing.T) {
+	const chunk = 256 << 10
+	content := bytes.Repeat([]byte("0123456789"), 110000)
+	changed := append([]byte(nil), content...)
+	changed[len(changed)-1] = 'x'
+	tests := []struct {
+		name string
+		// second is the content of the second upload, with the same size
+		// and leading bytes as the first.
+		second []byte
+		// sent is the number of bytes expected to reach the deployment.
+		sent int64
+	}{
+		{name: "same content", second: content, sent: int64(len(content))},
+		{name: "changed tail", second: changed, sent: 2*chunk + int64(len(changed))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := ivcaptest.New()
+			pf := &patchFaults{h: d.Handler()}
+			srv := httptest.NewServer(pf)
+			defer srv.Close()
+			c, err := ivcap.New(srv.URL, ivcap.WithJWT(ivcaptest.Token("urn:ivcap:user:alice")))
+			if err != nil {
+				t.Fatal(err)
+			}
+			state := filepath.Join(t.TempDir(), "upload.json")
+
+			// The first process stops after two chunks.
+			ctx, cancel := context.WithCancel(context.Background())
+			_, err = c.Artifacts.UploadResumable(ctx, &artifact.UploadPayload{}, bytes.NewReader(content), int64(len(content)), &ivcap.ResumableUpload{
+				ChunkSize: chunk,
+				StatePath: state,
+				Progress: func(offset, size int64) {
+					if offset >= 2*chunk {
+						cancel()
+					}
+				},
+			})
+			if err == nil {
+				t.Fatal("first upload completed")
+			}
+
+			// The second continues where it stopped, if the content is the
+			// same.
+			st, err := c.Artifacts.UploadResumable(context.Background(), &artifact.UploadPayload{}, bytes.NewReader(tt.second), int64(len(tt.second)), &ivcap.ResumableUpload{ChunkSize: chunk, StatePath: state})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, _ := d.Artifacts.Content(st.ID); !bytes.Equal(got, tt.second) {
+				t.Errorf("uploaded %d bytes differing from the content", len(got))
+			}
+			if pf.sent != tt.sent {
+				t.Errorf("sent %d bytes, want %d", pf.sent, tt.sent)
+			}
+			if m, _ := filepath.Glob(state); len(m) != 0 {
+				t.Errorf("state file %s left after the upload", state)
+			}
+		})
+	}
+}
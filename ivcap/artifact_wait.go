@@ -0,0 +1,193 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	artifact "github.com/ivcap-works/ivcap-core-api/gen/artifact"
+	"github.com/ivcap-works/ivcap-core-api/ivcaperr"
+)
+
+// ArtifactFailedError is returned when an artifact waited for ends up in the
+// error or unknown status, or stays partial without its upload progressing.
+type ArtifactFailedError struct {
+	// ID of the artifact.
+	ID string
+	// Status of the artifact.
+	Status string
+}
+
+// Error returns the error message.
+func (e *ArtifactFailedError) Error() string {
+	return fmt.Sprintf("ivcap: artifact %s ended in status %q", e.ID, e.Status)
+}
+
+// WaitPolicy configures how ArtifactsClient.WaitReady and WaitAllReady poll
+// the status of artifacts. The time spent waiting is bounded by the context.
+type WaitPolicy struct {
+	// InitialInterval is the delay before the second poll. It defaults to
+	// 500ms.
+	InitialInterval time.Duration
+	// MaxInterval caps the delay between two polls. It defaults to 10s.
+	MaxInterval time.Duration
+	// Multiplier is the factor the delay grows by after each poll. It
+	// defaults to 2.
+	Multiplier float64
+	// Concurrency is the number of artifacts WaitAllReady polls at the same
+	// time. It defaults to 8.
+	Concurrency int
+	// StallTimeout is how long an artifact may stay in the partial status
+	// without its size growing, as when its upload was abandoned. It
+	// defaults to 5m.
+	StallTimeout time.Duration
+	// MaxReadErrors is the number of consecutive polls failing with a
+	// transient error, such as ivcaperr.ErrUnavailable, tolerated before
+	// giving up. It defaults to 5.
+	MaxReadErrors int
+}
+
+// WaitReady polls the status of artifact id until it is ready and returns
+// it. An artifact in the error or unknown status, or which stays partial for
+// StallTimeout, fails with *ArtifactFailedError. opts may be nil.
+func (s *ArtifactsClient) WaitReady(ctx context.Context, id string, opts *WaitPolicy) (*artifact.ArtifactStatusRT, error) {
+	p := waitDefaults(opts)
+	delay := p.InitialInterval
+	var (
+		st       *artifact.ArtifactStatusRT
+		failures int
+		size     int64
+		progress time.Time
+	)
+	for {
+		cur, err := s.Read(ctx, id)
+		switch {
+		case err == nil:
+			st, failures = cur, 0
+		case transient(err) && ctx.Err() == nil && failures < p.MaxReadErrors:
+			failures++
+		default:
+			return st, err
+		}
+		if err == nil {
+			switch st.Status {
+			case "ready":
+				return st, nil
+			case "error", "unknown":
+				return st, &ArtifactFailedError{ID: id, Status: st.Status}
+			case "partial":
+				var n int64
+				if st.Size != nil {
+					n = *st.Size
+				}
+				if progress.IsZero() || n > size {
+					size, progress = n, time.Now()
+				} else if time.Since(progress) >= p.StallTimeout {
+					return st, &ArtifactFailedError{ID: id, Status: st.Status}
+				}
+			default:
+				progress = time.Time{}
+			}
+		}
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return st, ctx.Err()
+		case <-t.C:
+		}
+		if delay = time.Duration(float64(delay) * p.Multiplier); delay > p.MaxInterval {
+			delay = p.MaxInterval
+		}
+	}
+}
+
+// WaitAllReady waits for all the artifacts in ids to be ready, polling
+// Concurrency of them at the same time, and returns their status in the same
+// order. The first failure cancels the wait for the others. opts may be nil.
+func (s *ArtifactsClient) WaitAllReady(ctx context.Context, ids []string, opts *WaitPolicy) ([]*artifact.ArtifactStatusRT, error) {
+	p := waitDefaults(opts)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	res := make([]*artifact.ArtifactStatusRT, len(ids))
+	sem := make(chan struct{}, p.Concurrency)
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	for i, id := range ids {
+		wg.Add(1)
+		go func(i int, id string) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+			st, err := s.WaitReady(ctx, id, &p)
+			res[i] = st
+			if err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(i, id)
+	}
+	wg.Wait()
+	if firstErr == nil {
+		firstErr = ctx.Err()
+	}
+	return res, firstErr
+}
+
+// waitDefaults returns opts with the defaults of unset fields applied.
+func waitDefaults(opts *WaitPolicy) WaitPolicy {
+	var p WaitPolicy
+	if opts != nil {
+		p = *opts
+	}
+	if p.InitialInterval <= 0 {
+		p.InitialInterval = 500 * time.Millisecond
+	}
+	if p.MaxInterval <= 0 {
+		p.MaxInterval = 10 * time.Second
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = 2
+	}
+	if p.Concurrency <= 0 {
+		p.Concurrency = 8
+	}
+	if p.StallTimeout <= 0 {
+		p.StallTimeout = 5 * time.Minute
+	}
+	if p.MaxReadErrors <= 0 {
+		p.MaxReadErrors = 5
+	}
+	return p
+}
+
+// transient reports whether a request failing with err may succeed when
+// repeated.
+func transient(err error) bool {
+	return errors.Is(err, ivcaperr.ErrUnavailable) || errors.Is(err, ivcaperr.ErrTransport)
+}
@@ -0,0 +1,111 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	artifact "github.com/ivcap-works/ivcap-core-api/gen/artifact"
+	"github.com/ivcap-works/ivcap-core-api/ivcap"
+	"github.com/ivcap-works/ivcap-core-api/ivcaperr"
+	"github.com/ivcap-works/ivcap-core-api/ivcaptest"
+)
+
+// unavailable serves h, failing the first n GET requests with 503.
+type unavailable struct {
+	h http.Handler
+	n int32
+}
+
+func (u *unavailable) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == "GET" && atomic.AddInt32(&u.n, -1) >= 0 {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	u.h.ServeHTTP(w, r)
+}
+
+func TestWaitReady(t *testing.T) {
+	tests := []struct {
+		name   string
+		status string
+		// later is the status set after a while, if any.
+		later       string
+		unavailable int32
+		// want is the error kind, errFailed for *ArtifactFailedError.
+		want error
+	}{
+		{name: "ready", status: "ready"},
+		{name: "error", status: "error", want: errFailed},
+		{name: "unknown", status: "unknown", want: errFailed},
+		{name: "stalled", status: "partial", want: errFailed},
+		{name: "pending", status: "pending", later: "ready"},
+		{name: "partial", status: "partial", later: "ready"},
+		// MaxReadErrors is 3: as many errors in a row are tolerated, but
+		// not one more.
+		{name: "tolerated errors", status: "ready", unavailable: 3},
+		{name: "one error too many", status: "ready", unavailable: 4, want: ivcaperr.ErrUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := ivcaptest.New()
+			srv := httptest.NewServer(&unavailable{h: d.Handler(), n: tt.unavailable})
+			t.Cleanup(srv.Close)
+			c, err := ivcap.New(srv.URL, ivcap.WithJWT(ivcaptest.Token("urn:ivcap:user:alice")))
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			up, err := c.Artifacts.Upload(ctx, &artifact.UploadPayload{}, strings.NewReader("content"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			d.Artifacts.SetStatus(up.ID, tt.status)
+			if tt.later != "" {
+				time.AfterFunc(50*time.Millisecond, func() { d.Artifacts.SetStatus(up.ID, tt.later) })
+			}
+			stall := time.Second
+			if tt.later == "" {
+				stall = 20 * time.Millisecond
+			}
+			_, err = c.Artifacts.WaitReady(ctx, up.ID, &ivcap.WaitPolicy{
+				InitialInterval: 5 * time.Millisecond,
+				MaxInterval:     10 * time.Millisecond,
+				StallTimeout:    stall,
+				MaxReadErrors:   3,
+			})
+			var failed *ivcap.ArtifactFailedError
+			switch {
+			case tt.want == nil && err != nil:
+				t.Fatal(err)
+			case tt.want == errFailed && !errors.As(err, &failed):
+				t.Fatalf("error %v, want *ArtifactFailedError", err)
+			case tt.want != nil && tt.want != errFailed && !errors.Is(err, tt.want):
+				t.Fatalf("error %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+// errFailed stands for an *ArtifactFailedError in TestWaitReady.
+var errFailed = errors.New("artifact failed")
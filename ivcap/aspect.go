@@ -0,0 +1,163 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap
+
+import (
+	"context"
+	"net/http"
+
+	aspect "github.com/ivcap-works/ivcap-core-api/gen/aspect"
+	aspectc "github.com/ivcap-works/ivcap-core-api/http/aspect"
+	goahttp "goa.design/goa/v3/http"
+	goa "goa.design/goa/v3/pkg"
+)
+
+// AspectsClient gives typed access to the aspect service.
+type AspectsClient struct {
+	c       *Client
+	read    goa.Endpoint
+	list    goa.Endpoint
+	create  goa.Endpoint
+	update  goa.Endpoint
+	retract goa.Endpoint
+}
+
+func newAspectsClient(c *Client, enc func(*http.Request) goahttp.Encoder, dec func(*http.Response) goahttp.Decoder, restoreBody bool) *AspectsClient {
+	hc := aspectc.NewClient(c.scheme, c.host, c.doer, enc, dec, restoreBody)
+	return &AspectsClient{
+		c:       c,
+		read:    c.endpoint(aspect.ServiceName, "read", hc.Read()),
+		list:    c.endpoint(aspect.ServiceName, "list", hc.List()),
+		create:  c.endpoint(aspect.ServiceName, "create", hc.Create()),
+		update:  c.endpoint(aspect.ServiceName, "update", hc.Update()),
+		retract: c.endpoint(aspect.ServiceName, "retract", hc.Retract()),
+	}
+}
+
+// Read returns the aspect with the given ID. Content with a schema
+// registered with RegisterSchema is decoded into its type; if it does not
+// match, Read fails with a *ContentError.
+func (s *AspectsClient) Read(ctx context.Context, id string) (*aspect.AspectRT, error) {
+	res, err := s.readRaw(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := decodeContent(res.Schema, res.ID, &res.Content); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// readRaw returns the aspect with the given ID, leaving its content as read.
+func (s *AspectsClient) readRaw(ctx context.Context, id string) (*aspect.AspectRT, error) {
+	p := &aspect.ReadPayload{ID: id}
+	return invoke[*aspect.AspectRT](ctx, s.read, p)
+}
+
+// List returns a page of aspects. Content with a schema registered with
+// RegisterSchema is decoded into its type, and left as read if it does not
+// match; ListIter reports the *ContentError of such items.
+func (s *AspectsClient) List(ctx context.Context, p *aspect.ListPayload) (*aspect.AspectListRT, error) {
+	res, err := s.listRaw(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range res.Items {
+		decodeContent(a.Schema, a.ID, &a.Content)
+	}
+	return res, nil
+}
+
+// listRaw returns a page of aspects, leaving their content as read.
+func (s *AspectsClient) listRaw(ctx context.Context, p *aspect.ListPayload) (*aspect.AspectListRT, error) {
+	var q aspect.ListPayload
+	if p != nil {
+		q = *p
+	}
+	return invoke[*aspect.AspectListRT](ctx, s.list, &q)
+}
+
+// Create attaches a new aspect to an entity. Content with a schema
+// registered with RegisterSchema is checked against its type first, and
+// validated with Validate.
+func (s *AspectsClient) Create(ctx context.Context, p *aspect.CreatePayload) (*aspect.AspectIDRT, error) {
+	var q aspect.CreatePayload
+	if p != nil {
+		q = *p
+	}
+	if err := checkContent(q.Schema, q.Content); err != nil {
+		return nil, err
+	}
+	if err := s.Validate(ctx, q.Schema, q.Content); err != nil {
+		return nil, err
+	}
+	return invoke[*aspect.AspectIDRT](ctx, s.create, &q)
+}
+
+// Update creates a new aspect and retracts any existing aspect for the same
+// entity and schema. Content with a schema registered with RegisterSchema
+// is checked against its type first, and validated with Validate.
+func (s *AspectsClient) Update(ctx context.Context, p *aspect.UpdatePayload) (*aspect.AspectIDRT, error) {
+	var q aspect.UpdatePayload
+	if p != nil {
+		q = *p
+	}
+	if err := checkContent(q.Schema, q.Content); err != nil {
+		return nil, err
+	}
+	if err := s.Validate(ctx, q.Schema, q.Content); err != nil {
+		return nil, err
+	}
+	return invoke[*aspect.AspectIDRT](ctx, s.update, &q)
+}
+
+// Retract retracts the aspect with the given ID.
+func (s *AspectsClient) Retract(ctx context.Context, id string) error {
+	p := &aspect.RetractPayload{ID: id}
+	_, err := s.retract(ctx, p)
+	return err
+}
+
+// ListIter returns an iterator over all aspects matching p, following the
+// next page links. Content with a schema registered with RegisterSchema is
+// decoded into its type; if it does not match, the aspect is returned as read
+// and ItemErr returns a *ContentError.
+func (s *AspectsClient) ListIter(ctx context.Context, p *aspect.ListPayload) *Iterator[*aspect.AspectListItemRT] {
+	it := s.iter(ctx, p, s.listRaw)
+	it.check = func(a *aspect.AspectListItemRT) error {
+		return decodeContent(a.Schema, a.ID, &a.Content)
+	}
+	return it
+}
+
+// iter returns an iterator over the pages of aspects matching p returned by
+// list.
+func (s *AspectsClient) iter(ctx context.Context, p *aspect.ListPayload, list func(context.Context, *aspect.ListPayload) (*aspect.AspectListRT, error)) *Iterator[*aspect.AspectListItemRT] {
+	var q aspect.ListPayload
+	if p != nil {
+		q = *p
+	}
+	q.Limit = pageLimit(q.Limit)
+	return newIterator(ctx, func(ctx context.Context, page string) ([]*aspect.AspectListItemRT, string, error) {
+		if page != "" {
+			q.Page = &page
+		}
+		res, err := list(ctx, &q)
+		if err != nil {
+			return nil, "", err
+		}
+		return res.Items, nextPage(res.Links, func(l *aspect.LinkT) (string, string) { return l.Rel, l.Href }), nil
+	})
+}
@@ -0,0 +1,436 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	aspect "github.com/ivcap-works/ivcap-core-api/gen/aspect"
+)
+
+// exportConcurrency is the number of aspects Export reads at the same time.
+const exportConcurrency = 8
+
+// Actions of AspectImportResult.
+const (
+	// AspectImportCreate asserts a new aspect.
+	AspectImportCreate = "create"
+	// AspectImportReplace replaces the active aspect with the same entity
+	// and schema.
+	AspectImportReplace = "replace"
+	// AspectImportUnchanged leaves an active aspect with the same content as
+	// it is.
+	AspectImportUnchanged = "unchanged"
+)
+
+// AspectRecord is an aspect as written by AspectsClient.Export and read by
+// Import, one JSON object per line.
+type AspectRecord struct {
+	ID          string `json:"id,omitempty"`
+	Entity      string `json:"entity"`
+	Schema      string `json:"schema"`
+	Content     any    `json:"content"`
+	ContentType string `json:"content-type,omitempty"`
+	ValidFrom   string `json:"valid-from,omitempty"`
+	Replaces    string `json:"replaces,omitempty"`
+}
+
+// AspectImport configures AspectsClient.Import.
+type AspectImport struct {
+	// Concurrency is the number of records imported at the same time. It
+	// defaults to 4.
+	Concurrency int
+	// RateLimit caps the number of records imported per second. Zero
+	// means no limit.
+	RateLimit float64
+	// CheckpointPath is the optional file the records imported are recorded
+	// in, by a hash of their content. Running the import again skips them,
+	// so an interrupted import continues where it stopped even if lines were
+	// added, removed or reordered in between.
+	CheckpointPath string
+	// Replace replaces the active aspect with the same entity and schema
+	// when its content differs. By default the record is asserted next to
+	// it.
+	Replace bool
+	// DryRun only reports the action each record would take.
+	DryRun bool
+	// Policy is the optional URN of the policy controlling access to the
+	// aspects created.
+	Policy string
+	// Progress is called after each record with its result, or the error
+	// importing it.
+	Progress func(r *AspectImportResult, err error)
+}
+
+// AspectImportResult is the outcome of importing a record.
+type AspectImportResult struct {
+	// Line is the number of the line holding the record, starting at 1.
+	Line   int
+	Record *AspectRecord
+	// Action is AspectImportCreate, AspectImportReplace or
+	// AspectImportUnchanged.
+	Action string
+	// ID is the aspect created, or the one with the same content. It is
+	// empty for records created or replaced in a dry run.
+	ID string
+}
+
+// AspectImportReport counts the records imported by action.
+type AspectImportReport struct {
+	Created   int `json:"created"`
+	Replaced  int `json:"replaced"`
+	Unchanged int `json:"unchanged"`
+	// Skipped counts the records found in the checkpoint.
+	Skipped int `json:"skipped"`
+}
+
+// Export writes the aspects matching p as AspectRecord lines to w and
+// returns their number. The ID and Replaces of the records are those of the
+// aspects in this deployment. p may be nil to export all aspects.
+func (s *AspectsClient) Export(ctx context.Context, w io.Writer, p *aspect.ListPayload) (int, error) {
+	var q aspect.ListPayload
+	if p != nil {
+		q = *p
+	}
+	q.IncludeContent = nil
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type pending struct {
+		id   string
+		rec  *AspectRecord
+		done chan error
+	}
+	queue := make(chan *pending, exportConcurrency)
+	var listErr error
+	go func() {
+		defer close(queue)
+		it := s.iter(ctx, &q, s.listRaw)
+		for it.Next() {
+			id := it.Item().ID
+			pd := &pending{id: id, rec: &AspectRecord{}, done: make(chan error, 1)}
+			select {
+			case queue <- pd:
+			case <-ctx.Done():
+				return
+			}
+			go func() {
+				a, err := s.readRaw(ctx, id)
+				if err == nil {
+					*pd.rec = AspectRecord{
+						ID:          a.ID,
+						Entity:      a.Entity,
+						Schema:      a.Schema,
+						Content:     a.Content,
+						ContentType: a.ContentType,
+						ValidFrom:   a.ValidFrom,
+						Replaces:    stringValue(a.Replaces),
+					}
+				}
+				pd.done <- err
+			}()
+		}
+		listErr = it.Err()
+	}()
+
+	enc := json.NewEncoder(w)
+	n := 0
+	for pd := range queue {
+		if err := <-pd.done; err != nil {
+			return n, fmt.Errorf("ivcap: exporting %s: %w", pd.id, err)
+		}
+		if err := enc.Encode(pd.rec); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, listErr
+}
+
+// Import asserts the aspects read as AspectRecord lines from r, Concurrency
+// at a time. Records with the same entity and schema are imported in the
+// order they are read. Each record is compared with the active aspects of
+// its entity and schema: one with the same content leaves it unchanged,
+// otherwise it is created, or replaces the active aspect with Replace set.
+// The ID, ValidFrom and Replaces of the records are ignored. The first
+// failure stops the import; the report returned counts the records imported
+// until then. opts may be nil.
+func (s *AspectsClient) Import(ctx context.Context, r io.Reader, opts *AspectImport) (*AspectImportReport, error) {
+	var o AspectImport
+	if opts != nil {
+		o = *opts
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = 4
+	}
+	cp, err := loadImportCheckpoint(o.CheckpointPath)
+	if err != nil {
+		return nil, err
+	}
+	var tick <-chan time.Time
+	if o.RateLimit > 0 {
+		t := time.NewTicker(time.Duration(float64(time.Second) / o.RateLimit))
+		defer t.Stop()
+		tick = t.C
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		mu       sync.Mutex
+		firstErr error
+		wg       sync.WaitGroup
+		report   AspectImportReport
+	)
+	fail := func(err error) {
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+	// Records are sent to the worker chosen by their entity and schema, so
+	// that the records of an aspect do not race each other, and records
+	// with the same key are marked done in the order they are read.
+	type job struct {
+		*AspectImportResult
+		key string
+	}
+	work := make([]chan job, o.Concurrency)
+	for i := range work {
+		work[i] = make(chan job)
+		wg.Add(1)
+		go func(work <-chan job) {
+			defer wg.Done()
+			for res := range work {
+				if tick != nil {
+					select {
+					case <-tick:
+					case <-ctx.Done():
+						continue
+					}
+				}
+				err := s.importRecord(ctx, res.AspectImportResult, &o)
+				mu.Lock()
+				if err != nil {
+					fail(fmt.Errorf("ivcap: importing line %d: %w", res.Line, err))
+				} else {
+					switch res.Action {
+					case AspectImportCreate:
+						report.Created++
+					case AspectImportReplace:
+						report.Replaced++
+					default:
+						report.Unchanged++
+					}
+					if !o.DryRun {
+						if serr := cp.mark(res.key, o.CheckpointPath); serr != nil {
+							fail(serr)
+						}
+					}
+				}
+				mu.Unlock()
+				if o.Progress != nil {
+					o.Progress(res.AspectImportResult, err)
+				}
+			}
+		}(work[i])
+	}
+
+	br := bufio.NewReader(r)
+	seen := map[string]int{}
+feed:
+	for line := 1; ; line++ {
+		b, err := br.ReadBytes('\n')
+		if len(bytes.TrimSpace(b)) > 0 {
+			rec := &AspectRecord{}
+			if derr := json.Unmarshal(b, rec); derr != nil {
+				mu.Lock()
+				fail(fmt.Errorf("ivcap: line %d: %w", line, derr))
+				mu.Unlock()
+				break
+			}
+			key, kerr := recordKey(rec)
+			if kerr != nil {
+				mu.Lock()
+				fail(fmt.Errorf("ivcap: line %d: %w", line, kerr))
+				mu.Unlock()
+				break
+			}
+			seen[key]++
+			mu.Lock()
+			skip := cp.done(key, seen[key])
+			if skip {
+				report.Skipped++
+			}
+			mu.Unlock()
+			if !skip {
+				h := fnv.New32a()
+				io.WriteString(h, rec.Entity+"\x00"+rec.Schema)
+				select {
+				case work[h.Sum32()%uint32(len(work))] <- job{&AspectImportResult{Line: line, Record: rec}, key}:
+				case <-ctx.Done():
+					break feed
+				}
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			mu.Lock()
+			fail(err)
+			mu.Unlock()
+			break
+		}
+	}
+	for _, c := range work {
+		close(c)
+	}
+	wg.Wait()
+	if firstErr == nil {
+		firstErr = ctx.Err()
+	}
+	return &report, firstErr
+}
+
+// importRecord decides on the action for res and, unless in a dry run,
+// takes it.
+func (s *AspectsClient) importRecord(ctx context.Context, res *AspectImportResult, o *AspectImport) error {
+	rec := res.Record
+	if rec.Entity == "" || rec.Schema == "" {
+		return errors.New("missing entity or schema")
+	}
+	include := true
+	it := s.iter(ctx, &aspect.ListPayload{Entity: &rec.Entity, Schema: &rec.Schema, IncludeContent: &include}, s.listRaw)
+	content := jsonValue(rec.Content)
+	active := 0
+	for it.Next() {
+		a := it.Item()
+		if a.Schema != rec.Schema {
+			continue
+		}
+		active++
+		if reflect.DeepEqual(jsonValue(a.Content), content) {
+			res.Action, res.ID = AspectImportUnchanged, a.ID
+			return nil
+		}
+	}
+	if err := it.Err(); err != nil {
+		return err
+	}
+	res.Action = AspectImportCreate
+	if o.Replace && active > 0 {
+		res.Action = AspectImportReplace
+	}
+	if o.DryRun {
+		return nil
+	}
+	ct := rec.ContentType
+	if ct == "" {
+		ct = "application/json"
+	}
+	var policy *string
+	if o.Policy != "" {
+		policy = &o.Policy
+	}
+	var id *aspect.AspectIDRT
+	var err error
+	if res.Action == AspectImportReplace {
+		id, err = s.Update(ctx, &aspect.UpdatePayload{Entity: rec.Entity, Schema: rec.Schema, Content: rec.Content, ContentType: ct, Policy: policy})
+	} else {
+		id, err = s.Create(ctx, &aspect.CreatePayload{Entity: rec.Entity, Schema: rec.Schema, Content: rec.Content, ContentType: ct, Policy: policy})
+	}
+	if err != nil {
+		return err
+	}
+	res.ID = id.ID
+	return nil
+}
+
+// recordKey returns the key of rec in an import checkpoint, the SHA-256 hash
+// of its JSON encoding. The keys of the content objects are sorted, so the
+// same record has the same key however it was formatted.
+func recordKey(rec *AspectRecord) (string, error) {
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// importCheckpoint records the records of an import which are done.
+type importCheckpoint struct {
+	// Records counts the records done by key, as the same record may be
+	// imported more than once, say to revert an aspect to an earlier
+	// content.
+	Records map[string]int `json:"records"`
+}
+
+// loadImportCheckpoint reads the checkpoint saved at path, returning an
+// empty one if path is empty or does not exist.
+func loadImportCheckpoint(path string) (*importCheckpoint, error) {
+	cp := &importCheckpoint{}
+	if path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("ivcap: reading checkpoint: %w", err)
+		}
+		if err == nil {
+			if err := json.Unmarshal(b, cp); err != nil {
+				return nil, fmt.Errorf("ivcap: reading checkpoint %s: %w", path, err)
+			}
+		}
+	}
+	if cp.Records == nil {
+		cp.Records = map[string]int{}
+	}
+	return cp, nil
+}
+
+// done reports whether the n-th record with key is done, counting from 1.
+func (cp *importCheckpoint) done(key string, n int) bool {
+	return n <= cp.Records[key]
+}
+
+// mark records one more record with key as done and saves the checkpoint to
+// path.
+func (cp *importCheckpoint) mark(key, path string) error {
+	cp.Records[key]++
+	if path == "" {
+		return nil
+	}
+	b, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, append(b, '\n'), 0644); err != nil {
+		return fmt.Errorf("ivcap: writing checkpoint: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ivcap-works/ivcap-core-api/ivcap"
+)
+
+// record returns an import line for the test aspect of entity with the
+// content {"v": v}.
+func record(entity string, v int) string {
+	return fmt.Sprintf(`{"entity": %q, "schema": %q, "content": {"v": %d}}`, entity, testSchema, v)
+}
+
+func TestImportCheckpoint(t *testing.T) {
+	a, b, c := record("urn:ivcap:entity:a", 1), record("urn:ivcap:entity:b", 1), record("urn:ivcap:entity:c", 1)
+	a2 := record("urn:ivcap:entity:a", 2)
+	tests := []struct {
+		name    string
+		first   []string
+		second  []string
+		replace bool
+		want    ivcap.AspectImportReport
+	}{
+		{
+			name:   "same lines",
+			first:  []string{a, b, c},
+			second: []string{a, b, c},
+			want:   ivcap.AspectImportReport{Skipped: 3},
+		},
+		{
+			name:   "reordered and added",
+			first:  []string{a, b},
+			second: []string{c, b, a, record("urn:ivcap:entity:d", 1)},
+			want:   ivcap.AspectImportReport{Created: 2, Skipped: 2},
+		},
+		{
+			name:   "reformatted",
+			first:  []string{a},
+			second: []string{`{"content":{"v":1},"schema":"` + testSchema + `","entity":"urn:ivcap:entity:a"}`},
+			want:   ivcap.AspectImportReport{Skipped: 1},
+		},
+		{
+			name:   "removed",
+			first:  []string{a, b, c},
+			second: []string{a, c},
+			want:   ivcap.AspectImportReport{Skipped: 2},
+		},
+		{
+			name:    "reverted",
+			first:   []string{a, a2},
+			second:  []string{a, a2, a},
+			replace: true,
+			want:    ivcap.AspectImportReport{Replaced: 1, Skipped: 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newTestClient(t, "urn:ivcap:user:alice")
+			ctx := context.Background()
+			opts := &ivcap.AspectImport{
+				CheckpointPath: filepath.Join(t.TempDir(), "import.ckpt"),
+				Replace:        tt.replace,
+			}
+			if _, err := c.Aspects.Import(ctx, strings.NewReader(strings.Join(tt.first, "\n")), opts); err != nil {
+				t.Fatal(err)
+			}
+			got, err := c.Aspects.Import(ctx, strings.NewReader(strings.Join(tt.second, "\n")), opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *got != tt.want {
+				t.Errorf("report = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,355 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	aspect "github.com/ivcap-works/ivcap-core-api/gen/aspect"
+)
+
+// urnPattern matches the URNs referenced in aspect content.
+var urnPattern = regexp.MustCompile(`(?i)^urn:[a-z0-9][a-z0-9-]{0,31}:\S+$`)
+
+// GraphTraversal configures AspectsClient.Graph.
+type GraphTraversal struct {
+	// Depth is the number of hops followed from the root entity. It
+	// defaults to 1.
+	Depth int
+	// Refs maps schemas to the JSON paths of their content holding
+	// references, such as "$.artifacts[*]" or "$.inputs[*].id". The paths
+	// support member names, indices and the '*' wildcard. The content of
+	// aspects with other schemas is searched for references in all its
+	// strings.
+	Refs map[string][]string
+	// Follow optionally restricts the entities referenced which are
+	// visited. Entities not followed do not appear in the graph.
+	Follow func(urn string) bool
+	// MaxNodes bounds the number of entities in the graph. It defaults to
+	// 1000.
+	MaxNodes int
+	// Concurrency is the number of entities whose aspects are listed at the
+	// same time. It defaults to 4.
+	Concurrency int
+}
+
+// EntityGraph is the graph of entities reachable from a root entity through
+// the references in their aspects.
+type EntityGraph struct {
+	Root string `json:"root"`
+	// Nodes lists the entities in the order they were reached.
+	Nodes []*GraphNode `json:"nodes"`
+	Edges []*GraphEdge `json:"edges"`
+}
+
+// GraphNode is an entity of an EntityGraph.
+type GraphNode struct {
+	URN string `json:"urn"`
+	// Depth is the number of hops from the root entity.
+	Depth int `json:"depth"`
+	// Aspects lists the aspects of the entity. It is empty for the entities
+	// at the maximum depth, whose aspects are not listed.
+	Aspects []*aspect.AspectListItemRT `json:"aspects,omitempty"`
+}
+
+// GraphEdge is a reference from the aspect of an entity to another entity.
+type GraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Aspect is the ID of the aspect holding the reference.
+	Aspect string `json:"aspect"`
+	// Schema of the aspect.
+	Schema string `json:"schema"`
+	// Path is the JSON path of the reference in the content of the aspect.
+	Path string `json:"path"`
+}
+
+// Graph lists the aspects of entity root and of the entities they
+// reference, breadth first, up to opts.Depth hops away. Each entity is
+// visited once, so reference cycles are followed no further. opts may be
+// nil.
+func (s *AspectsClient) Graph(ctx context.Context, root string, opts *GraphTraversal) (*EntityGraph, error) {
+	var o GraphTraversal
+	if opts != nil {
+		o = *opts
+	}
+	if o.Depth <= 0 {
+		o.Depth = 1
+	}
+	if o.MaxNodes <= 0 {
+		o.MaxNodes = 1000
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = 4
+	}
+	g := &EntityGraph{Root: root}
+	seen := map[string]bool{root: true}
+	level := []*GraphNode{{URN: root}}
+	g.Nodes = append(g.Nodes, level...)
+	for depth := 0; depth < o.Depth && len(level) > 0; depth++ {
+		if err := s.listNodes(ctx, level, o.Concurrency); err != nil {
+			return nil, err
+		}
+		var next []*GraphNode
+		for _, n := range level {
+			for _, a := range n.Aspects {
+				for _, r := range aspectRefs(a, o.Refs[a.Schema]) {
+					if r.urn == n.URN || (o.Follow != nil && !o.Follow(r.urn)) {
+						continue
+					}
+					if !seen[r.urn] {
+						if len(g.Nodes) >= o.MaxNodes {
+							continue
+						}
+						seen[r.urn] = true
+						nn := &GraphNode{URN: r.urn, Depth: depth + 1}
+						g.Nodes = append(g.Nodes, nn)
+						next = append(next, nn)
+					}
+					g.Edges = append(g.Edges, &GraphEdge{From: n.URN, To: r.urn, Aspect: a.ID, Schema: a.Schema, Path: r.path})
+				}
+			}
+		}
+		level = next
+	}
+	return g, nil
+}
+
+// listNodes lists the aspects of nodes, concurrency at a time.
+func (s *AspectsClient) listNodes(ctx context.Context, nodes []*GraphNode, concurrency int) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		mu       sync.Mutex
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	work := make(chan *GraphNode)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range work {
+				include := true
+				items, err := s.iter(ctx, &aspect.ListPayload{Entity: &n.URN, IncludeContent: &include}, s.listRaw).Collect()
+				if err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = fmt.Errorf("ivcap: listing aspects of %s: %w", n.URN, err)
+						cancel()
+					}
+					mu.Unlock()
+					continue
+				}
+				n.Aspects = items
+			}
+		}()
+	}
+feed:
+	for _, n := range nodes {
+		select {
+		case work <- n:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(work)
+	wg.Wait()
+	if firstErr == nil {
+		firstErr = ctx.Err()
+	}
+	return firstErr
+}
+
+// aspectRef is a reference found in the content of an aspect.
+type aspectRef struct {
+	urn  string
+	path string
+}
+
+// aspectRefs returns the references in the content of a at paths, or in all
+// its strings if paths is nil, in the order they appear.
+func aspectRefs(a *aspect.AspectListItemRT, paths []string) []aspectRef {
+	var refs []aspectRef
+	var walk func(path string, v any)
+	walk = func(path string, v any) {
+		switch v := v.(type) {
+		case string:
+			if urnPattern.MatchString(v) {
+				refs = append(refs, aspectRef{urn: v, path: path})
+			}
+		case []any:
+			for i, e := range v {
+				walk(path+"["+strconv.Itoa(i)+"]", e)
+			}
+		case map[string]any:
+			keys := make([]string, 0, len(v))
+			for k := range v {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				walk(path+"."+k, v[k])
+			}
+		}
+	}
+	content := jsonValue(a.Content)
+	if paths == nil {
+		walk("$", content)
+		return refs
+	}
+	for _, p := range paths {
+		for _, m := range selectPath(content, p) {
+			if s, ok := m.value.(string); ok && urnPattern.MatchString(s) {
+				refs = append(refs, aspectRef{urn: s, path: m.path})
+			}
+		}
+	}
+	return refs
+}
+
+// pathMatch is a value selected by a JSON path.
+type pathMatch struct {
+	path  string
+	value any
+}
+
+// selectPath returns the values of v selected by the JSON path p, made of
+// member names (".name"), indices ("[0]") and wildcards (".*" or "[*]").
+func selectPath(v any, p string) []pathMatch {
+	matches := []pathMatch{{path: "$", value: v}}
+	rest := strings.TrimPrefix(p, "$")
+	for rest != "" {
+		var step string
+		switch {
+		case strings.HasPrefix(rest, "["):
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil
+			}
+			step, rest = strings.Trim(rest[1:end], `'"`), rest[end+1:]
+		case strings.HasPrefix(rest, "."):
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			step, rest = rest[:end], rest[end:]
+		default:
+			return nil
+		}
+		var next []pathMatch
+		for _, m := range matches {
+			switch c := m.value.(type) {
+			case []any:
+				for i, e := range c {
+					if step == "*" || step == strconv.Itoa(i) {
+						next = append(next, pathMatch{path: m.path + "[" + strconv.Itoa(i) + "]", value: e})
+					}
+				}
+			case map[string]any:
+				if step != "*" {
+					if e, ok := c[step]; ok {
+						next = append(next, pathMatch{path: m.path + "." + step, value: e})
+					}
+					continue
+				}
+				keys := make([]string, 0, len(c))
+				for k := range c {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+				for _, k := range keys {
+					next = append(next, pathMatch{path: m.path + "." + k, value: c[k]})
+				}
+			}
+		}
+		matches = next
+	}
+	return matches
+}
+
+// DOT writes the graph in the Graphviz DOT language, labelling the edges
+// with the schema of the aspect holding the reference.
+func (g *EntityGraph) DOT(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("digraph entities {\n")
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&b, "  %s;\n", strconv.Quote(n.URN))
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %s -> %s [label=%s];\n", strconv.Quote(e.From), strconv.Quote(e.To), strconv.Quote(e.Schema))
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Mermaid writes the graph as a Mermaid flowchart, labelling the edges with
+// the schema of the aspect holding the reference.
+func (g *EntityGraph) Mermaid(w io.Writer) error {
+	ids := make(map[string]string, len(g.Nodes))
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for i, n := range g.Nodes {
+		ids[n.URN] = "n" + strconv.Itoa(i)
+		fmt.Fprintf(&b, "  %s[\"%s\"]\n", ids[n.URN], mermaidEscape(n.URN))
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %s -->|\"%s\"| %s\n", ids[e.From], mermaidEscape(e.Schema), ids[e.To])
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// mermaidEscape escapes the quotes in a Mermaid label.
+func mermaidEscape(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
+
+// JSONLD writes the graph as a JSON-LD document: a node object for each
+// entity, linked to the entities it references by properties named after
+// the schemas of the aspects holding the references.
+func (g *EntityGraph) JSONLD(w io.Writer) error {
+	nodes := make([]map[string]any, 0, len(g.Nodes))
+	index := make(map[string]map[string]any, len(g.Nodes))
+	for _, n := range g.Nodes {
+		obj := map[string]any{"@id": n.URN}
+		index[n.URN] = obj
+		nodes = append(nodes, obj)
+	}
+	for _, e := range g.Edges {
+		obj := index[e.From]
+		refs, _ := obj[e.Schema].([]map[string]string)
+		dup := false
+		for _, r := range refs {
+			dup = dup || r["@id"] == e.To
+		}
+		if !dup {
+			obj[e.Schema] = append(refs, map[string]string{"@id": e.To})
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(map[string]any{"@graph": nodes})
+}
@@ -0,0 +1,214 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	aspect "github.com/ivcap-works/ivcap-core-api/gen/aspect"
+	"github.com/ivcap-works/ivcap-core-api/ivcap"
+)
+
+const (
+	linkSchema = "urn:ivcap:schema:test.link.1"
+	refsSchema = "urn:ivcap:schema:test.refs.1"
+)
+
+func TestGraph(t *testing.T) {
+	// a links to b, which links to c, which links back to a. a also
+	// references d through an input and e in a note.
+	aspects := []struct {
+		entity, schema string
+		content        any
+	}{
+		{"a", linkSchema, map[string]any{"target": "urn:ivcap:entity:b", "label": "not a reference"}},
+		{"a", refsSchema, map[string]any{"inputs": []any{map[string]any{"id": "urn:ivcap:entity:d"}}, "note": "urn:ivcap:entity:e"}},
+		{"b", linkSchema, map[string]any{"target": "urn:ivcap:entity:c"}},
+		{"c", linkSchema, map[string]any{"target": "urn:ivcap:entity:a"}},
+	}
+	refs := map[string][]string{refsSchema: {"$.inputs[*].id"}}
+	tests := []struct {
+		name string
+		opts *ivcap.GraphTraversal
+		// nodes lists the entities reached as "name depth aspects", and
+		// edges the references as "from -> to path", both sorted.
+		nodes []string
+		edges []string
+	}{
+		{
+			name:  "default depth",
+			opts:  &ivcap.GraphTraversal{Refs: refs},
+			nodes: []string{"a 0 2", "b 1 0", "d 1 0"},
+			edges: []string{"a -> b $.target", "a -> d $.inputs[0].id"},
+		},
+		{
+			name:  "all strings",
+			opts:  nil,
+			nodes: []string{"a 0 2", "b 1 0", "d 1 0", "e 1 0"},
+			edges: []string{"a -> b $.target", "a -> d $.inputs[0].id", "a -> e $.note"},
+		},
+		{
+			name:  "depth 2",
+			opts:  &ivcap.GraphTraversal{Depth: 2, Refs: refs},
+			nodes: []string{"a 0 2", "b 1 1", "c 2 0", "d 1 0"},
+			edges: []string{"a -> b $.target", "a -> d $.inputs[0].id", "b -> c $.target"},
+		},
+		{
+			name:  "cycle",
+			opts:  &ivcap.GraphTraversal{Depth: 10, Refs: refs},
+			nodes: []string{"a 0 2", "b 1 1", "c 2 1", "d 1 0"},
+			edges: []string{"a -> b $.target", "a -> d $.inputs[0].id", "b -> c $.target", "c -> a $.target"},
+		},
+		{
+			name:  "max nodes",
+			opts:  &ivcap.GraphTraversal{Depth: 10, Refs: refs, MaxNodes: 3},
+			nodes: []string{"a 0 2", "b 1 1", "d 1 0"},
+			edges: []string{"a -> b $.target", "a -> d $.inputs[0].id"},
+		},
+		{
+			name: "follow",
+			opts: &ivcap.GraphTraversal{Depth: 10, Refs: refs, Follow: func(urn string) bool {
+				return urn != "urn:ivcap:entity:d"
+			}},
+			nodes: []string{"a 0 2", "b 1 1", "c 2 1"},
+			edges: []string{"a -> b $.target", "b -> c $.target", "c -> a $.target"},
+		},
+	}
+	c, _ := newTestClient(t, "urn:ivcap:user:alice")
+	ctx := context.Background()
+	for _, a := range aspects {
+		_, err := c.Aspects.Create(ctx, &aspect.CreatePayload{Entity: "urn:ivcap:entity:" + a.entity, Schema: a.schema, Content: a.content, ContentType: "application/json"})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := c.Aspects.Graph(ctx, "urn:ivcap:entity:a", tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var nodes, edges []string
+			for _, n := range g.Nodes {
+				nodes = append(nodes, fmt.Sprintf("%s %d %d", entityName(n.URN), n.Depth, len(n.Aspects)))
+			}
+			for _, e := range g.Edges {
+				edges = append(edges, fmt.Sprintf("%s -> %s %s", entityName(e.From), entityName(e.To), e.Path))
+			}
+			sort.Strings(nodes)
+			sort.Strings(edges)
+			if g.Nodes[0].URN != "urn:ivcap:entity:a" {
+				t.Errorf("first node %s, want the root", g.Nodes[0].URN)
+			}
+			if !reflect.DeepEqual(nodes, tt.nodes) {
+				t.Errorf("nodes = %q, want %q", nodes, tt.nodes)
+			}
+			if !reflect.DeepEqual(edges, tt.edges) {
+				t.Errorf("edges = %q, want %q", edges, tt.edges)
+			}
+		})
+	}
+}
+
+func entityName(urn string) string {
+	return strings.TrimPrefix(urn, "urn:ivcap:entity:")
+}
+
+func TestGraphRender(t *testing.T) {
+	g := &ivcap.EntityGraph{
+		Root: "urn:x:a",
+		Nodes: []*ivcap.GraphNode{
+			{URN: "urn:x:a"},
+			{URN: `urn:x:"b"`, Depth: 1},
+		},
+		Edges: []*ivcap.GraphEdge{
+			{From: "urn:x:a", To: `urn:x:"b"`, Schema: "urn:s:1", Path: "$.x"},
+			{From: "urn:x:a", To: `urn:x:"b"`, Schema: "urn:s:1", Path: "$.y"},
+			{From: `urn:x:"b"`, To: "urn:x:a", Schema: "urn:s:2", Path: "$.z"},
+		},
+	}
+	tests := []struct {
+		name   string
+		render func(*bytes.Buffer) error
+		want   string
+	}{
+		{
+			name:   "DOT",
+			render: func(b *bytes.Buffer) error { return g.DOT(b) },
+			want: `digraph entities {
+  "urn:x:a";
+  "urn:x:\"b\"";
+  "urn:x:a" -> "urn:x:\"b\"" [label="urn:s:1"];
+  "urn:x:a" -> "urn:x:\"b\"" [label="urn:s:1"];
+  "urn:x:\"b\"" -> "urn:x:a" [label="urn:s:2"];
+}
+`,
+		},
+		{
+			name:   "Mermaid",
+			render: func(b *bytes.Buffer) error { return g.Mermaid(b) },
+			want: `flowchart LR
+  n0["urn:x:a"]
+  n1["urn:x:#quot;b#quot;"]
+  n0 -->|"urn:s:1"| n1
+  n0 -->|"urn:s:1"| n1
+  n1 -->|"urn:s:2"| n0
+`,
+		},
+		{
+			// The references repeated by several paths are listed once.
+			name:   "JSONLD",
+			render: func(b *bytes.Buffer) error { return g.JSONLD(b) },
+			want: `{
+  "@graph": [
+    {
+      "@id": "urn:x:a",
+      "urn:s:1": [
+        {
+          "@id": "urn:x:\"b\""
+        }
+      ]
+    },
+    {
+      "@id": "urn:x:\"b\"",
+      "urn:s:2": [
+        {
+          "@id": "urn:x:a"
+        }
+      ]
+    }
+  ]
+}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := tt.render(&b); err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", b.String(), tt.want)
+			}
+		})
+	}
+}
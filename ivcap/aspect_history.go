@@ -0,0 +1,314 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	aspect "github.com/ivcap-works/ivcap-core-api/gen/aspect"
+)
+
+// maxHistorySnapshots bounds the number of at-time queries made by History
+// to discover the versions which are not linked by Replaces.
+const maxHistorySnapshots = 100
+
+// Kinds of AspectChange.
+const (
+	// AspectAsserted is the assertion of an aspect replacing none.
+	AspectAsserted = "asserted"
+	// AspectReplaced is the assertion of an aspect replacing another.
+	AspectReplaced = "replaced"
+	// AspectRetracted is the retraction of an aspect not replaced by
+	// another.
+	AspectRetracted = "retracted"
+)
+
+// AspectHistory is the timeline of the aspects of an entity.
+type AspectHistory struct {
+	// Entity the aspects are about.
+	Entity string
+	// Schema of the aspects, possibly a prefix ending with '%'.
+	Schema string
+	// Versions lists all the aspects found, in the order they were asserted.
+	Versions []*aspect.AspectRT
+	// Changes lists the assertions and retractions in the order they were
+	// made.
+	Changes []*AspectChange
+}
+
+// AspectChange is a change in the aspects of an entity.
+type AspectChange struct {
+	// Time of the change.
+	Time time.Time
+	// Kind is AspectAsserted, AspectReplaced or AspectRetracted.
+	Kind string
+	// By is the user who asserted or retracted the aspect.
+	By string
+	// Aspect is the version asserted, or the one retracted.
+	Aspect *aspect.AspectRT
+	// Previous is the version replaced by Aspect, if any.
+	Previous *aspect.AspectRT
+}
+
+// AspectDiff lists the differences between the aspects of an entity at two
+// points in time.
+type AspectDiff struct {
+	From, To time.Time
+	// Added lists the aspects valid at To only, which replace none valid at
+	// From.
+	Added []*aspect.AspectRT
+	// Removed lists the aspects valid at From only, which are not replaced by
+	// any valid at To.
+	Removed []*aspect.AspectRT
+	// Changed lists the aspects valid at From which are replaced, possibly
+	// over several versions, by one valid at To.
+	Changed []*AspectUpdate
+}
+
+// AspectUpdate is an aspect replaced by a later version.
+type AspectUpdate struct {
+	Before, After *aspect.AspectRT
+	// Paths lists the JSON paths of the content which differ, such as
+	// "$.name".
+	Paths []string
+}
+
+// History returns the timeline of the aspects of entity with schema, which
+// may be a prefix ending with '%', or empty for all schemas. The versions
+// are found by following the Replaces links from the aspects valid now, and
+// from those valid just before the start and at the end of each version
+// found, until no more turn up. Aspects which were retracted without
+// replacement and valid only while no other version was asserted or
+// retracted cannot be found. Content which does not decode into the type
+// registered for its schema is left as read.
+func (s *AspectsClient) History(ctx context.Context, entity, schema string) (*AspectHistory, error) {
+	h := &historyBuilder{s: s, entity: entity, versions: map[string]*aspect.AspectRT{}}
+	if schema != "" {
+		h.schema = &schema
+	}
+	if err := h.snapshot(ctx, nil); err != nil {
+		return nil, err
+	}
+	queried := map[time.Time]bool{}
+	for n := 0; n < maxHistorySnapshots; {
+		var next []time.Time
+		for _, v := range h.versions {
+			from, to := versionTimes(v)
+			next = append(next, from.Add(-time.Microsecond))
+			if !to.IsZero() {
+				next = append(next, to)
+			}
+		}
+		found := false
+		for _, t := range next {
+			if queried[t] || n >= maxHistorySnapshots {
+				continue
+			}
+			queried[t] = true
+			n++
+			before := len(h.versions)
+			if err := h.snapshot(ctx, &t); err != nil {
+				return nil, err
+			}
+			found = found || len(h.versions) > before
+		}
+		if !found {
+			break
+		}
+	}
+	return h.history(), nil
+}
+
+// historyBuilder collects the versions of the aspects of an entity.
+type historyBuilder struct {
+	s        *AspectsClient
+	entity   string
+	schema   *string
+	versions map[string]*aspect.AspectRT
+}
+
+// snapshot adds the aspects valid at t, or now if nil, and the ones they
+// replace.
+func (h *historyBuilder) snapshot(ctx context.Context, t *time.Time) error {
+	p := &aspect.ListPayload{Entity: &h.entity, Schema: h.schema}
+	if t != nil {
+		at := t.UTC().Format(time.RFC3339Nano)
+		p.AtTime = &at
+	}
+	it := h.s.ListIter(ctx, p)
+	for it.Next() {
+		for id := it.Item().ID; id != ""; {
+			if _, ok := h.versions[id]; ok {
+				break
+			}
+			v, err := h.s.readRaw(ctx, id)
+			if err != nil {
+				return err
+			}
+			decodeContent(v.Schema, v.ID, &v.Content)
+			h.versions[id] = v
+			id = stringValue(v.Replaces)
+		}
+	}
+	return it.Err()
+}
+
+// history orders the versions collected and derives the changes.
+func (h *historyBuilder) history() *AspectHistory {
+	res := &AspectHistory{Entity: h.entity, Schema: stringValue(h.schema)}
+	replaced := map[string]bool{}
+	for _, v := range h.versions {
+		res.Versions = append(res.Versions, v)
+		if v.Replaces != nil {
+			replaced[*v.Replaces] = true
+		}
+	}
+	sort.SliceStable(res.Versions, func(i, j int) bool {
+		fi, _ := versionTimes(res.Versions[i])
+		fj, _ := versionTimes(res.Versions[j])
+		return fi.Before(fj)
+	})
+	for _, v := range res.Versions {
+		from, to := versionTimes(v)
+		c := &AspectChange{Time: from, Kind: AspectAsserted, By: v.Asserter, Aspect: v}
+		if v.Replaces != nil {
+			c.Kind, c.Previous = AspectReplaced, h.versions[*v.Replaces]
+		}
+		res.Changes = append(res.Changes, c)
+		if !to.IsZero() && !replaced[v.ID] {
+			res.Changes = append(res.Changes, &AspectChange{Time: to, Kind: AspectRetracted, By: stringValue(v.Retracter), Aspect: v})
+		}
+	}
+	sort.SliceStable(res.Changes, func(i, j int) bool { return res.Changes[i].Time.Before(res.Changes[j].Time) })
+	return res
+}
+
+// At returns the versions valid at t.
+func (h *AspectHistory) At(t time.Time) []*aspect.AspectRT {
+	var res []*aspect.AspectRT
+	for _, v := range h.Versions {
+		from, to := versionTimes(v)
+		if !t.Before(from) && (to.IsZero() || t.Before(to)) {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// Diff returns the differences between the versions valid at from and those
+// valid at to.
+func (h *AspectHistory) Diff(from, to time.Time) *AspectDiff {
+	d := &AspectDiff{From: from, To: to}
+	before, after := map[string]*aspect.AspectRT{}, map[string]*aspect.AspectRT{}
+	for _, v := range h.At(from) {
+		before[v.ID] = v
+	}
+	for _, v := range h.At(to) {
+		after[v.ID] = v
+	}
+	byID := map[string]*aspect.AspectRT{}
+	for _, v := range h.Versions {
+		byID[v.ID] = v
+	}
+	matched := map[string]bool{}
+	for _, v := range h.At(to) {
+		if _, ok := before[v.ID]; ok {
+			continue
+		}
+		var prev *aspect.AspectRT
+		for id := stringValue(v.Replaces); id != "" && prev == nil; {
+			if b, ok := before[id]; ok && after[id] == nil {
+				prev = b
+			} else if r, ok := byID[id]; ok {
+				id = stringValue(r.Replaces)
+			} else {
+				id = ""
+			}
+		}
+		if prev == nil {
+			d.Added = append(d.Added, v)
+			continue
+		}
+		matched[prev.ID] = true
+		d.Changed = append(d.Changed, &AspectUpdate{Before: prev, After: v, Paths: contentDiff(prev.Content, v.Content)})
+	}
+	for _, v := range h.At(from) {
+		if after[v.ID] == nil && !matched[v.ID] {
+			d.Removed = append(d.Removed, v)
+		}
+	}
+	return d
+}
+
+// versionTimes returns the validity period of v. The end is zero while v is
+// valid.
+func versionTimes(v *aspect.AspectRT) (from, to time.Time) {
+	from, _ = time.Parse(time.RFC3339Nano, v.ValidFrom)
+	if v.ValidTo != nil {
+		to, _ = time.Parse(time.RFC3339Nano, *v.ValidTo)
+	}
+	return from, to
+}
+
+// contentDiff returns the JSON paths at which the contents a and b differ,
+// in lexical order.
+func contentDiff(a, b any) []string {
+	var paths []string
+	var walk func(path string, a, b any)
+	walk = func(path string, a, b any) {
+		ma, oka := a.(map[string]any)
+		mb, okb := b.(map[string]any)
+		if !oka || !okb {
+			if !reflect.DeepEqual(a, b) {
+				paths = append(paths, path)
+			}
+			return
+		}
+		for k, va := range ma {
+			vb, ok := mb[k]
+			if !ok {
+				paths = append(paths, path+"."+k)
+				continue
+			}
+			walk(path+"."+k, va, vb)
+		}
+		for k := range mb {
+			if _, ok := ma[k]; !ok {
+				paths = append(paths, path+"."+k)
+			}
+		}
+	}
+	walk("$", jsonValue(a), jsonValue(b))
+	sort.Strings(paths)
+	return paths
+}
+
+// jsonValue returns v as decoded by encoding/json into an any.
+func jsonValue(v any) any {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	var res any
+	if json.Unmarshal(b, &res) != nil {
+		return string(b)
+	}
+	return res
+}
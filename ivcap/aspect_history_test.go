@@ -0,0 +1,99 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	aspect "github.com/ivcap-works/ivcap-core-api/gen/aspect"
+	"github.com/ivcap-works/ivcap-core-api/ivcap"
+)
+
+func TestHistory(t *testing.T) {
+	// Each step writes content n, replacing the aspect written by the
+	// previous step.
+	tests := []struct {
+		name  string
+		steps []string
+		kinds []string
+	}{
+		{
+			name:  "replaced",
+			steps: []string{"update", "update", "update"},
+			kinds: []string{ivcap.AspectAsserted, ivcap.AspectReplaced, ivcap.AspectReplaced},
+		},
+		{
+			name:  "upserted",
+			steps: []string{"upsert", "upsert", "upsert"},
+			kinds: []string{ivcap.AspectAsserted, ivcap.AspectAsserted, ivcap.AspectRetracted, ivcap.AspectAsserted, ivcap.AspectRetracted},
+		},
+		{
+			name:  "updated",
+			steps: []string{"upsert", "update"},
+			kinds: []string{ivcap.AspectAsserted, ivcap.AspectReplaced},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newTestClient(t, "urn:ivcap:user:alice")
+			ctx := context.Background()
+			var ids []string
+			prev := ""
+			for n, step := range tt.steps {
+				var err error
+				var res *aspect.AspectIDRT
+				switch step {
+				case "upsert":
+					res, err = c.Aspects.Upsert(ctx, testEntity, testSchema, map[string]any{"n": n}, prev)
+				case "update":
+					res, err = c.Aspects.Update(ctx, &aspect.UpdatePayload{
+						Entity:      testEntity,
+						Schema:      testSchema,
+						Content:     map[string]any{"n": n},
+						ContentType: "application/json",
+					})
+				}
+				if err != nil {
+					t.Fatalf("%s: %v", step, err)
+				}
+				prev = res.ID
+				ids = append(ids, res.ID)
+			}
+
+			h, err := c.Aspects.History(ctx, testEntity, testSchema)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var kinds, versions []string
+			for _, ch := range h.Changes {
+				kinds = append(kinds, ch.Kind)
+				if ch.Kind == ivcap.AspectReplaced && (ch.Previous == nil || ch.Previous.ID != stringOf(ch.Aspect.Replaces)) {
+					t.Errorf("change of %s: Previous = %v, want %s", ch.Aspect.ID, ch.Previous, stringOf(ch.Aspect.Replaces))
+				}
+			}
+			for _, v := range h.Versions {
+				versions = append(versions, v.ID)
+			}
+			if !reflect.DeepEqual(kinds, tt.kinds) {
+				t.Errorf("kinds = %v, want %v", kinds, tt.kinds)
+			}
+			if !reflect.DeepEqual(versions, ids) {
+				t.Errorf("versions = %v, want %v", versions, ids)
+			}
+		})
+	}
+}
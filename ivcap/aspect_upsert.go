@@ -0,0 +1,178 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	aspect "github.com/ivcap-works/ivcap-core-api/gen/aspect"
+	"github.com/ivcap-works/ivcap-core-api/ivcaperr"
+)
+
+// maxUpsertAttempts bounds the number of times UpsertMerge reads the current
+// aspect and tries to replace it.
+const maxUpsertAttempts = 5
+
+// upsertBackoff spaces out the attempts of UpsertMerge racing other writers.
+var upsertBackoff = RetryPolicy{
+	InitialBackoff: 100 * time.Millisecond,
+	MaxBackoff:     2 * time.Second,
+	Multiplier:     2,
+}
+
+// AspectConflictError is returned by AspectsClient.Upsert when the active
+// aspect of the entity and schema is not the one expected. It matches
+// ivcaperr.ErrConflict.
+type AspectConflictError struct {
+	Entity string
+	Schema string
+	// Expected is the ID of the aspect the caller expected to be active, or
+	// "" if none.
+	Expected string
+	// Current lists the IDs of the aspects active when the conflict was
+	// detected.
+	Current []string
+}
+
+// Error returns the error message.
+func (e *AspectConflictError) Error() string {
+	cur := "none"
+	if len(e.Current) > 0 {
+		cur = strings.Join(e.Current, ", ")
+	}
+	exp := e.Expected
+	if exp == "" {
+		exp = "none"
+	}
+	return fmt.Sprintf("ivcap: aspect %s of %s changed: expected %s, found %s", e.Schema, e.Entity, exp, cur)
+}
+
+// Is reports whether target is ivcaperr.ErrConflict.
+func (e *AspectConflictError) Is(target error) bool {
+	return target == ivcaperr.ErrConflict
+}
+
+// Upsert asserts content as the aspect of entity with schema, replacing the
+// aspect expectedID, or asserting a first one if expectedID is "". It fails
+// with *AspectConflictError if expectedID is not the only active aspect, for
+// example because another writer replaced it since it was read.
+//
+// The service has no conditional create, so the new aspect is asserted first
+// and kept only if the aspects active next to it are exactly the expected
+// one, which is then retracted. Otherwise the new aspect is retracted again.
+// Of several writers racing to replace the same aspect at most one succeeds,
+// but readers may briefly see the new aspect next to the one it replaces,
+// and the new aspect does not name it in Replaces.
+func (s *AspectsClient) Upsert(ctx context.Context, entity, schema string, content any, expectedID string) (*aspect.AspectIDRT, error) {
+	cur, err := s.activeIDs(ctx, entity, schema, "")
+	if err != nil {
+		return nil, err
+	}
+	if !onlyExpected(cur, expectedID) {
+		return nil, &AspectConflictError{Entity: entity, Schema: schema, Expected: expectedID, Current: cur}
+	}
+	res, err := s.Create(ctx, &aspect.CreatePayload{
+		Entity:      entity,
+		Schema:      schema,
+		Content:     content,
+		ContentType: "application/json",
+	})
+	if err != nil {
+		return nil, err
+	}
+	if cur, err = s.activeIDs(ctx, entity, schema, res.ID); err != nil {
+		return nil, err
+	}
+	if !onlyExpected(cur, expectedID) {
+		if err := s.Retract(ctx, res.ID); err != nil {
+			return nil, err
+		}
+		return nil, &AspectConflictError{Entity: entity, Schema: schema, Expected: expectedID, Current: cur}
+	}
+	if expectedID != "" {
+		if err := s.Retract(ctx, expectedID); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// UpsertMerge asserts the aspect of entity with schema returned by merge,
+// which is called with the active aspect, or nil if there is none. If
+// another writer changes the aspect in between, merge is called again with
+// the new one, up to a few times before giving up with
+// *AspectConflictError. An entity with several active aspects of schema is a
+// conflict merge is not asked to resolve.
+func (s *AspectsClient) UpsertMerge(ctx context.Context, entity, schema string, merge func(current *aspect.AspectRT) (any, error)) (*aspect.AspectIDRT, error) {
+	for attempt := 1; ; attempt++ {
+		ids, err := s.activeIDs(ctx, entity, schema, "")
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) > 1 {
+			return nil, &AspectConflictError{Entity: entity, Schema: schema, Current: ids}
+		}
+		var cur *aspect.AspectRT
+		var expected string
+		if len(ids) == 1 {
+			if cur, err = s.Read(ctx, ids[0]); err != nil {
+				return nil, err
+			}
+			expected = cur.ID
+		}
+		content, err := merge(cur)
+		if err != nil {
+			return nil, err
+		}
+		res, err := s.Upsert(ctx, entity, schema, content, expected)
+		var ce *AspectConflictError
+		if !errors.As(err, &ce) || attempt >= maxUpsertAttempts {
+			return res, err
+		}
+		t := time.NewTimer(upsertBackoff.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// activeIDs returns the IDs of the aspects of entity with schema valid now,
+// leaving out the aspect skip.
+func (s *AspectsClient) activeIDs(ctx context.Context, entity, schema, skip string) ([]string, error) {
+	var ids []string
+	it := s.ListIter(ctx, &aspect.ListPayload{Entity: &entity, Schema: &schema})
+	for it.Next() {
+		if v := it.Item(); v.Schema == schema && v.ID != skip {
+			ids = append(ids, v.ID)
+		}
+	}
+	return ids, it.Err()
+}
+
+// onlyExpected reports whether ids is empty if expected is "", or holds
+// expected only otherwise.
+func onlyExpected(ids []string, expected string) bool {
+	if expected == "" {
+		return len(ids) == 0
+	}
+	return len(ids) == 1 && ids[0] == expected
+}
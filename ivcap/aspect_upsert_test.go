@@ -0,0 +1,158 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	aspect "github.com/ivcap-works/ivcap-core-api/gen/aspect"
+	"github.com/ivcap-works/ivcap-core-api/ivcap"
+	"github.com/ivcap-works/ivcap-core-api/ivcaperr"
+)
+
+const (
+	testEntity = "urn:ivcap:entity:test"
+	testSchema = "urn:ivcap:schema:test.1"
+)
+
+func TestUpsert(t *testing.T) {
+	tests := []struct {
+		name string
+		// expected picks the expected ID from the IDs of the aspects
+		// asserted by setup, in order.
+		setup    int
+		retract  bool
+		expected func(ids []string) string
+		conflict bool
+	}{
+		{name: "first", expected: none},
+		{name: "first but one exists", setup: 1, expected: none, conflict: true},
+		{name: "replace current", setup: 1, expected: last},
+		{name: "replace stale", setup: 2, expected: first, conflict: true},
+		{name: "replace retracted", setup: 1, retract: true, expected: last, conflict: true},
+		{name: "replace unknown", expected: func([]string) string { return "urn:ivcap:aspect:unknown" }, conflict: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newTestClient(t, "urn:ivcap:user:alice")
+			ctx := context.Background()
+			var ids []string
+			for i := 0; i < tt.setup; i++ {
+				res, err := c.Aspects.Update(ctx, &aspect.UpdatePayload{
+					Entity:      testEntity,
+					Schema:      testSchema,
+					Content:     map[string]any{"n": i},
+					ContentType: "application/json",
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				ids = append(ids, res.ID)
+			}
+			if tt.retract {
+				if err := c.Aspects.Retract(ctx, ids[len(ids)-1]); err != nil {
+					t.Fatal(err)
+				}
+			}
+			expected := tt.expected(ids)
+			res, err := c.Aspects.Upsert(ctx, testEntity, testSchema, map[string]any{"n": "new"}, expected)
+			if tt.conflict {
+				var ce *ivcap.AspectConflictError
+				if !errors.As(err, &ce) || !errors.Is(err, ivcaperr.ErrConflict) {
+					t.Fatalf("got %v, want *AspectConflictError", err)
+				}
+				if ce.Expected != expected {
+					t.Errorf("Expected = %q, want %q", ce.Expected, expected)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			active := activeIDs(t, c)
+			if len(active) != 1 || active[0] != res.ID {
+				t.Errorf("active = %v, want [%s]", active, res.ID)
+			}
+		})
+	}
+}
+
+func TestUpsertMergeConcurrent(t *testing.T) {
+	c, _ := newTestClient(t, "urn:ivcap:user:alice")
+	ctx := context.Background()
+	const writers = 4
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		go func() {
+			_, err := c.Aspects.UpsertMerge(ctx, testEntity, testSchema, func(cur *aspect.AspectRT) (any, error) {
+				n := 0.0
+				if cur != nil {
+					n = cur.Content.(map[string]any)["n"].(float64)
+				}
+				return map[string]any{"n": n + 1}, nil
+			})
+			errs <- err
+		}()
+	}
+	done := 0
+	for i := 0; i < writers; i++ {
+		if err := <-errs; err == nil {
+			done++
+		} else if !errors.Is(err, ivcaperr.ErrConflict) {
+			t.Fatal(err)
+		}
+	}
+	active := activeIDs(t, c)
+	if len(active) != 1 {
+		t.Fatalf("active = %v, want one aspect", active)
+	}
+	a, err := c.Aspects.Read(ctx, active[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := a.Content.(map[string]any)["n"].(float64); int(n) != done {
+		t.Errorf("n = %v after %d successful merges", n, done)
+	}
+}
+
+func none([]string) string      { return "" }
+func first(ids []string) string { return ids[0] }
+func last(ids []string) string  { return ids[len(ids)-1] }
+
+func stringOf(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// activeIDs returns the IDs of the aspects of testEntity with testSchema
+// valid now.
+func activeIDs(t *testing.T, c *ivcap.Client) []string {
+	t.Helper()
+	var ids []string
+	it := c.Aspects.ListIter(context.Background(), &aspect.ListPayload{Entity: ptr(testEntity), Schema: ptr(testSchema)})
+	for it.Next() {
+		ids = append(ids, it.Item().ID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return ids
+}
+
+func ptr[T any](v T) *T { return &v }
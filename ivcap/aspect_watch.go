@@ -0,0 +1,282 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	aspect "github.com/ivcap-works/ivcap-core-api/gen/aspect"
+	"github.com/ivcap-works/ivcap-core-api/ivcaperr"
+)
+
+// AspectWatch selects the aspects AspectsClient.Watch reports the changes
+// of, and how it polls them.
+type AspectWatch struct {
+	// Entity is the optional URN of the entity the aspects are attached to.
+	Entity string
+	// Schema is the optional schema of the aspects, possibly a prefix ending
+	// with '%'.
+	Schema string
+	// ContentPath is an optional JSONPath filter on the aspect content.
+	ContentPath string
+	// IncludeContent adds the content of the aspects to the events.
+	IncludeContent bool
+	// Interval is the delay between two polls. It defaults to 10s.
+	Interval time.Duration
+	// Lag is how long a change is left to settle before it is reported,
+	// allowing for differences between the clocks of the client and the
+	// deployment. It defaults to 2s.
+	Lag time.Duration
+	// Since is the time the changes are reported from when there is no
+	// cursor to continue from. It defaults to now.
+	Since time.Time
+	// CursorPath is the optional file the position in the feed is kept in,
+	// so that a restarted watch continues after the last event received.
+	CursorPath string
+}
+
+// AspectEvent is the assertion or retraction of an aspect.
+type AspectEvent struct {
+	// Kind is AspectAsserted or AspectRetracted.
+	Kind string `json:"kind"`
+	// Time the aspect was asserted or retracted.
+	Time   time.Time                `json:"time"`
+	Aspect *aspect.AspectListItemRT `json:"aspect"`
+	// ContentErr is the *ContentError of content which does not decode into
+	// the type registered for its schema, and is left as read.
+	ContentErr error `json:"-"`
+}
+
+// AspectWatcher delivers the events of AspectsClient.Watch.
+type AspectWatcher struct {
+	// C delivers the events in the order they happened. It is closed when
+	// the context of the watch is done or polling fails.
+	C   <-chan *AspectEvent
+	err error
+}
+
+// Err returns the error which stopped the watch, once C is closed.
+func (w *AspectWatcher) Err() error {
+	return w.err
+}
+
+// watchCursor is the position in the feed: the time of the last event
+// delivered and the events delivered at that time.
+type watchCursor struct {
+	Time time.Time `json:"time"`
+	Seen []string  `json:"seen,omitempty"`
+}
+
+// Watch reports the aspects matching f as they are asserted and retracted.
+// It lists the aspects valid at the start and at the end of successive time
+// windows, and reports those asserted or retracted in between. Changes are
+// delivered at least once: with f.CursorPath set, a restarted watch only
+// repeats the event being received when it stopped. An aspect asserted and
+// retracted within the same window is not reported. Failures to reach the
+// deployment are retried at the next poll; others stop the watch.
+func (s *AspectsClient) Watch(ctx context.Context, f *AspectWatch) (*AspectWatcher, error) {
+	var o AspectWatch
+	if f != nil {
+		o = *f
+	}
+	if o.Interval <= 0 {
+		o.Interval = 10 * time.Second
+	}
+	if o.Lag <= 0 {
+		o.Lag = 2 * time.Second
+	}
+	cur, err := loadWatchCursor(o.CursorPath)
+	if err != nil {
+		return nil, err
+	}
+	if cur == nil {
+		cur = &watchCursor{Time: o.Since}
+		if cur.Time.IsZero() {
+			cur.Time = time.Now().Add(-o.Lag)
+		}
+	}
+	c := make(chan *AspectEvent)
+	w := &AspectWatcher{C: c}
+	go func() {
+		defer close(c)
+		w.err = s.watch(ctx, &o, cur, c)
+	}()
+	return w, nil
+}
+
+// watch polls the aspects matching o from cur on, until ctx is done or a
+// poll fails permanently.
+func (s *AspectsClient) watch(ctx context.Context, o *AspectWatch, cur *watchCursor, c chan<- *AspectEvent) error {
+	seen := map[string]bool{}
+	for _, k := range cur.Seen {
+		seen[k] = true
+	}
+	for {
+		end := time.Now().Add(-o.Lag)
+		if end.After(cur.Time) {
+			events, err := s.window(ctx, o, cur.Time, end)
+			if err != nil && !errors.Is(err, ivcaperr.ErrUnavailable) && !errors.Is(err, ivcaperr.ErrTransport) {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				return err
+			}
+			if err == nil {
+				for _, ev := range events {
+					key := ev.Kind + " " + ev.Aspect.ID
+					if ev.Time.Equal(cur.Time) && seen[key] {
+						continue
+					}
+					select {
+					case c <- ev:
+					case <-ctx.Done():
+						return ctx.Err()
+					}
+					if ev.Time.After(cur.Time) {
+						cur.Time, seen = ev.Time, map[string]bool{}
+					}
+					seen[key] = true
+					if err := cur.save(o.CursorPath, seen); err != nil {
+						return err
+					}
+				}
+				if end.After(cur.Time) {
+					cur.Time, seen = end, map[string]bool{}
+					if err := cur.save(o.CursorPath, seen); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		t := time.NewTimer(o.Interval)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// window returns the events of the aspects matching o between from and to,
+// both included, in the order they happened. Assertions are found among the
+// aspects valid at to, retractions among those valid just before from.
+func (s *AspectsClient) window(ctx context.Context, o *AspectWatch, from, to time.Time) ([]*AspectEvent, error) {
+	in := func(t time.Time) bool {
+		return !t.Before(from) && !t.After(to)
+	}
+	var events []*AspectEvent
+	list := func(at time.Time, add func(a *aspect.AspectListItemRT, cerr error)) error {
+		p := &aspect.ListPayload{AtTime: timeString(at)}
+		if o.Entity != "" {
+			p.Entity = &o.Entity
+		}
+		if o.Schema != "" {
+			p.Schema = &o.Schema
+		}
+		if o.ContentPath != "" {
+			p.ContentPath = &o.ContentPath
+		}
+		if o.IncludeContent {
+			p.IncludeContent = &o.IncludeContent
+		}
+		it := s.ListIter(ctx, p)
+		for it.Next() {
+			add(it.Item(), it.ItemErr())
+		}
+		return it.Err()
+	}
+	err := list(to, func(a *aspect.AspectListItemRT, cerr error) {
+		if t, err := time.Parse(time.RFC3339Nano, stringValue(a.ValidFrom)); err == nil && in(t) {
+			events = append(events, &AspectEvent{Kind: AspectAsserted, Time: t, Aspect: a, ContentErr: cerr})
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = list(from.Add(-time.Microsecond), func(a *aspect.AspectListItemRT, cerr error) {
+		if a.ValidTo == nil {
+			return
+		}
+		if t, err := time.Parse(time.RFC3339Nano, *a.ValidTo); err == nil && in(t) {
+			events = append(events, &AspectEvent{Kind: AspectRetracted, Time: t, Aspect: a, ContentErr: cerr})
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	// A replacement retracts and asserts at the same time; the retraction
+	// is reported first.
+	sort.SliceStable(events, func(i, j int) bool {
+		a, b := events[i], events[j]
+		if !a.Time.Equal(b.Time) {
+			return a.Time.Before(b.Time)
+		}
+		return a.Kind == AspectRetracted && b.Kind != AspectRetracted
+	})
+	return events, nil
+}
+
+// timeString formats t for the at-time parameter of list calls.
+func timeString(t time.Time) *string {
+	v := t.UTC().Format(time.RFC3339Nano)
+	return &v
+}
+
+// loadWatchCursor reads the cursor saved at path, returning nil if path is
+// empty or does not exist.
+func loadWatchCursor(path string) (*watchCursor, error) {
+	if path == "" {
+		return nil, nil
+	}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ivcap: reading cursor: %w", err)
+	}
+	var cur watchCursor
+	if err := json.Unmarshal(b, &cur); err != nil {
+		return nil, fmt.Errorf("ivcap: reading cursor %s: %w", path, err)
+	}
+	return &cur, nil
+}
+
+// save writes the cursor, with the events seen at its time, to path.
+func (cur *watchCursor) save(path string, seen map[string]bool) error {
+	if path == "" {
+		return nil
+	}
+	cur.Seen = cur.Seen[:0]
+	for k := range seen {
+		cur.Seen = append(cur.Seen, k)
+	}
+	sort.Strings(cur.Seen)
+	b, err := json.Marshal(cur)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, append(b, '\n'), 0644); err != nil {
+		return fmt.Errorf("ivcap: writing cursor: %w", err)
+	}
+	return nil
+}
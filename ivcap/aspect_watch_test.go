@@ -0,0 +1,121 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	aspect "github.com/ivcap-works/ivcap-core-api/gen/aspect"
+	"github.com/ivcap-works/ivcap-core-api/ivcap"
+)
+
+// watchEntities returns the entities of the events received on w until n
+// were received or none came for a while.
+func watchEntities(t *testing.T, w *ivcap.AspectWatcher, n int) []string {
+	t.Helper()
+	var got []string
+	for len(got) < n {
+		select {
+		case ev, ok := <-w.C:
+			if !ok {
+				t.Fatalf("watch stopped: %v", w.Err())
+			}
+			got = append(got, ev.Aspect.Entity)
+		case <-time.After(200 * time.Millisecond):
+			return got
+		}
+	}
+	return got
+}
+
+func TestWatchCursor(t *testing.T) {
+	tests := []struct {
+		name string
+		// first is the number of events received before the first watch
+		// stops, after the aspects of entities 1 and 2 were asserted. The
+		// aspect of entity 3 is asserted before the second watch starts.
+		first int
+		// cursor makes the watches keep a cursor, and want lists the
+		// entities of the events received by the second one.
+		cursor bool
+		want   []string
+	}{
+		{name: "continued", first: 2, cursor: true, want: []string{"urn:ivcap:entity:3"}},
+		{name: "stopped while receiving", first: 1, cursor: true, want: []string{"urn:ivcap:entity:2", "urn:ivcap:entity:3"}},
+		{name: "no cursor", first: 2, want: []string{"urn:ivcap:entity:1", "urn:ivcap:entity:2", "urn:ivcap:entity:3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newTestClient(t, "urn:ivcap:user:alice")
+			ctx := context.Background()
+			create := func(entity string) {
+				t.Helper()
+				_, err := c.Aspects.Create(ctx, &aspect.CreatePayload{Entity: entity, Schema: testSchema, Content: map[string]any{"v": 1}, ContentType: "application/json"})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			f := &ivcap.AspectWatch{
+				Schema:   testSchema,
+				Interval: 10 * time.Millisecond,
+				Lag:      time.Millisecond,
+				Since:    time.Now().Add(-time.Second),
+			}
+			if tt.cursor {
+				f.CursorPath = filepath.Join(t.TempDir(), "watch.cursor")
+			}
+			create("urn:ivcap:entity:1")
+			create("urn:ivcap:entity:2")
+
+			wctx, cancel := context.WithCancel(ctx)
+			w, err := c.Aspects.Watch(wctx, f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := watchEntities(t, w, tt.first); len(got) != tt.first {
+				t.Fatalf("first watch received %v, want %d events", got, tt.first)
+			}
+			cancel()
+			for range w.C {
+			}
+
+			create("urn:ivcap:entity:3")
+			wctx, cancel = context.WithCancel(ctx)
+			defer cancel()
+			if w, err = c.Aspects.Watch(wctx, f); err != nil {
+				t.Fatal(err)
+			}
+			if got := watchEntities(t, w, len(tt.want)+1); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("second watch received %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWatchCorruptCursor(t *testing.T) {
+	c, _ := newTestClient(t, "urn:ivcap:user:alice")
+	path := filepath.Join(t.TempDir(), "watch.cursor")
+	if err := os.WriteFile(path, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Aspects.Watch(context.Background(), &ivcap.AspectWatch{CursorPath: path}); err == nil {
+		t.Error("watch started from a corrupt cursor")
+	}
+}
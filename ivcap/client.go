@@ -0,0 +1,226 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ivcap provides a single client for all the services of an IVCAP
+// deployment. It wires the generated HTTP clients in http/* behind one
+// constructor and exposes typed methods returning the gen/* result types.
+package ivcap
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	goahttp "goa.design/goa/v3/http"
+	goa "goa.design/goa/v3/pkg"
+)
+
+// Client gives access to all the services of an IVCAP deployment.
+type Client struct {
+	Artifacts  *ArtifactsClient
+	Aspects    *AspectsClient
+	Dashboards *DashboardsClient
+	Metadata   *MetadataClient
+	OpenAPI    *OpenAPIClient
+	Orders     *OrdersClient
+	Packages   *PackagesClient
+	Projects   *ProjectsClient
+	Queues     *QueuesClient
+	Search     *SearchClient
+	Secrets    *SecretsClient
+	Services   *ServicesClient
+
+	scheme  string
+	host    string
+	doer    goahttp.Doer
+	plain   goahttp.Doer
+	retry   *RetryPolicy
+	project string
+	account string
+	cache   *ArtifactCache
+	schemas *schemaValidator
+}
+
+// Option configures a Client created by New.
+type Option func(*options)
+
+type options struct {
+	doer         goahttp.Doer
+	timeout      time.Duration
+	tokens       TokenSource
+	retry        *RetryPolicy
+	restoreBody  bool
+	project      string
+	account      string
+	versionCheck VersionCheck
+	versionWarn  func(error)
+	cache        *ArtifactCache
+	schemas      SchemaResolver
+}
+
+// WithDoer sets the HTTP client shared by all service clients. It defaults to
+// a http.Client honouring the timeout set by WithTimeout.
+func WithDoer(doer goahttp.Doer) Option {
+	return func(o *options) {
+		o.doer = doer
+	}
+}
+
+// WithTimeout sets the timeout of the default HTTP client. It is ignored when
+// a doer is provided with WithDoer.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+// WithJWT sets a fixed JWT used for every request which does not already
+// carry one in its payload.
+func WithJWT(jwt string) Option {
+	return WithTokenSource(StaticTokenSource(jwt))
+}
+
+// WithTokenSource sets the source asked for a token on every request which
+// does not already carry a JWT in its payload.
+func WithTokenSource(src TokenSource) Option {
+	return func(o *options) {
+		o.tokens = src
+	}
+}
+
+// WithRestoreResponseBody controls whether the response bodies are reset
+// after decoding so they can be read again.
+func WithRestoreResponseBody(restore bool) Option {
+	return func(o *options) {
+		o.restoreBody = restore
+	}
+}
+
+// New returns a client for the IVCAP deployment reachable at baseURL, such as
+// "https://develop.ivcap.net".
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL %q: %w", baseURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base URL %q: scheme must be http or https", baseURL)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid base URL %q: missing host", baseURL)
+	}
+	if u.Path != "" && u.Path != "/" {
+		return nil, fmt.Errorf("invalid base URL %q: path is not supported", baseURL)
+	}
+	o := &options{timeout: 30 * time.Second}
+	for _, opt := range opts {
+		opt(o)
+	}
+	doer := o.doer
+	if doer == nil {
+		doer = &http.Client{Timeout: o.timeout}
+	}
+	plain := goahttp.Doer(&recordingDoer{doer: doer})
+	doer = plain
+	if o.tokens != nil {
+		doer = &authDoer{doer: plain, src: o.tokens}
+	}
+	c := &Client{
+		scheme:  u.Scheme,
+		host:    u.Host,
+		doer:    doer,
+		plain:   plain,
+		retry:   o.retry,
+		project: o.project,
+		account: o.account,
+		cache:   o.cache,
+	}
+	if o.schemas != nil {
+		c.schemas = newSchemaValidator(o.schemas)
+	}
+	enc, dec := goahttp.RequestEncoder, goahttp.ResponseDecoder
+	c.Artifacts = newArtifactsClient(c, enc, dec, o.restoreBody)
+	c.Aspects = newAspectsClient(c, enc, dec, o.restoreBody)
+	c.Dashboards = newDashboardsClient(c, enc, dec, o.restoreBody)
+	c.Metadata = newMetadataClient(c, enc, dec, o.restoreBody)
+	c.OpenAPI = newOpenAPIClient(c, enc, dec, o.restoreBody)
+	c.Orders = newOrdersClient(c, enc, dec, o.restoreBody)
+	c.Packages = newPackagesClient(c, enc, dec, o.restoreBody)
+	c.Projects = newProjectsClient(c, enc, dec, o.restoreBody)
+	c.Queues = newQueuesClient(c, enc, dec, o.restoreBody)
+	c.Search = newSearchClient(c, enc, dec, o.restoreBody)
+	c.Secrets = newSecretsClient(c, enc, dec, o.restoreBody)
+	c.Services = newServicesClient(c, enc, dec, o.restoreBody)
+	if o.versionCheck != 0 {
+		if err := c.checkVersion(context.Background(), o); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// BaseURL returns the URL of the deployment this client talks to.
+func (c *Client) BaseURL() string {
+	return (&url.URL{Scheme: c.scheme, Host: c.host}).String()
+}
+
+// Doer returns the HTTP client shared by all service clients. Requests sent
+// through it are authorized with the client's token source.
+func (c *Client) Doer() goahttp.Doer {
+	return c.doer
+}
+
+// doerFor returns the doer for requests to u: the shared one for the
+// deployment itself, and one which does not authorize requests for any other
+// host, such as the presigned storage links handed out for artifact data.
+func (c *Client) doerFor(u *url.URL) goahttp.Doer {
+	if u.Scheme == c.scheme && strings.EqualFold(u.Host, c.host) {
+		return c.doer
+	}
+	return c.plain
+}
+
+// endpoint applies the client middleware to the endpoint of method of
+// service svc.
+func (c *Client) endpoint(svc, method string, ep goa.Endpoint) goa.Endpoint {
+	ep = errorMiddleware(svc, method)(ep)
+	if c.retry != nil {
+		ep = c.retry.middleware(svc, method)(ep)
+	}
+	return ep
+}
+
+// invoke calls endpoint with payload p and returns its result as a T.
+func invoke[T any](ctx context.Context, endpoint goa.Endpoint, p any) (T, error) {
+	var res T
+	v, err := endpoint(ctx, p)
+	if err != nil {
+		return res, err
+	}
+	if v == nil {
+		return res, nil
+	}
+	return v.(T), nil
+}
+
+// stringValue returns the string s points to, or "" if s is nil.
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
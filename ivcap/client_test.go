@@ -0,0 +1,43 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ivcap-works/ivcap-core-api/ivcap"
+	"github.com/ivcap-works/ivcap-core-api/ivcaptest"
+)
+
+// newTestClient returns a client for user talking to a new deployment of
+// the ivcaptest fakes, and the deployment.
+func newTestClient(t *testing.T, user string, opts ...ivcap.Option) (*ivcap.Client, *ivcaptest.Deployment) {
+	t.Helper()
+	d := ivcaptest.New()
+	return newDeploymentClient(t, d, user, opts...), d
+}
+
+// newDeploymentClient returns a client for user talking to d.
+func newDeploymentClient(t *testing.T, d *ivcaptest.Deployment, user string, opts ...ivcap.Option) *ivcap.Client {
+	t.Helper()
+	srv := httptest.NewServer(d.Handler())
+	t.Cleanup(srv.Close)
+	c, err := ivcap.New(srv.URL, append([]ivcap.Option{ivcap.WithJWT(ivcaptest.Token(user))}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
@@ -0,0 +1,236 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	openapi "github.com/ivcap-works/ivcap-core-api/gen/openapi"
+)
+
+// ErrIncompatible is returned when a deployment does not serve the API the
+// client was built against.
+var ErrIncompatible = errors.New("ivcap: incompatible deployment")
+
+// Operation identifies an HTTP operation of the API.
+type Operation struct {
+	// Service is the name of the service, such as "order".
+	Service string
+	// Method is the name of the service method, such as "create".
+	Method string
+	// HTTPMethod is the HTTP method of the operation, such as "POST".
+	HTTPMethod string
+	// Path is the path template of the operation, such as "/1/orders".
+	Path string
+}
+
+// String returns the operation as "order#create (POST /1/orders)".
+func (op Operation) String() string {
+	return fmt.Sprintf("%s#%s (%s %s)", op.Service, op.Method, op.HTTPMethod, op.Path)
+}
+
+// Operations lists the operations called by the client, as defined by
+// version openapi.APIVersion of the API.
+var Operations = []Operation{
+	{"artifact", "list", "GET", "/1/artifacts"},
+	{"artifact", "upload", "POST", "/1/artifacts"},
+	{"artifact", "read", "GET", "/1/artifacts/{id}"},
+	{"aspect", "list", "GET", "/1/aspects"},
+	{"aspect", "create", "POST", "/1/aspects"},
+	{"aspect", "update", "PUT", "/1/aspects"},
+	{"aspect", "retract", "DELETE", "/1/aspects/{id}"},
+	{"aspect", "read", "GET", "/1/aspects/{id}"},
+	{"dashboard", "list", "GET", "/1/dashboards"},
+	{"metadata", "list", "GET", "/1/metadata"},
+	{"metadata", "add", "POST", "/1/metadata"},
+	{"metadata", "revoke", "DELETE", "/1/metadata/{id}"},
+	{"metadata", "read", "GET", "/1/metadata/{id}"},
+	{"metadata", "update_record", "PUT", "/1/metadata/{id}"},
+	{"order", "list", "GET", "/1/orders"},
+	{"order", "create", "POST", "/1/orders"},
+	{"order", "read", "GET", "/1/orders/{id}"},
+	{"order", "logs", "GET", "/1/orders/{orderID}/logs"},
+	{"order", "metadata", "GET", "/1/orders/{orderID}/metadata"},
+	{"order", "products", "GET", "/1/orders/{orderID}/products"},
+	{"order", "top", "GET", "/1/orders/{orderID}/top"},
+	{"package", "list", "GET", "/1/packages/list"},
+	{"package", "pull", "GET", "/1/packages/pull"},
+	{"package", "push", "POST", "/1/packages/push"},
+	{"package", "remove", "DELETE", "/1/packages/remove"},
+	{"package", "status", "GET", "/1/packages/status"},
+	{"project", "list", "GET", "/1/project"},
+	{"project", "CreateProject", "POST", "/1/project"},
+	{"project", "DefaultProject", "GET", "/1/project/default"},
+	{"project", "SetDefaultProject", "PUT", "/1/project/default"},
+	{"project", "delete", "DELETE", "/1/project/{id}"},
+	{"project", "read", "GET", "/1/project/{id}"},
+	{"project", "ProjectAccount", "GET", "/1/project/{project_urn}/account"},
+	{"project", "SetProjectAccount", "PUT", "/1/project/{project_urn}/account"},
+	{"project", "RemoveMembership", "DELETE", "/1/project/{project_urn}/memberships/{user_urn}"},
+	{"project", "UpdateMembership", "PUT", "/1/project/{project_urn}/memberships/{user_urn}"},
+	{"project", "ListProjectMembers", "GET", "/1/project/{urn}/members"},
+	{"queue", "list", "GET", "/1/queues"},
+	{"queue", "create", "POST", "/1/queues"},
+	{"queue", "delete", "DELETE", "/1/queues/{id}"},
+	{"queue", "read", "GET", "/1/queues/{id}"},
+	{"queue", "dequeue", "GET", "/1/queues/{id}/messages"},
+	{"queue", "enqueue", "POST", "/1/queues/{id}/messages"},
+	{"search", "search", "POST", "/1/search"},
+	{"secret", "get", "GET", "/1/secrets"},
+	{"secret", "set", "POST", "/1/secrets"},
+	{"secret", "list", "GET", "/1/secrets/list"},
+	{"service", "list", "GET", "/1/services"},
+	{"service", "create_service", "POST", "/1/services"},
+	{"service", "delete", "DELETE", "/1/services/{id}"},
+	{"service", "read", "GET", "/1/services/{id}"},
+	{"service", "update", "PUT", "/1/services/{id}"},
+}
+
+// Compatibility reports how well a deployment matches the API the client was
+// built against.
+type Compatibility struct {
+	// ClientVersion is the API version the client was built against.
+	ClientVersion string
+	// ServerVersion is the API version served by the deployment.
+	ServerVersion string
+	// MajorMismatch is set if the major versions differ.
+	MajorMismatch bool
+	// Missing lists the operations called by the client which the deployment
+	// does not serve.
+	Missing []Operation
+}
+
+// Compatible reports whether the major versions match and the deployment
+// serves all operations called by the client.
+func (r *Compatibility) Compatible() bool {
+	return !r.MajorMismatch && len(r.Missing) == 0
+}
+
+// Err returns nil if the deployment is compatible, and otherwise an error
+// wrapping ErrIncompatible which describes the differences.
+func (r *Compatibility) Err() error {
+	if r.Compatible() {
+		return nil
+	}
+	var reasons []string
+	if r.MajorMismatch {
+		reasons = append(reasons, fmt.Sprintf("server API version %s, client built for %s", r.ServerVersion, r.ClientVersion))
+	}
+	if len(r.Missing) > 0 {
+		ops := make([]string, len(r.Missing))
+		for i, op := range r.Missing {
+			ops[i] = op.String()
+		}
+		reasons = append(reasons, "missing "+strings.Join(ops, ", "))
+	}
+	return fmt.Errorf("%w: %s", ErrIncompatible, strings.Join(reasons, "; "))
+}
+
+// CheckSpec compares the OpenAPI document of a deployment with the API the
+// client was built against.
+func CheckSpec(spec *OpenAPISpec) *Compatibility {
+	r := &Compatibility{
+		ClientVersion: openapi.APIVersion,
+		ServerVersion: spec.Info.Version,
+		MajorMismatch: majorVersion(spec.Info.Version) != majorVersion(openapi.APIVersion),
+	}
+	served := make(map[string]bool)
+	for path, ops := range spec.Paths {
+		for method := range ops {
+			served[method+" "+normalizePath(path)] = true
+		}
+	}
+	for _, op := range Operations {
+		if !served[op.HTTPMethod+" "+normalizePath(op.Path)] {
+			r.Missing = append(r.Missing, op)
+		}
+	}
+	return r
+}
+
+// CheckCompatibility fetches the OpenAPI document of the deployment and
+// compares it with the API the client was built against.
+func (c *Client) CheckCompatibility(ctx context.Context) (*Compatibility, error) {
+	spec, err := c.OpenAPI.FetchSpec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return CheckSpec(spec), nil
+}
+
+// VersionCheck selects how New handles a deployment which is not compatible
+// with the client.
+type VersionCheck int
+
+const (
+	// VersionCheckWarn reports the differences and returns the client.
+	VersionCheckWarn VersionCheck = iota + 1
+	// VersionCheckFail makes New fail.
+	VersionCheckFail
+)
+
+// WithVersionCheck makes New check the compatibility of the deployment, see
+// CheckCompatibility. With VersionCheckWarn, differences and failures to
+// fetch the OpenAPI document are passed to warn, which defaults to printing
+// them to stderr. With VersionCheckFail, New returns them as error.
+func WithVersionCheck(check VersionCheck, warn func(error)) Option {
+	if warn == nil {
+		warn = func(err error) {
+			fmt.Fprintln(os.Stderr, "warning:", err)
+		}
+	}
+	return func(o *options) {
+		o.versionCheck = check
+		o.versionWarn = warn
+	}
+}
+
+// checkVersion runs the check configured with WithVersionCheck.
+func (c *Client) checkVersion(ctx context.Context, o *options) error {
+	r, err := c.CheckCompatibility(ctx)
+	if err == nil {
+		err = r.Err()
+	} else {
+		err = fmt.Errorf("ivcap: checking compatibility: %w", err)
+	}
+	if err != nil && o.versionCheck == VersionCheckWarn {
+		o.versionWarn(err)
+		return nil
+	}
+	return err
+}
+
+// majorVersion returns the major component of version v, such as "0" for
+// "0.43".
+func majorVersion(v string) string {
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.IndexByte(v, '.'); i >= 0 {
+		return v[:i]
+	}
+	return v
+}
+
+var pathParam = regexp.MustCompile(`\{[^}]*\}`)
+
+// normalizePath replaces the parameter names in path template p, so that
+// templates differing only in parameter names compare equal.
+func normalizePath(p string) string {
+	return pathParam.ReplaceAllString(p, "{}")
+}
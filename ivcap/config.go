@@ -0,0 +1,266 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrNoContext is returned when a named context does not exist, or no context
+// was named and the configuration has no current context.
+var ErrNoContext = errors.New("ivcap: no such context")
+
+// Config holds the named deployment contexts of a user, as stored in
+// ~/.config/ivcap/config.yaml:
+//
+//	current-context: dev
+//	contexts:
+//	  - name: dev
+//	    url: https://develop.ivcap.net
+//	    token:
+//	      file: ~/.config/ivcap/dev-token.json
+//	    default-project: urn:ivcap:project:...
+//	    timeout: 1m
+type Config struct {
+	// CurrentContext is the name of the context used when none is named.
+	CurrentContext string `yaml:"current-context,omitempty"`
+	// Contexts lists the known contexts.
+	Contexts []*Context `yaml:"contexts"`
+}
+
+// Context describes how to reach and authenticate with an IVCAP deployment.
+type Context struct {
+	// Name identifies the context, such as "dev" or "prod".
+	Name string `yaml:"name"`
+	// URL is the base URL of the deployment, as passed to New.
+	URL string `yaml:"url"`
+	// Token selects the source of the tokens authorizing the requests.
+	Token TokenConfig `yaml:"token,omitempty"`
+	// DefaultProject is the URN of the project used when none is given.
+	DefaultProject string `yaml:"default-project,omitempty"`
+	// DefaultAccount is the URN of the account new projects are billed to
+	// when none is given.
+	DefaultAccount string `yaml:"default-account,omitempty"`
+	// Timeout is the timeout of each request. It defaults to 30 seconds.
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+}
+
+// TokenConfig selects the token source of a context. At most one field
+// should be set; they are tried in the order listed.
+type TokenConfig struct {
+	// JWT is a fixed token, see StaticTokenSource.
+	JWT string `yaml:"jwt,omitempty"`
+	// File is the path of a file holding the token, see FileTokenSource.
+	File string `yaml:"file,omitempty"`
+	// DeviceCode configures the device authorization grant, see
+	// DeviceCodeTokenSource.
+	DeviceCode *DeviceCodeConfig `yaml:"device-code,omitempty"`
+	// Env is the name of the environment variable holding the token, see
+	// EnvTokenSource.
+	Env string `yaml:"env,omitempty"`
+}
+
+// DefaultConfigPath returns the path of the configuration file: $IVCAP_CONFIG
+// if set, otherwise ivcap/config.yaml in the user configuration directory.
+func DefaultConfigPath() (string, error) {
+	if p := os.Getenv("IVCAP_CONFIG"); p != "" {
+		return p, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("ivcap: locating configuration: %w", err)
+	}
+	return filepath.Join(dir, "ivcap", "config.yaml"), nil
+}
+
+// LoadConfig reads the configuration file at path. A missing file yields an
+// empty configuration.
+func LoadConfig(path string) (*Config, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ivcap: reading configuration: %w", err)
+	}
+	var cfg Config
+	if err := yaml.Unmarshal(b, &cfg); err != nil {
+		return nil, fmt.Errorf("ivcap: decoding configuration %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// Save writes cfg to path, readable by the owner only as it may hold tokens.
+// The file is replaced atomically, so that a failed write leaves the previous
+// configuration in place.
+func (cfg *Config) Save(path string) error {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(cfg); err != nil {
+		return err
+	}
+	b := buf.Bytes()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("ivcap: writing configuration: %w", err)
+	}
+	if err := writeFileAtomic(path, b, 0600); err != nil {
+		return fmt.Errorf("ivcap: writing configuration: %w", err)
+	}
+	return nil
+}
+
+// Context returns the context called name, or the current context if name is
+// empty. It fails with ErrNoContext if there is no such context.
+func (cfg *Config) Context(name string) (*Context, error) {
+	if name == "" {
+		name = cfg.CurrentContext
+	}
+	if name == "" {
+		return nil, fmt.Errorf("%w: no current context", ErrNoContext)
+	}
+	for _, cx := range cfg.Contexts {
+		if cx.Name == name {
+			return cx, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", ErrNoContext, name)
+}
+
+// Use makes the context called name the current context.
+func (cfg *Config) Use(name string) error {
+	if _, err := cfg.Context(name); err != nil {
+		return err
+	}
+	cfg.CurrentContext = name
+	return nil
+}
+
+// Set adds cx to cfg, replacing the context of the same name if any.
+func (cfg *Config) Set(cx *Context) {
+	for i, old := range cfg.Contexts {
+		if old.Name == cx.Name {
+			cfg.Contexts[i] = cx
+			return
+		}
+	}
+	cfg.Contexts = append(cfg.Contexts, cx)
+}
+
+// Delete removes the context called name. The current context is cleared if
+// it is the one removed.
+func (cfg *Config) Delete(name string) error {
+	for i, cx := range cfg.Contexts {
+		if cx.Name == name {
+			cfg.Contexts = append(cfg.Contexts[:i], cfg.Contexts[i+1:]...)
+			if cfg.CurrentContext == name {
+				cfg.CurrentContext = ""
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %q", ErrNoContext, name)
+}
+
+// TokenSource returns the token source selected by c, or nil if no source is
+// configured. A leading "~/" in File is expanded to the home directory.
+func (c TokenConfig) TokenSource() TokenSource {
+	switch {
+	case c.JWT != "":
+		return StaticTokenSource(c.JWT)
+	case c.File != "":
+		return FileTokenSource(expandHome(c.File))
+	case c.DeviceCode != nil:
+		dc := *c.DeviceCode
+		dc.CachePath = expandHome(dc.CachePath)
+		return DeviceCodeTokenSource(dc)
+	case c.Env != "":
+		return EnvTokenSource(c.Env)
+	}
+	return nil
+}
+
+// Options returns the client options configured by cx. Options passed to
+// New after them take precedence.
+func (cx *Context) Options() []Option {
+	var opts []Option
+	if src := cx.Token.TokenSource(); src != nil {
+		opts = append(opts, WithTokenSource(src))
+	}
+	if cx.Timeout > 0 {
+		opts = append(opts, WithTimeout(cx.Timeout))
+	}
+	return append(opts, func(o *options) {
+		o.project = cx.DefaultProject
+		o.account = cx.DefaultAccount
+	})
+}
+
+// NewFromContext returns a client for the deployment of cx, configured by cx
+// and then opts.
+func NewFromContext(cx *Context, opts ...Option) (*Client, error) {
+	return New(cx.URL, append(cx.Options(), opts...)...)
+}
+
+// NewFromConfig returns a client for the context called name, or the current
+// context if name is empty, of the configuration file at DefaultConfigPath.
+func NewFromConfig(name string, opts ...Option) (*Client, error) {
+	path, err := DefaultConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	cx, err := cfg.Context(name)
+	if err != nil {
+		return nil, err
+	}
+	return NewFromContext(cx, opts...)
+}
+
+// DefaultProject returns the URN of the default project of the context the
+// client was created from, or "" if none is configured. The project service
+// also records a default project per user, see ProjectsClient.DefaultProject.
+func (c *Client) DefaultProject() string {
+	return c.project
+}
+
+// DefaultAccount returns the URN of the default account of the context the
+// client was created from, or "" if none is configured.
+func (c *Client) DefaultAccount() string {
+	return c.account
+}
+
+// expandHome replaces a leading "~/" in path with the home directory.
+func expandHome(path string) string {
+	if len(path) < 2 || path[:2] != "~/" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[2:])
+}
@@ -0,0 +1,59 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ivcap-works/ivcap-core-api/ivcap"
+)
+
+func TestConfigSave(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "ivcap")
+	path := filepath.Join(dir, "config.yaml")
+	for _, contexts := range []int{3, 1} {
+		cfg := &ivcap.Config{CurrentContext: "c0"}
+		for i := 0; i < contexts; i++ {
+			cfg.Contexts = append(cfg.Contexts, &ivcap.Context{Name: "c" + string(rune('0'+i)), URL: "https://ivcap.example.com"})
+		}
+		if err := cfg.Save(path); err != nil {
+			t.Fatal(err)
+		}
+		got, err := ivcap.LoadConfig(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got.Contexts) != contexts || got.CurrentContext != "c0" {
+			t.Errorf("loaded %+v, want %d contexts", got, contexts)
+		}
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode().Perm() != 0600 {
+		t.Errorf("mode %v, want 0600", fi.Mode())
+	}
+	// The configuration is written to a temporary file renamed over it.
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("files left in %s: %v", dir, entries)
+	}
+}
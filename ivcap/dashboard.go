@@ -0,0 +1,65 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap
+
+import (
+	"context"
+	"net/http"
+
+	dashboard "github.com/ivcap-works/ivcap-core-api/gen/dashboard"
+	dashboardc "github.com/ivcap-works/ivcap-core-api/http/dashboard"
+	goahttp "goa.design/goa/v3/http"
+	goa "goa.design/goa/v3/pkg"
+)
+
+// DashboardsClient gives typed access to the dashboard service.
+type DashboardsClient struct {
+	c    *Client
+	list goa.Endpoint
+}
+
+func newDashboardsClient(c *Client, enc func(*http.Request) goahttp.Encoder, dec func(*http.Response) goahttp.Decoder, restoreBody bool) *DashboardsClient {
+	hc := dashboardc.NewClient(c.scheme, c.host, c.doer, enc, dec, restoreBody)
+	return &DashboardsClient{
+		c:    c,
+		list: c.endpoint(dashboard.ServiceName, "list", hc.List()),
+	}
+}
+
+// List returns a page of dashboards.
+func (s *DashboardsClient) List(ctx context.Context, p *dashboard.ListPayload) (*dashboard.DashboardListRT, error) {
+	var q dashboard.ListPayload
+	if p != nil {
+		q = *p
+	}
+	return invoke[*dashboard.DashboardListRT](ctx, s.list, &q)
+}
+
+// ListIter returns an iterator over all dashboards matching p. The dashboard
+// list is not paginated, so a single request is made.
+func (s *DashboardsClient) ListIter(ctx context.Context, p *dashboard.ListPayload) *Iterator[*dashboard.DashboardListItem] {
+	var q dashboard.ListPayload
+	if p != nil {
+		q = *p
+	}
+	q.Limit = pageLimit(q.Limit)
+	return newIterator(ctx, func(ctx context.Context, page string) ([]*dashboard.DashboardListItem, string, error) {
+		res, err := s.List(ctx, &q)
+		if err != nil {
+			return nil, "", err
+		}
+		return res.Items, "", nil
+	})
+}
@@ -0,0 +1,97 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/ivcap-works/ivcap-core-api/ivcaperr"
+	goahttp "goa.design/goa/v3/http"
+	goa "goa.design/goa/v3/pkg"
+)
+
+// exchange records the outcome of the HTTP exchange of a method call so the
+// endpoint middleware can classify errors and decide whether to retry.
+type exchange struct {
+	status       int
+	requestID    string
+	retryAfter   time.Duration
+	transportErr bool
+	authErr      error
+}
+
+type exchangeKey struct{}
+
+// withExchange returns ctx carrying a fresh exchange record.
+func withExchange(ctx context.Context) (context.Context, *exchange) {
+	ex := &exchange{}
+	return context.WithValue(ctx, exchangeKey{}, ex), ex
+}
+
+// exchangeFrom returns the exchange record of ctx, if any.
+func exchangeFrom(ctx context.Context) *exchange {
+	ex, _ := ctx.Value(exchangeKey{}).(*exchange)
+	return ex
+}
+
+// recordingDoer records the outcome of each exchange in the exchange record
+// of the request context.
+type recordingDoer struct {
+	doer goahttp.Doer
+}
+
+func (d *recordingDoer) Do(req *http.Request) (*http.Response, error) {
+	resp, err := d.doer.Do(req)
+	ex := exchangeFrom(req.Context())
+	if ex == nil {
+		return resp, err
+	}
+	if err != nil {
+		ex.transportErr = req.Context().Err() == nil
+		return resp, err
+	}
+	ex.status = resp.StatusCode
+	ex.requestID = resp.Header.Get("X-Request-Id")
+	ex.retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+	return resp, err
+}
+
+// errorMiddleware returns the endpoint middleware wrapping the errors of
+// method of service svc into *ivcaperr.Error values.
+func errorMiddleware(svc, method string) func(goa.Endpoint) goa.Endpoint {
+	return func(ep goa.Endpoint) goa.Endpoint {
+		return func(ctx context.Context, req any) (any, error) {
+			ex := exchangeFrom(ctx)
+			if ex == nil {
+				ctx, ex = withExchange(ctx)
+			}
+			res, err := ep(ctx, req)
+			if err == nil {
+				return res, nil
+			}
+			if ex.authErr != nil {
+				return nil, &ivcaperr.Error{
+					Kind:    ivcaperr.ErrUnauthorized,
+					Service: svc,
+					Method:  method,
+					Err:     ex.authErr,
+				}
+			}
+			return nil, ivcaperr.Wrap(err, svc, method, ex.status, ex.requestID)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap
+
+import (
+	"context"
+	"net/http"
+
+	metadata "github.com/ivcap-works/ivcap-core-api/gen/metadata"
+	metadatac "github.com/ivcap-works/ivcap-core-api/http/metadata"
+	goahttp "goa.design/goa/v3/http"
+	goa "goa.design/goa/v3/pkg"
+)
+
+// MetadataClient gives typed access to the metadata service.
+type MetadataClient struct {
+	c            *Client
+	read         goa.Endpoint
+	list         goa.Endpoint
+	add          goa.Endpoint
+	updateRecord goa.Endpoint
+	revoke       goa.Endpoint
+}
+
+func newMetadataClient(c *Client, enc func(*http.Request) goahttp.Encoder, dec func(*http.Response) goahttp.Decoder, restoreBody bool) *MetadataClient {
+	hc := metadatac.NewClient(c.scheme, c.host, c.doer, enc, dec, restoreBody)
+	return &MetadataClient{
+		c:            c,
+		read:         c.endpoint(metadata.ServiceName, "read", hc.Read()),
+		list:         c.endpoint(metadata.ServiceName, "list", hc.List()),
+		add:          c.endpoint(metadata.ServiceName, "add", hc.Add()),
+		updateRecord: c.endpoint(metadata.ServiceName, "update_record", hc.UpdateRecord()),
+		revoke:       c.endpoint(metadata.ServiceName, "revoke", hc.Revoke()),
+	}
+}
+
+// Read returns the metadata record with the given ID. An aspect with a schema
+// registered with RegisterSchema is decoded into its type; if it does not
+// match, Read fails with a *ContentError.
+func (s *MetadataClient) Read(ctx context.Context, id string) (*metadata.MetadataRecordRT, error) {
+	p := &metadata.ReadPayload{ID: id}
+	res, err := invoke[*metadata.MetadataRecordRT](ctx, s.read, p)
+	if err != nil {
+		return nil, err
+	}
+	if err := decodeContent(res.Schema, res.ID, &res.Aspect); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// List returns a page of metadata records. Aspects with a schema registered
+// with RegisterSchema are decoded into its type, and left as read if they do
+// not match; ListIter reports the *ContentError of such records.
+func (s *MetadataClient) List(ctx context.Context, p *metadata.ListPayload) (*metadata.ListMetaRT, error) {
+	res, err := s.listRaw(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range res.Items {
+		decodeContent(r.Schema, r.ID, &r.Aspect)
+	}
+	return res, nil
+}
+
+// listRaw returns a page of metadata records, leaving their aspects as read.
+func (s *MetadataClient) listRaw(ctx context.Context, p *metadata.ListPayload) (*metadata.ListMetaRT, error) {
+	var q metadata.ListPayload
+	if p != nil {
+		q = *p
+	}
+	return invoke[*metadata.ListMetaRT](ctx, s.list, &q)
+}
+
+// Add attaches new metadata to an entity. An aspect with a schema registered
+// with RegisterSchema is checked against its type first.
+func (s *MetadataClient) Add(ctx context.Context, p *metadata.AddPayload) (*metadata.AddMetaRT, error) {
+	var q metadata.AddPayload
+	if p != nil {
+		q = *p
+	}
+	if err := checkContent(q.Schema, q.Aspect); err != nil {
+		return nil, err
+	}
+	return invoke[*metadata.AddMetaRT](ctx, s.add, &q)
+}
+
+// UpdateRecord revokes a record and creates a new one in its place. An
+// aspect with a schema registered with RegisterSchema is checked against its
+// type first.
+func (s *MetadataClient) UpdateRecord(ctx context.Context, p *metadata.UpdateRecordPayload) (*metadata.AddMetaRT, error) {
+	var q metadata.UpdateRecordPayload
+	if p != nil {
+		q = *p
+	}
+	if q.Schema != nil {
+		if err := checkContent(*q.Schema, q.Aspect); err != nil {
+			return nil, err
+		}
+	}
+	return invoke[*metadata.AddMetaRT](ctx, s.updateRecord, &q)
+}
+
+// Revoke retracts the metadata record with the given ID.
+func (s *MetadataClient) Revoke(ctx context.Context, id string) error {
+	p := &metadata.RevokePayload{ID: &id}
+	_, err := s.revoke(ctx, p)
+	return err
+}
+
+// ListIter returns an iterator over all metadata records matching p, following the next page links.
+// An aspect with a schema registered with RegisterSchema is decoded into its
+// type; if it does not match, the record is returned as read and ItemErr
+// returns a *ContentError.
+func (s *MetadataClient) ListIter(ctx context.Context, p *metadata.ListPayload) *Iterator[*metadata.MetadataListItemRT] {
+	var q metadata.ListPayload
+	if p != nil {
+		q = *p
+	}
+	q.Limit = pageLimit(q.Limit)
+	it := newIterator(ctx, func(ctx context.Context, page string) ([]*metadata.MetadataListItemRT, string, error) {
+		if page != "" {
+			q.Page = &page
+		}
+		res, err := s.listRaw(ctx, &q)
+		if err != nil {
+			return nil, "", err
+		}
+		return res.Items, nextPage(res.Links, func(l *metadata.LinkT) (string, string) { return l.Rel, l.Href }), nil
+	})
+	it.check = func(r *metadata.MetadataListItemRT) error {
+		return decodeContent(r.Schema, r.ID, &r.Aspect)
+	}
+	return it
+}
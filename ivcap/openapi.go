@@ -0,0 +1,151 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	openapi "github.com/ivcap-works/ivcap-core-api/gen/openapi"
+	goahttp "goa.design/goa/v3/http"
+	goa "goa.design/goa/v3/pkg"
+)
+
+// specPath is the path of the OpenAPI document served by a deployment.
+const specPath = "/1/openapi/openapi3.json"
+
+// OpenAPIClient gives access to the openapi service.
+type OpenAPIClient struct {
+	c         *Client
+	fetchSpec goa.Endpoint
+}
+
+func newOpenAPIClient(c *Client, enc func(*http.Request) goahttp.Encoder, dec func(*http.Response) goahttp.Decoder, restoreBody bool) *OpenAPIClient {
+	s := &OpenAPIClient{c: c}
+	s.fetchSpec = c.endpoint(openapi.ServiceName, "fetch-spec", s.fetchSpecEndpoint())
+	return s
+}
+
+// OpenAPISpec is the OpenAPI 3 document served by a deployment.
+type OpenAPISpec struct {
+	// OpenAPI is the version of the OpenAPI specification the document
+	// follows.
+	OpenAPI string
+	Info    *OpenAPIInfo
+	// Paths lists the operations served, by path and upper case HTTP method.
+	Paths map[string]map[string]*OpenAPIOperation
+	// Document is the raw JSON document.
+	Document []byte
+}
+
+// OpenAPIInfo describes the API.
+type OpenAPIInfo struct {
+	Title string `json:"title"`
+	// Version of the API, such as "0.43".
+	Version     string  `json:"version"`
+	Description *string `json:"description,omitempty"`
+}
+
+// OpenAPIOperation describes an operation of the API.
+type OpenAPIOperation struct {
+	// OperationID identifies the operation, such as "order#create".
+	OperationID *string  `json:"operationId,omitempty"`
+	Summary     *string  `json:"summary,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+}
+
+// FetchSpec returns the OpenAPI document served by the deployment.
+func (s *OpenAPIClient) FetchSpec(ctx context.Context) (*OpenAPISpec, error) {
+	return invoke[*OpenAPISpec](ctx, s.fetchSpec, nil)
+}
+
+// fetchSpecEndpoint returns the endpoint fetching the OpenAPI document of
+// the deployment, which the design of the openapi service leaves out.
+func (s *OpenAPIClient) fetchSpecEndpoint() goa.Endpoint {
+	return func(ctx context.Context, _ any) (any, error) {
+		u := (&url.URL{Scheme: s.c.scheme, Host: s.c.host, Path: specPath}).String()
+		req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+		if err != nil {
+			return nil, goahttp.ErrInvalidURL("openapi", "fetch-spec", u, err)
+		}
+		resp, err := s.c.doer.Do(req)
+		if err != nil {
+			return nil, goahttp.ErrRequestError("openapi", "fetch-spec", err)
+		}
+		defer resp.Body.Close()
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, goahttp.ErrDecodingError("openapi", "fetch-spec", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, goahttp.ErrInvalidResponse("openapi", "fetch-spec", resp.StatusCode, string(b))
+		}
+		spec, err := parseSpec(b)
+		if err != nil {
+			return nil, goahttp.ErrDecodingError("openapi", "fetch-spec", err)
+		}
+		return spec, nil
+	}
+}
+
+// parseSpec decodes the OpenAPI document b. Path item fields which are not
+// operations are ignored.
+func parseSpec(b []byte) (*OpenAPISpec, error) {
+	var doc struct {
+		OpenAPI string                                `json:"openapi"`
+		Info    *OpenAPIInfo                          `json:"info"`
+		Paths   map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+	var err error
+	if doc.OpenAPI == "" {
+		err = goa.MergeErrors(err, goa.MissingFieldError("openapi", "body"))
+	}
+	if doc.Info == nil || doc.Info.Version == "" {
+		err = goa.MergeErrors(err, goa.MissingFieldError("info.version", "body"))
+	}
+	if err != nil {
+		return nil, err
+	}
+	spec := &OpenAPISpec{
+		OpenAPI:  doc.OpenAPI,
+		Info:     doc.Info,
+		Paths:    make(map[string]map[string]*OpenAPIOperation, len(doc.Paths)),
+		Document: b,
+	}
+	for path, item := range doc.Paths {
+		ops := make(map[string]*OpenAPIOperation, len(item))
+		for method, raw := range item {
+			switch method {
+			case "get", "put", "post", "delete", "options", "head", "patch", "trace":
+			default:
+				continue
+			}
+			var op OpenAPIOperation
+			if err := json.Unmarshal(raw, &op); err != nil {
+				return nil, err
+			}
+			ops[strings.ToUpper(method)] = &op
+		}
+		spec.Paths[path] = ops
+	}
+	return spec, nil
+}
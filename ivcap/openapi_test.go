@@ -0,0 +1,99 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap_test
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	openapi "github.com/ivcap-works/ivcap-core-api/gen/openapi"
+	"github.com/ivcap-works/ivcap-core-api/ivcap"
+	"github.com/ivcap-works/ivcap-core-api/ivcaptest"
+)
+
+func TestFetchSpec(t *testing.T) {
+	// The fakes serve the document of this module, as a deployment of the
+	// same version would.
+	doc, err := os.ReadFile(filepath.Join("..", "openapi3.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := ivcaptest.New()
+	d.OpenAPISpec = doc
+	srv := httptest.NewServer(d.Handler())
+	defer srv.Close()
+	c, err := ivcap.New(srv.URL, ivcap.WithJWT(ivcaptest.Token("urn:ivcap:user:alice")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	spec, err := c.OpenAPI.FetchSpec(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spec.Info.Version != openapi.APIVersion {
+		t.Errorf("version = %q, want %q", spec.Info.Version, openapi.APIVersion)
+	}
+	if op := spec.Paths["/1/aspects"]["POST"]; op == nil || op.OperationID == nil || *op.OperationID != "aspect#create" {
+		t.Errorf("POST /1/aspects = %+v", op)
+	}
+	if !bytes.Equal(spec.Document, doc) {
+		t.Error("raw document differs from openapi3.json")
+	}
+	r, err := c.CheckCompatibility(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.Compatible() {
+		t.Errorf("client not compatible with openapi3.json: %+v", r)
+	}
+}
+
+func TestCheckSpec(t *testing.T) {
+	all := map[string]map[string]*ivcap.OpenAPIOperation{}
+	for _, op := range ivcap.Operations {
+		if all[op.Path] == nil {
+			all[op.Path] = map[string]*ivcap.OpenAPIOperation{}
+		}
+		all[op.Path][op.HTTPMethod] = &ivcap.OpenAPIOperation{}
+	}
+	tests := []struct {
+		name       string
+		version    string
+		drop       string
+		compatible bool
+	}{
+		{name: "same", version: openapi.APIVersion, compatible: true},
+		{name: "other major", version: "99.0"},
+		{name: "missing operation", version: openapi.APIVersion, drop: "/1/aspects"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &ivcap.OpenAPISpec{Info: &ivcap.OpenAPIInfo{Version: tt.version}, Paths: map[string]map[string]*ivcap.OpenAPIOperation{}}
+			for path, ops := range all {
+				if path != tt.drop {
+					spec.Paths[path] = ops
+				}
+			}
+			if got := ivcap.CheckSpec(spec).Compatible(); got != tt.compatible {
+				t.Errorf("Compatible() = %v, want %v", got, tt.compatible)
+			}
+		})
+	}
+}
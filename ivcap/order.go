@@ -0,0 +1,168 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	order "github.com/ivcap-works/ivcap-core-api/gen/order"
+	orderc "github.com/ivcap-works/ivcap-core-api/http/order"
+	goahttp "goa.design/goa/v3/http"
+	goa "goa.design/goa/v3/pkg"
+)
+
+// OrdersClient gives typed access to the order service.
+type OrdersClient struct {
+	c        *Client
+	list     goa.Endpoint
+	read     goa.Endpoint
+	products goa.Endpoint
+	metadata goa.Endpoint
+	create   goa.Endpoint
+	logs     goa.Endpoint
+	top      goa.Endpoint
+}
+
+func newOrdersClient(c *Client, enc func(*http.Request) goahttp.Encoder, dec func(*http.Response) goahttp.Decoder, restoreBody bool) *OrdersClient {
+	hc := orderc.NewClient(c.scheme, c.host, c.doer, enc, dec, restoreBody)
+	return &OrdersClient{
+		c:        c,
+		list:     c.endpoint(order.ServiceName, "list", hc.List()),
+		read:     c.endpoint(order.ServiceName, "read", hc.Read()),
+		products: c.endpoint(order.ServiceName, "products", hc.Products()),
+		metadata: c.endpoint(order.ServiceName, "metadata", hc.Metadata()),
+		create:   c.endpoint(order.ServiceName, "create", hc.Create()),
+		logs:     c.endpoint(order.ServiceName, "logs", hc.Logs()),
+		top:      c.endpoint(order.ServiceName, "top", hc.Top()),
+	}
+}
+
+// List returns a page of orders.
+func (s *OrdersClient) List(ctx context.Context, p *order.ListPayload) (*order.OrderListRT, error) {
+	var q order.ListPayload
+	if p != nil {
+		q = *p
+	}
+	return invoke[*order.OrderListRT](ctx, s.list, &q)
+}
+
+// Read returns the status of the order with the given ID.
+func (s *OrdersClient) Read(ctx context.Context, id string) (*order.OrderStatusRT, error) {
+	p := &order.ReadPayload{ID: id}
+	return invoke[*order.OrderStatusRT](ctx, s.read, p)
+}
+
+// Products returns a page of the products created by an order.
+func (s *OrdersClient) Products(ctx context.Context, p *order.ProductsPayload) (*order.PartialProductListT, error) {
+	var q order.ProductsPayload
+	if p != nil {
+		q = *p
+	}
+	return invoke[*order.PartialProductListT](ctx, s.products, &q)
+}
+
+// Metadata returns a page of the metadata created by an order.
+func (s *OrdersClient) Metadata(ctx context.Context, p *order.MetadataPayload) (*order.PartialMetaListT, error) {
+	var q order.MetadataPayload
+	if p != nil {
+		q = *p
+	}
+	return invoke[*order.PartialMetaListT](ctx, s.metadata, &q)
+}
+
+// Create places a new order and returns its status.
+func (s *OrdersClient) Create(ctx context.Context, req *order.OrderRequestT) (*order.OrderStatusRT, error) {
+	p := &order.CreatePayload{Orders: req}
+	return invoke[*order.OrderStatusRT](ctx, s.create, p)
+}
+
+// Logs streams the logs of an order. The caller must close the returned
+// reader.
+func (s *OrdersClient) Logs(ctx context.Context, p *order.LogsPayload) (io.ReadCloser, error) {
+	var q order.LogsPayload
+	if p != nil {
+		q = *p
+	}
+	res, err := invoke[*order.LogsResponseData](ctx, s.logs, &q)
+	if err != nil {
+		return nil, err
+	}
+	return res.Body, nil
+}
+
+// Top returns the resource usage of the order with the given ID.
+func (s *OrdersClient) Top(ctx context.Context, orderID string) (order.OrderTopResultItemCollection, error) {
+	p := &order.TopPayload{OrderID: orderID}
+	return invoke[order.OrderTopResultItemCollection](ctx, s.top, p)
+}
+
+// ListIter returns an iterator over all orders matching p, following the next page links.
+func (s *OrdersClient) ListIter(ctx context.Context, p *order.ListPayload) *Iterator[*order.OrderListItem] {
+	var q order.ListPayload
+	if p != nil {
+		q = *p
+	}
+	q.Limit = pageLimit(q.Limit)
+	return newIterator(ctx, func(ctx context.Context, page string) ([]*order.OrderListItem, string, error) {
+		if page != "" {
+			q.Page = &page
+		}
+		res, err := s.List(ctx, &q)
+		if err != nil {
+			return nil, "", err
+		}
+		return res.Items, nextPage(res.Links, func(l *order.LinkT) (string, string) { return l.Rel, l.Href }), nil
+	})
+}
+
+// ProductsIter returns an iterator over all products created by an order, following the next page links.
+func (s *OrdersClient) ProductsIter(ctx context.Context, p *order.ProductsPayload) *Iterator[*order.ProductListItemT] {
+	var q order.ProductsPayload
+	if p != nil {
+		q = *p
+	}
+	q.Limit = pageLimit(q.Limit)
+	return newIterator(ctx, func(ctx context.Context, page string) ([]*order.ProductListItemT, string, error) {
+		if page != "" {
+			q.Page = &page
+		}
+		res, err := s.Products(ctx, &q)
+		if err != nil {
+			return nil, "", err
+		}
+		return res.Items, nextPage(res.Links, func(l *order.LinkT) (string, string) { return l.Rel, l.Href }), nil
+	})
+}
+
+// MetadataIter returns an iterator over all metadata created by an order, following the next page links.
+func (s *OrdersClient) MetadataIter(ctx context.Context, p *order.MetadataPayload) *Iterator[*order.OrderMetadataListItemRT] {
+	var q order.MetadataPayload
+	if p != nil {
+		q = *p
+	}
+	q.Limit = pageLimit(q.Limit)
+	return newIterator(ctx, func(ctx context.Context, page string) ([]*order.OrderMetadataListItemRT, string, error) {
+		if page != "" {
+			q.Page = &page
+		}
+		res, err := s.Metadata(ctx, &q)
+		if err != nil {
+			return nil, "", err
+		}
+		return res.Items, nextPage(res.Links, func(l *order.LinkT) (string, string) { return l.Rel, l.Href }), nil
+	})
+}
@@ -0,0 +1,116 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	package_ "github.com/ivcap-works/ivcap-core-api/gen/package_"
+	package_c "github.com/ivcap-works/ivcap-core-api/http/package_"
+	goahttp "goa.design/goa/v3/http"
+	goa "goa.design/goa/v3/pkg"
+)
+
+// PackagesClient gives typed access to the package service.
+type PackagesClient struct {
+	c      *Client
+	list   goa.Endpoint
+	pull   goa.Endpoint
+	push   goa.Endpoint
+	status goa.Endpoint
+	remove goa.Endpoint
+}
+
+func newPackagesClient(c *Client, enc func(*http.Request) goahttp.Encoder, dec func(*http.Response) goahttp.Decoder, restoreBody bool) *PackagesClient {
+	hc := package_c.NewClient(c.scheme, c.host, c.doer, enc, dec, restoreBody)
+	return &PackagesClient{
+		c:      c,
+		list:   c.endpoint(package_.ServiceName, "list", hc.List()),
+		pull:   c.endpoint(package_.ServiceName, "pull", hc.Pull()),
+		push:   c.endpoint(package_.ServiceName, "push", hc.Push()),
+		status: c.endpoint(package_.ServiceName, "status", hc.Status()),
+		remove: c.endpoint(package_.ServiceName, "remove", hc.Remove()),
+	}
+}
+
+// List returns a page of the docker images under the caller's account.
+func (s *PackagesClient) List(ctx context.Context, p *package_.ListPayload) (*package_.ListResult, error) {
+	var q package_.ListPayload
+	if p != nil {
+		q = *p
+	}
+	return invoke[*package_.ListResult](ctx, s.list, &q)
+}
+
+// Pull streams a manifest, config or layer of a docker image. The caller must
+// close the returned reader.
+func (s *PackagesClient) Pull(ctx context.Context, p *package_.PullPayload) (*package_.PullResultT, io.ReadCloser, error) {
+	var q package_.PullPayload
+	if p != nil {
+		q = *p
+	}
+	res, err := invoke[*package_.PullResponseData](ctx, s.pull, &q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return res.Result, res.Body, nil
+}
+
+// Push uploads a manifest, config or layer of a docker image read from body.
+func (s *PackagesClient) Push(ctx context.Context, p *package_.PushPayload, body io.Reader) (*package_.PushResult, error) {
+	var q package_.PushPayload
+	if p != nil {
+		q = *p
+	}
+	rc, ok := body.(io.ReadCloser)
+	if !ok {
+		rc = io.NopCloser(body)
+	}
+	return invoke[*package_.PushResult](ctx, s.push, &package_.PushRequestData{Payload: &q, Body: rc})
+}
+
+// Status returns the push status of an image layer.
+func (s *PackagesClient) Status(ctx context.Context, tag, digest string) (*package_.PushStatusT, error) {
+	p := &package_.StatusPayload{Tag: tag, Digest: digest}
+	return invoke[*package_.PushStatusT](ctx, s.status, p)
+}
+
+// Remove deletes the docker image with the given tag.
+func (s *PackagesClient) Remove(ctx context.Context, tag string) error {
+	p := &package_.RemovePayload{Tag: tag}
+	_, err := s.remove(ctx, p)
+	return err
+}
+
+// ListIter returns an iterator over all docker image tags matching p,
+// following the next page links.
+func (s *PackagesClient) ListIter(ctx context.Context, p *package_.ListPayload) *Iterator[string] {
+	var q package_.ListPayload
+	if p != nil {
+		q = *p
+	}
+	return newIterator(ctx, func(ctx context.Context, page string) ([]string, string, error) {
+		if page != "" {
+			q.Page = &page
+		}
+		res, err := s.List(ctx, &q)
+		if err != nil {
+			return nil, "", err
+		}
+		return res.Items, nextPage(res.Links, func(l *package_.LinkT) (string, string) { return l.Rel, l.Href }), nil
+	})
+}
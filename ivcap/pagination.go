@@ -0,0 +1,168 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+const (
+	// DefaultPageSize is the page size iterators request when the payload
+	// does not set a limit.
+	DefaultPageSize = 10
+	// MaxPageSize is the largest page size accepted by the list methods.
+	MaxPageSize = 50
+)
+
+// Iterator walks through all the items of a paginated list, fetching further
+// pages on demand.
+//
+//	it := c.Artifacts.ListIter(ctx, &artifact.ListPayload{})
+//	for it.Next() {
+//		item := it.Item()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator[T any] struct {
+	ctx     context.Context
+	fetch   pageFetcher[T]
+	check   func(T) error
+	items   []T
+	item    T
+	itemErr error
+	page    string
+	seen    map[string]bool
+	done    bool
+	err     error
+}
+
+// pageFetcher returns the items of the page with the given token, or of the
+// first page if page is empty, together with the token of the next page.
+type pageFetcher[T any] func(ctx context.Context, page string) (items []T, next string, err error)
+
+func newIterator[T any](ctx context.Context, fetch pageFetcher[T]) *Iterator[T] {
+	return &Iterator[T]{ctx: ctx, fetch: fetch, seen: map[string]bool{}}
+}
+
+// Next advances the iterator to the next item, fetching the next page if
+// needed. It returns false when there are no more items, the context is done
+// or a request failed.
+func (it *Iterator[T]) Next() bool {
+	for len(it.items) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		if err := it.ctx.Err(); err != nil {
+			it.err = err
+			return false
+		}
+		items, next, err := it.fetch(it.ctx, it.page)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.seen[it.page] = true
+		// Stop when the server does not offer a next page, or points back to
+		// one already visited.
+		if next == "" || it.seen[next] {
+			it.done = true
+		}
+		it.items, it.page = items, next
+	}
+	it.item, it.items = it.items[0], it.items[1:]
+	it.itemErr = nil
+	if it.check != nil {
+		it.itemErr = it.check(it.item)
+	}
+	return true
+}
+
+// Item returns the current item. It is only valid after Next returned true.
+func (it *Iterator[T]) Item() T {
+	return it.item
+}
+
+// ItemErr returns the error found with the current item, which does not stop
+// the iteration, such as a *ContentError for content which does not decode
+// into the type registered for its schema. The item is then returned as
+// read. It is only valid after Next returned true.
+func (it *Iterator[T]) ItemErr() error {
+	return it.itemErr
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// Collect drains the iterator and returns all remaining items.
+func (it *Iterator[T]) Collect() ([]T, error) {
+	var all []T
+	for it.Next() {
+		all = append(all, it.Item())
+	}
+	return all, it.Err()
+}
+
+// pageLimit clamps limit to the range accepted by the list methods.
+func pageLimit(limit int) int {
+	if limit < 1 {
+		return DefaultPageSize
+	}
+	if limit > MaxPageSize {
+		return MaxPageSize
+	}
+	return limit
+}
+
+// nextLink returns the href of the "next" link in links, or "" if there is
+// none. attrs returns the relation and href of a link.
+func nextLink[L any](links []L, attrs func(L) (rel, href string)) string {
+	for _, l := range links {
+		rel, href := attrs(l)
+		for _, r := range strings.Fields(strings.ReplaceAll(rel, ",", " ")) {
+			if strings.EqualFold(r, "next") {
+				return href
+			}
+		}
+	}
+	return ""
+}
+
+// linkParam returns the value of the query parameter name in href. Links
+// which are neither absolute URLs nor paths are taken to be the value itself.
+func linkParam(href, name string) string {
+	if href == "" {
+		return ""
+	}
+	u, err := url.Parse(href)
+	if err != nil || (u.Scheme == "" && u.Host == "" && !strings.HasPrefix(u.Path, "/") && u.RawQuery == "") {
+		return href
+	}
+	q := u.Query()
+	if v := q.Get(name); v != "" {
+		return v
+	}
+	return q.Get("$" + name)
+}
+
+// nextPage returns the page token of the "next" link in links.
+func nextPage[L any](links []L, attrs func(L) (rel, href string)) string {
+	return linkParam(nextLink(links, attrs), "page")
+}
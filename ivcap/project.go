@@ -0,0 +1,188 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap
+
+import (
+	"context"
+	"net/http"
+
+	project "github.com/ivcap-works/ivcap-core-api/gen/project"
+	projectc "github.com/ivcap-works/ivcap-core-api/http/project"
+	goahttp "goa.design/goa/v3/http"
+	goa "goa.design/goa/v3/pkg"
+)
+
+// ProjectsClient gives typed access to the project service.
+type ProjectsClient struct {
+	c                  *Client
+	list               goa.Endpoint
+	createProject      goa.Endpoint
+	delete             goa.Endpoint
+	read               goa.Endpoint
+	listProjectMembers goa.Endpoint
+	updateMembership   goa.Endpoint
+	removeMembership   goa.Endpoint
+	defaultProject     goa.Endpoint
+	setDefaultProject  goa.Endpoint
+	projectAccount     goa.Endpoint
+	setProjectAccount  goa.Endpoint
+}
+
+func newProjectsClient(c *Client, enc func(*http.Request) goahttp.Encoder, dec func(*http.Response) goahttp.Decoder, restoreBody bool) *ProjectsClient {
+	hc := projectc.NewClient(c.scheme, c.host, c.doer, enc, dec, restoreBody)
+	return &ProjectsClient{
+		c:                  c,
+		list:               c.endpoint(project.ServiceName, "list", hc.List()),
+		createProject:      c.endpoint(project.ServiceName, "CreateProject", hc.CreateProject()),
+		delete:             c.endpoint(project.ServiceName, "delete", hc.Delete()),
+		read:               c.endpoint(project.ServiceName, "read", hc.Read()),
+		listProjectMembers: c.endpoint(project.ServiceName, "ListProjectMembers", hc.ListProjectMembers()),
+		updateMembership:   c.endpoint(project.ServiceName, "UpdateMembership", hc.UpdateMembership()),
+		removeMembership:   c.endpoint(project.ServiceName, "RemoveMembership", hc.RemoveMembership()),
+		defaultProject:     c.endpoint(project.ServiceName, "DefaultProject", hc.DefaultProject()),
+		setDefaultProject:  c.endpoint(project.ServiceName, "SetDefaultProject", hc.SetDefaultProject()),
+		projectAccount:     c.endpoint(project.ServiceName, "ProjectAccount", hc.ProjectAccount()),
+		setProjectAccount:  c.endpoint(project.ServiceName, "SetProjectAccount", hc.SetProjectAccount()),
+	}
+}
+
+// List returns a page of projects.
+func (s *ProjectsClient) List(ctx context.Context, p *project.ListPayload) (*project.ProjectListRT, error) {
+	var q project.ListPayload
+	if p != nil {
+		q = *p
+	}
+	return invoke[*project.ProjectListRT](ctx, s.list, &q)
+}
+
+// CreateProject creates a new project and returns its status. The project is
+// billed to the default account of the client, see Client.DefaultAccount,
+// unless req names an account.
+func (s *ProjectsClient) CreateProject(ctx context.Context, req *project.ProjectCreateRequest) (*project.ProjectStatusRT, error) {
+	var q project.ProjectCreateRequest
+	if req != nil {
+		q = *req
+	}
+	if q.AccountUrn == nil && s.c.account != "" {
+		q.AccountUrn = &s.c.account
+	}
+	p := &project.CreateProjectPayload{Project: &q}
+	return invoke[*project.ProjectStatusRT](ctx, s.createProject, p)
+}
+
+// Delete deletes the project with the given ID.
+func (s *ProjectsClient) Delete(ctx context.Context, id string) error {
+	p := &project.DeletePayload{ID: id}
+	_, err := s.delete(ctx, p)
+	return err
+}
+
+// Read returns the status of the project with the given ID.
+func (s *ProjectsClient) Read(ctx context.Context, id string) (*project.ProjectStatusRT, error) {
+	p := &project.ReadPayload{ID: id}
+	return invoke[*project.ProjectStatusRT](ctx, s.read, p)
+}
+
+// ListProjectMembers returns a page of the members of a project.
+func (s *ProjectsClient) ListProjectMembers(ctx context.Context, p *project.ListProjectMembersPayload) (*project.MembersList, error) {
+	var q project.ListProjectMembersPayload
+	if p != nil {
+		q = *p
+	}
+	return invoke[*project.MembersList](ctx, s.listProjectMembers, &q)
+}
+
+// UpdateMembership adds a user to a project or updates its role.
+func (s *ProjectsClient) UpdateMembership(ctx context.Context, projectURN, userURN, role string) error {
+	p := &project.UpdateMembershipPayload{ProjectUrn: projectURN, UserUrn: userURN, Role: role}
+	_, err := s.updateMembership(ctx, p)
+	return err
+}
+
+// RemoveMembership removes a user from a project.
+func (s *ProjectsClient) RemoveMembership(ctx context.Context, projectURN, userURN string) error {
+	p := &project.RemoveMembershipPayload{ProjectUrn: projectURN, UserUrn: userURN}
+	_, err := s.removeMembership(ctx, p)
+	return err
+}
+
+// DefaultProject returns the caller's current default project.
+func (s *ProjectsClient) DefaultProject(ctx context.Context) (*project.ProjectStatusRT, error) {
+	p := &project.DefaultProjectPayload{}
+	return invoke[*project.ProjectStatusRT](ctx, s.defaultProject, p)
+}
+
+// SetDefaultProject sets the default project of a user.
+func (s *ProjectsClient) SetDefaultProject(ctx context.Context, p *project.SetDefaultProjectPayload) error {
+	var q project.SetDefaultProjectPayload
+	if p != nil {
+		q = *p
+	}
+	_, err := s.setDefaultProject(ctx, &q)
+	return err
+}
+
+// ProjectAccount returns the billing account of a project.
+func (s *ProjectsClient) ProjectAccount(ctx context.Context, projectURN string) (*project.AccountResult, error) {
+	p := &project.ProjectAccountPayload{ProjectUrn: projectURN}
+	return invoke[*project.AccountResult](ctx, s.projectAccount, p)
+}
+
+// SetProjectAccount sets the billing account of a project.
+func (s *ProjectsClient) SetProjectAccount(ctx context.Context, projectURN, accountURN string) error {
+	p := &project.SetProjectAccountPayload{ProjectUrn: projectURN, AccountUrn: accountURN}
+	_, err := s.setProjectAccount(ctx, p)
+	return err
+}
+
+// ListIter returns an iterator over all projects matching p, following the
+// page tokens.
+func (s *ProjectsClient) ListIter(ctx context.Context, p *project.ListPayload) *Iterator[*project.ProjectListItem] {
+	var q project.ListPayload
+	if p != nil {
+		q = *p
+	}
+	q.Limit = pageLimit(q.Limit)
+	return newIterator(ctx, func(ctx context.Context, page string) ([]*project.ProjectListItem, string, error) {
+		if page != "" {
+			q.Page = &page
+		}
+		res, err := s.List(ctx, &q)
+		if err != nil {
+			return nil, "", err
+		}
+		return res.Projects, stringValue(res.Page), nil
+	})
+}
+
+// ListProjectMembersIter returns an iterator over all members of a project,
+// following the page tokens.
+func (s *ProjectsClient) ListProjectMembersIter(ctx context.Context, p *project.ListProjectMembersPayload) *Iterator[*project.UserListItem] {
+	var q project.ListProjectMembersPayload
+	if p != nil {
+		q = *p
+	}
+	q.Limit = pageLimit(q.Limit)
+	return newIterator(ctx, func(ctx context.Context, page string) ([]*project.UserListItem, string, error) {
+		if page != "" {
+			q.Page = &page
+		}
+		res, err := s.ListProjectMembers(ctx, &q)
+		if err != nil {
+			return nil, "", err
+		}
+		return res.Members, stringValue(res.Page), nil
+	})
+}
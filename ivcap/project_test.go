@@ -0,0 +1,65 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	project "github.com/ivcap-works/ivcap-core-api/gen/project"
+	"github.com/ivcap-works/ivcap-core-api/ivcap"
+	"github.com/ivcap-works/ivcap-core-api/ivcaptest"
+)
+
+func TestCreateProjectDefaultAccount(t *testing.T) {
+	const user = "urn:ivcap:user:alice"
+	tests := []struct {
+		name           string
+		defaultAccount string
+		account        *string
+		want           string
+	}{
+		{name: "no default", want: ivcaptest.Account(user)},
+		{name: "default", defaultAccount: "urn:ivcap:account:default", want: "urn:ivcap:account:default"},
+		{name: "explicit", defaultAccount: "urn:ivcap:account:default", account: ptr("urn:ivcap:account:other"), want: "urn:ivcap:account:other"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(ivcaptest.New().Handler())
+			t.Cleanup(srv.Close)
+			c, err := ivcap.NewFromContext(&ivcap.Context{
+				URL:            srv.URL,
+				Token:          ivcap.TokenConfig{JWT: ivcaptest.Token(user)},
+				DefaultAccount: tt.defaultAccount,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := context.Background()
+			p, err := c.Projects.CreateProject(ctx, &project.ProjectCreateRequest{Name: "test", AccountUrn: tt.account})
+			if err != nil {
+				t.Fatal(err)
+			}
+			acc, err := c.Projects.ProjectAccount(ctx, p.Urn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if acc.AccountUrn != tt.want {
+				t.Errorf("account = %s, want %s", acc.AccountUrn, tt.want)
+			}
+		})
+	}
+}
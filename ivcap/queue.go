@@ -0,0 +1,137 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap
+
+import (
+	"context"
+	"net/http"
+
+	queue "github.com/ivcap-works/ivcap-core-api/gen/queue"
+	queuec "github.com/ivcap-works/ivcap-core-api/http/queue"
+	goahttp "goa.design/goa/v3/http"
+	goa "goa.design/goa/v3/pkg"
+)
+
+// QueuesClient gives typed access to the queue service.
+type QueuesClient struct {
+	c       *Client
+	create  goa.Endpoint
+	read    goa.Endpoint
+	delete  goa.Endpoint
+	list    goa.Endpoint
+	enqueue goa.Endpoint
+	dequeue goa.Endpoint
+}
+
+func newQueuesClient(c *Client, enc func(*http.Request) goahttp.Encoder, dec func(*http.Response) goahttp.Decoder, restoreBody bool) *QueuesClient {
+	hc := queuec.NewClient(c.scheme, c.host, c.doer, enc, dec, restoreBody)
+	return &QueuesClient{
+		c:       c,
+		create:  c.endpoint(queue.ServiceName, "create", hc.Create()),
+		read:    c.endpoint(queue.ServiceName, "read", hc.Read()),
+		delete:  c.endpoint(queue.ServiceName, "delete", hc.Delete()),
+		list:    c.endpoint(queue.ServiceName, "list", hc.List()),
+		enqueue: c.endpoint(queue.ServiceName, "enqueue", hc.Enqueue()),
+		dequeue: c.endpoint(queue.ServiceName, "dequeue", hc.Dequeue()),
+	}
+}
+
+// Create creates a new queue and returns its status.
+func (s *QueuesClient) Create(ctx context.Context, req *queue.PayloadForCreateEndpoint) (*queue.Createqueueresponse, error) {
+	p := &queue.CreatePayload{Queues: req}
+	return invoke[*queue.Createqueueresponse](ctx, s.create, p)
+}
+
+// Read returns the status of the queue with the given ID.
+func (s *QueuesClient) Read(ctx context.Context, id string) (*queue.Readqueueresponse, error) {
+	p := &queue.ReadPayload{ID: id}
+	return invoke[*queue.Readqueueresponse](ctx, s.read, p)
+}
+
+// Delete deletes the queue with the given ID.
+func (s *QueuesClient) Delete(ctx context.Context, id string) error {
+	p := &queue.DeletePayload{ID: id}
+	_, err := s.delete(ctx, p)
+	return err
+}
+
+// List returns a page of queues.
+func (s *QueuesClient) List(ctx context.Context, p *queue.ListPayload) (*queue.QueueListResult, error) {
+	var q queue.ListPayload
+	if p != nil {
+		q = *p
+	}
+	return invoke[*queue.QueueListResult](ctx, s.list, &q)
+}
+
+// Enqueue sends a message to a queue.
+func (s *QueuesClient) Enqueue(ctx context.Context, p *queue.EnqueuePayload) (*queue.Messagestatus, error) {
+	var q queue.EnqueuePayload
+	if p != nil {
+		q = *p
+	}
+	return invoke[*queue.Messagestatus](ctx, s.enqueue, &q)
+}
+
+// DequeueResult is the result of QueuesClient.Dequeue.
+type DequeueResult struct {
+	*queue.MessageList
+	// ContentErrs holds, at the index of each of Messages, the *ContentError
+	// of content which does not decode into the type registered for its
+	// schema, and is left as read, or nil.
+	ContentErrs []error `json:"-"`
+}
+
+// Dequeue reads up to limit messages from the queue with the given ID. A limit
+// of zero leaves the choice to the server. As the messages returned are
+// removed from the queue, failed calls are only retried when the context
+// comes from WithMutatingRetry or the retry policy allows mutating retries.
+// Content with a schema registered with RegisterSchema is decoded into its
+// type; content which does not match is left as read and reported in
+// ContentErrs, so that no message received is lost to an error.
+func (s *QueuesClient) Dequeue(ctx context.Context, id string, limit int) (*DequeueResult, error) {
+	p := &queue.DequeuePayload{ID: id}
+	if limit > 0 {
+		p.Limit = &limit
+	}
+	list, err := invoke[*queue.MessageList](ctx, s.dequeue, p)
+	if err != nil {
+		return nil, err
+	}
+	res := &DequeueResult{MessageList: list, ContentErrs: make([]error, len(list.Messages))}
+	for i, m := range list.Messages {
+		res.ContentErrs[i] = decodeContent(stringValue(m.Schema), stringValue(m.ID), &m.Content)
+	}
+	return res, nil
+}
+
+// ListIter returns an iterator over all queues matching p, following the next page links.
+func (s *QueuesClient) ListIter(ctx context.Context, p *queue.ListPayload) *Iterator[*queue.QueueListItem] {
+	var q queue.ListPayload
+	if p != nil {
+		q = *p
+	}
+	q.Limit = pageLimit(q.Limit)
+	return newIterator(ctx, func(ctx context.Context, page string) ([]*queue.QueueListItem, string, error) {
+		if page != "" {
+			q.Page = &page
+		}
+		res, err := s.List(ctx, &q)
+		if err != nil {
+			return nil, "", err
+		}
+		return res.Items, nextPage(res.Links, func(l *queue.LinkT) (string, string) { return l.Rel, l.Href }), nil
+	})
+}
@@ -0,0 +1,185 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ivcap-works/ivcap-core-api/ivcaperr"
+	goa "goa.design/goa/v3/pkg"
+)
+
+// RetryPolicy controls how failed requests are retried. Requests are retried
+// when the service is not available, the server responds with 429, 502, 503
+// or 504, or the request could not be sent at all.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one.
+	MaxAttempts int
+	// InitialBackoff is the upper bound of the delay before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between two attempts, unless the server asks
+	// for a longer one with Retry-After.
+	MaxBackoff time.Duration
+	// Multiplier is the factor the backoff grows by after each attempt.
+	Multiplier float64
+	// Mutating enables retries of methods which are not idempotent, such as
+	// order create or secret set. Use WithMutatingRetry to opt in for a
+	// single call instead.
+	Mutating bool
+}
+
+// DefaultRetryPolicy returns the retry policy used by WithRetry when no
+// fields are set.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     30 * time.Second,
+		Multiplier:     2,
+	}
+}
+
+// WithRetry enables retries with exponential backoff and full jitter.
+// Idempotent methods are retried by default, mutating ones only when p.Mutating
+// is set or the call opted in with WithMutatingRetry. Methods streaming a
+// request body (artifact upload, package push) are never retried.
+func WithRetry(p RetryPolicy) Option {
+	d := DefaultRetryPolicy()
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = d.MaxAttempts
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = d.InitialBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = d.MaxBackoff
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = d.Multiplier
+	}
+	return func(o *options) {
+		o.retry = &p
+	}
+}
+
+type mutatingRetryKey struct{}
+
+// WithMutatingRetry returns a context which allows retrying the mutating
+// method called with it.
+func WithMutatingRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, mutatingRetryKey{}, true)
+}
+
+// idempotentMethods lists the methods retried by default, keyed by service.
+// Queue dequeue is not one of them: a retry after a response was lost would
+// remove a second batch of messages, losing the first.
+var idempotentMethods = map[string]map[string]bool{
+	"artifact":  {"list": true, "read": true, "tus-head": true, "download": true},
+	"aspect":    {"read": true, "list": true},
+	"dashboard": {"list": true},
+	"metadata":  {"read": true, "list": true},
+	"openapi":   {"fetch-spec": true},
+	"order":     {"list": true, "read": true, "products": true, "metadata": true, "logs": true, "top": true},
+	"package":   {"list": true, "pull": true, "status": true},
+	"project":   {"list": true, "read": true, "ListProjectMembers": true, "DefaultProject": true, "ProjectAccount": true},
+	"queue":     {"read": true, "list": true},
+	"search":    {"search": true},
+	"secret":    {"list": true, "get": true},
+	"service":   {"list": true, "read": true},
+}
+
+// streamingMethods lists the methods whose request body is a stream which
+// cannot be replayed.
+var streamingMethods = map[string]map[string]bool{
+	"artifact": {"upload": true},
+	"package":  {"push": true},
+}
+
+// middleware returns the endpoint middleware retrying method of service svc.
+func (p *RetryPolicy) middleware(svc, method string) func(goa.Endpoint) goa.Endpoint {
+	return func(ep goa.Endpoint) goa.Endpoint {
+		if streamingMethods[svc][method] {
+			return ep
+		}
+		idempotent := idempotentMethods[svc][method]
+		return func(ctx context.Context, req any) (any, error) {
+			retry := idempotent || p.Mutating || ctx.Value(mutatingRetryKey{}) != nil
+			for attempt := 1; ; attempt++ {
+				actx, ex := withExchange(ctx)
+				res, err := ep(actx, req)
+				if err == nil || !retry || attempt >= p.MaxAttempts || !ex.retryable(err) {
+					return res, err
+				}
+				delay := p.backoff(attempt)
+				if ex.retryAfter > delay {
+					delay = ex.retryAfter
+				}
+				t := time.NewTimer(delay)
+				select {
+				case <-ctx.Done():
+					t.Stop()
+					return res, err
+				case <-t.C:
+				}
+			}
+		}
+	}
+}
+
+// backoff returns a random delay of up to InitialBackoff*Multiplier^(n-1),
+// capped by MaxBackoff.
+func (p *RetryPolicy) backoff(n int) time.Duration {
+	d := float64(p.InitialBackoff) * math.Pow(p.Multiplier, float64(n-1))
+	if d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
+
+// retryable reports whether the attempt which failed with err is worth
+// repeating.
+func (ex *exchange) retryable(err error) bool {
+	if ex.transportErr {
+		return true
+	}
+	switch ex.status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return errors.Is(err, ivcaperr.ErrUnavailable)
+}
+
+// parseRetryAfter returns the delay requested by a Retry-After header given
+// either in seconds or as a HTTP date.
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if s, err := strconv.Atoi(v); err == nil && s > 0 {
+		return time.Duration(s) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
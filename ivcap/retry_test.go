@@ -0,0 +1,141 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	artifact "github.com/ivcap-works/ivcap-core-api/gen/artifact"
+	secret "github.com/ivcap-works/ivcap-core-api/gen/secret"
+	"github.com/ivcap-works/ivcap-core-api/ivcap"
+	"github.com/ivcap-works/ivcap-core-api/ivcaperr"
+	"github.com/ivcap-works/ivcap-core-api/ivcaptest"
+)
+
+// statusFaults serves h, answering the first requests with verb to paths
+// starting with prefix with the statuses listed, 0 dropping the connection,
+// and counting them.
+type statusFaults struct {
+	h        http.Handler
+	verb     string
+	prefix   string
+	statuses []int
+	requests int32
+}
+
+func (f *statusFaults) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != f.verb || !strings.HasPrefix(r.URL.Path, f.prefix) {
+		f.h.ServeHTTP(w, r)
+		return
+	}
+	n := int(atomic.AddInt32(&f.requests, 1))
+	if n > len(f.statuses) {
+		f.h.ServeHTTP(w, r)
+		return
+	}
+	if f.statuses[n-1] == 0 {
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+		return
+	}
+	http.Error(w, "fault", f.statuses[n-1])
+}
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		name     string
+		call     string
+		mutating bool
+		statuses []int
+		// requests is the number of requests sent, and want the kind of the
+		// error returned, if any.
+		requests int32
+		want     error
+	}{
+		{name: "unavailable", call: "read", statuses: []int{503, 503}, requests: 3},
+		{name: "too many requests", call: "read", statuses: []int{429}, requests: 2},
+		{name: "bad gateway", call: "read", statuses: []int{502}, requests: 2},
+		{name: "gateway timeout", call: "read", statuses: []int{504}, requests: 2},
+		{name: "connection dropped", call: "read", statuses: []int{0}, requests: 2},
+		{name: "attempts exhausted", call: "read", statuses: []int{503, 503, 503, 503}, requests: 3, want: ivcaperr.ErrUnavailable},
+		{name: "internal error", call: "read", statuses: []int{500}, requests: 1, want: ivcaperr.ErrInternal},
+		{name: "not found", call: "read", statuses: []int{404}, requests: 1, want: ivcaperr.ErrNotFound},
+		{name: "mutating", call: "set", statuses: []int{503}, requests: 1, want: ivcaperr.ErrUnavailable},
+		{name: "mutating opted in", call: "set", mutating: true, statuses: []int{503}, requests: 2},
+		{name: "streaming", call: "upload", mutating: true, statuses: []int{503}, requests: 1, want: ivcaperr.ErrUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := ivcaptest.New()
+			setup := newDeploymentClient(t, d, "urn:ivcap:user:alice")
+			ctx := context.Background()
+			up, err := setup.Artifacts.Upload(ctx, &artifact.UploadPayload{}, strings.NewReader("content"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			f := &statusFaults{h: d.Handler(), statuses: tt.statuses}
+			var call func(ctx context.Context, c *ivcap.Client) error
+			switch tt.call {
+			case "read":
+				f.verb, f.prefix = "GET", "/1/artifacts/"
+				call = func(ctx context.Context, c *ivcap.Client) error {
+					_, err := c.Artifacts.Read(ctx, up.ID)
+					return err
+				}
+			case "set":
+				f.verb, f.prefix = "POST", "/1/secrets"
+				call = func(ctx context.Context, c *ivcap.Client) error {
+					return c.Secrets.Set(ctx, &secret.SetSecretRequestT{SecretName: "name", SecretValue: "value"})
+				}
+			case "upload":
+				f.verb, f.prefix = "POST", "/1/artifacts"
+				call = func(ctx context.Context, c *ivcap.Client) error {
+					_, err := c.Artifacts.Upload(ctx, &artifact.UploadPayload{}, strings.NewReader("content"))
+					return err
+				}
+			}
+			srv := httptest.NewServer(f)
+			t.Cleanup(srv.Close)
+			c, err := ivcap.New(srv.URL,
+				ivcap.WithJWT(ivcaptest.Token("urn:ivcap:user:alice")),
+				ivcap.WithRetry(ivcap.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.mutating {
+				ctx = ivcap.WithMutatingRetry(ctx)
+			}
+			err = call(ctx, c)
+			switch {
+			case tt.want == nil && err != nil:
+				t.Fatal(err)
+			case tt.want != nil && !errors.Is(err, tt.want):
+				t.Errorf("error %v, want %v", err, tt.want)
+			}
+			if f.requests != tt.requests {
+				t.Errorf("sent %d requests, want %d", f.requests, tt.requests)
+			}
+		})
+	}
+}
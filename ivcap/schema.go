@@ -0,0 +1,132 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// schemaType decodes and checks content with a registered schema.
+type schemaType struct {
+	typ    reflect.Type
+	decode func(v any) (any, error)
+}
+
+var (
+	schemaTypesMu sync.RWMutex
+	schemaTypes   = map[string]*schemaType{}
+)
+
+// RegisterSchema makes the clients decode the content of aspects, metadata
+// records and queue messages with schema, such as
+// "urn:example:schema:dataset.1", into a T. AspectsClient.Read, List and
+// ListIter, the same methods of MetadataClient, and QueuesClient.Dequeue
+// return such content as a T value, and AspectsClient.Create and Update and
+// MetadataClient.Add and UpdateRecord check that the content given decodes
+// into a T before sending it. Read fails with a *ContentError for content
+// which does not decode into a T. The other methods leave such content as it
+// is, and report it in DequeueResult.ContentErrs for Dequeue and with
+// Iterator.ItemErr for the items of ListIter; List leaves it unreported.
+// Fields of the content without a counterpart in T are ignored.
+//
+// The registry is global: a schema registered applies to every Client of
+// the process, including those created before, and registering it again
+// replaces its type. It is safe to call RegisterSchema concurrently with
+// the clients using it, typically from an init function.
+func RegisterSchema[T any](schema string) {
+	schemaTypesMu.Lock()
+	defer schemaTypesMu.Unlock()
+	schemaTypes[schema] = &schemaType{
+		typ: reflect.TypeOf((*T)(nil)).Elem(),
+		decode: func(v any) (any, error) {
+			var res T
+			b, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			if err := json.Unmarshal(b, &res); err != nil {
+				return nil, err
+			}
+			return res, nil
+		},
+	}
+}
+
+// ContentError is returned when the content of an aspect or queue message
+// does not decode into the type registered for its schema.
+type ContentError struct {
+	// Schema of the content.
+	Schema string
+	// ID of the aspect or message, empty for content not sent yet.
+	ID string
+	// Type registered for Schema.
+	Type reflect.Type
+	Err  error
+}
+
+// Error returns the error message.
+func (e *ContentError) Error() string {
+	if e.ID == "" {
+		return fmt.Sprintf("ivcap: content does not match %s of schema %s: %s", e.Type, e.Schema, e.Err)
+	}
+	return fmt.Sprintf("ivcap: content of %s does not match %s of schema %s: %s", e.ID, e.Type, e.Schema, e.Err)
+}
+
+// Unwrap returns the decoding error.
+func (e *ContentError) Unwrap() error {
+	return e.Err
+}
+
+// lookupSchema returns the type registered for schema, or nil.
+func lookupSchema(schema string) *schemaType {
+	schemaTypesMu.RLock()
+	defer schemaTypesMu.RUnlock()
+	return schemaTypes[schema]
+}
+
+// decodeContent decodes *content into the type registered for schema, if
+// any, leaving it as it is if it does not match. id identifies the aspect or
+// message in errors.
+func decodeContent(schema, id string, content *any) error {
+	t := lookupSchema(schema)
+	if t == nil || *content == nil || reflect.TypeOf(*content) == t.typ {
+		return nil
+	}
+	v, err := t.decode(*content)
+	if err != nil {
+		return &ContentError{Schema: schema, ID: id, Type: t.typ, Err: err}
+	}
+	*content = v
+	return nil
+}
+
+// checkContent returns a *ContentError if content does not decode into the
+// type registered for schema.
+func checkContent(schema string, content any) error {
+	t := lookupSchema(schema)
+	if t == nil {
+		return nil
+	}
+	if ct := reflect.TypeOf(content); ct == t.typ || ct == reflect.PtrTo(t.typ) {
+		return nil
+	}
+	if _, err := t.decode(content); err != nil {
+		return &ContentError{Schema: schema, Type: t.typ, Err: err}
+	}
+	return nil
+}
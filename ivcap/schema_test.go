@@ -0,0 +1,196 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	aspect "github.com/ivcap-works/ivcap-core-api/gen/aspect"
+	metadata "github.com/ivcap-works/ivcap-core-api/gen/metadata"
+	queue "github.com/ivcap-works/ivcap-core-api/gen/queue"
+	"github.com/ivcap-works/ivcap-core-api/ivcap"
+)
+
+type testDataset struct {
+	Name string `json:"name"`
+	Size int    `json:"size"`
+}
+
+const datasetSchema = "urn:ivcap:schema:test-dataset.1"
+
+func init() {
+	ivcap.RegisterSchema[testDataset](datasetSchema)
+}
+
+func TestContentDecoding(t *testing.T) {
+	tests := []struct {
+		name    string
+		content any
+		want    any
+		wantErr bool
+	}{
+		{name: "match", content: map[string]any{"name": "a", "size": 1}, want: testDataset{Name: "a", Size: 1}},
+		{name: "extra field", content: map[string]any{"name": "b", "other": true}, want: testDataset{Name: "b"}},
+		{name: "mismatch", content: map[string]any{"name": 3}, want: map[string]any{"name": 3.0}, wantErr: true},
+	}
+	c, d := newTestClient(t, "urn:ivcap:user:alice")
+	ctx := context.Background()
+	// The fakes store the content as given, so that content not matching
+	// the registered type can be set up.
+	for _, tt := range tests {
+		_, err := d.Aspects.Create(ctx, &aspect.CreatePayload{Entity: "urn:ivcap:entity:" + tt.name, Schema: datasetSchema, Content: tt.content, ContentType: "application/json"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = d.Metadata.Add(ctx, &metadata.AddPayload{EntityID: "urn:ivcap:entity:" + tt.name, Schema: datasetSchema, Aspect: tt.content, ContentType: "application/json"})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	schema := datasetSchema
+	aspects := map[string]*aspect.AspectListItemRT{}
+	aspectErrs := map[string]error{}
+	it := c.Aspects.ListIter(ctx, &aspect.ListPayload{Schema: &schema, IncludeContent: ptr(true)})
+	for it.Next() {
+		aspects[it.Item().Entity], aspectErrs[it.Item().Entity] = it.Item(), it.ItemErr()
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	records := map[string]*metadata.MetadataListItemRT{}
+	recordErrs := map[string]error{}
+	mit := c.Metadata.ListIter(ctx, &metadata.ListPayload{Schema: &schema, IncludeContent: true})
+	for mit.Next() {
+		records[mit.Item().Entity], recordErrs[mit.Item().Entity] = mit.Item(), mit.ItemErr()
+	}
+	if err := mit.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entity := "urn:ivcap:entity:" + tt.name
+			a := aspects[entity]
+			if a == nil {
+				t.Fatal("aspect not listed")
+			}
+			checkDecoded(t, "ListIter", a.Content, aspectErrs[entity], tt.want, tt.wantErr)
+			r, err := c.Aspects.Read(ctx, a.ID)
+			checkRead(t, "Read", r != nil, func() any { return r.Content }, err, tt.want, tt.wantErr)
+
+			m := records[entity]
+			if m == nil {
+				t.Fatal("metadata record not listed")
+			}
+			checkDecoded(t, "Metadata.ListIter", m.Aspect, recordErrs[entity], tt.want, tt.wantErr)
+			mr, err := c.Metadata.Read(ctx, m.ID)
+			checkRead(t, "Metadata.Read", mr != nil, func() any { return mr.Aspect }, err, tt.want, tt.wantErr)
+		})
+	}
+}
+
+func checkDecoded(t *testing.T, method string, got any, err error, want any, wantErr bool) {
+	t.Helper()
+	var ce *ivcap.ContentError
+	if errors.As(err, &ce) != wantErr {
+		t.Errorf("%s: error %v, want a *ContentError: %v", method, err, wantErr)
+	} else if err != nil && !wantErr {
+		t.Errorf("%s: %v", method, err)
+	}
+	if !equalJSON(got, want) {
+		t.Errorf("%s: content %#v, want %#v", method, got, want)
+	}
+}
+
+// checkRead checks the result of a Read, which fails instead of returning
+// content which does not decode.
+func checkRead(t *testing.T, method string, found bool, content func() any, err error, want any, wantErr bool) {
+	t.Helper()
+	if wantErr {
+		var ce *ivcap.ContentError
+		if !errors.As(err, &ce) || found {
+			t.Errorf("%s: got %v, want only a *ContentError", method, err)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("%s: %v", method, err)
+	}
+	checkDecoded(t, method, content(), nil, want, false)
+}
+
+func equalJSON(a, b any) bool {
+	if _, ok := b.(testDataset); ok {
+		return a == b
+	}
+	am, aok := a.(map[string]any)
+	bm, bok := b.(map[string]any)
+	if !aok || !bok || len(am) != len(bm) {
+		return false
+	}
+	for k, v := range bm {
+		if am[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func TestDequeueContent(t *testing.T) {
+	c, _ := newTestClient(t, "urn:ivcap:user:alice")
+	ctx := context.Background()
+	q, err := c.Queues.Create(ctx, &queue.PayloadForCreateEndpoint{Name: "content"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	contents := []any{
+		map[string]any{"name": "a", "size": 1},
+		map[string]any{"name": 3},
+		map[string]any{"name": "c"},
+	}
+	for _, content := range contents {
+		_, err := c.Queues.Enqueue(ctx, &queue.EnqueuePayload{ID: q.ID, Schema: ptr(datasetSchema), Content: content})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	res, err := c.Queues.Dequeue(ctx, q.ID, len(contents))
+	if err != nil {
+		t.Fatalf("Dequeue: %v, want the messages with their content errors", err)
+	}
+	if len(res.Messages) != len(contents) || len(res.ContentErrs) != len(contents) {
+		t.Fatalf("got %d messages and %d content errors, want %d", len(res.Messages), len(res.ContentErrs), len(contents))
+	}
+	want := []any{testDataset{Name: "a", Size: 1}, map[string]any{"name": 3.0}, testDataset{Name: "c"}}
+	for i, m := range res.Messages {
+		checkDecoded(t, "Dequeue", m.Content, res.ContentErrs[i], want[i], i == 1)
+	}
+}
+
+func TestContentCheck(t *testing.T) {
+	c, _ := newTestClient(t, "urn:ivcap:user:alice")
+	_, err := c.Aspects.Create(context.Background(), &aspect.CreatePayload{
+		Entity:      testEntity,
+		Schema:      datasetSchema,
+		Content:     map[string]any{"size": "large"},
+		ContentType: "application/json",
+	})
+	var ce *ivcap.ContentError
+	if !errors.As(err, &ce) || ce.Schema != datasetSchema {
+		t.Errorf("Create: got %v, want *ContentError", err)
+	}
+}
@@ -0,0 +1,308 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	aspect "github.com/ivcap-works/ivcap-core-api/gen/aspect"
+	"github.com/ivcap-works/ivcap-core-api/ivcaperr"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// ErrSchemaNotFound is returned by a SchemaResolver which does not know a
+// schema. Content with such a schema is not validated.
+var ErrSchemaNotFound = errors.New("ivcap: schema not found")
+
+// SchemaResolver returns the JSON Schema documents of schema URNs.
+// Implementations must be safe for concurrent use.
+type SchemaResolver interface {
+	// Resolve returns the document of schema, or ErrSchemaNotFound.
+	Resolve(ctx context.Context, schema string) ([]byte, error)
+}
+
+// SchemaResolverFunc adapts a function, for example fetching documents from
+// a registry, to a SchemaResolver.
+type SchemaResolverFunc func(ctx context.Context, schema string) ([]byte, error)
+
+// Resolve returns f(ctx, schema).
+func (f SchemaResolverFunc) Resolve(ctx context.Context, schema string) ([]byte, error) {
+	return f(ctx, schema)
+}
+
+// FSSchemaResolver returns a resolver serving the documents found in the
+// ".json" files of fsys, such as an embed.FS bundled with a program, by their
+// "$id".
+func FSSchemaResolver(fsys fs.FS) (SchemaResolver, error) {
+	docs := fsSchemas{}
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || path.Ext(p) != ".json" {
+			return err
+		}
+		b, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		var doc struct {
+			ID string `json:"$id"`
+		}
+		if err := json.Unmarshal(b, &doc); err != nil {
+			return fmt.Errorf("ivcap: reading schema %s: %w", p, err)
+		}
+		if doc.ID != "" {
+			docs[doc.ID] = b
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// DirSchemaResolver returns a resolver serving the documents found in the
+// ".json" files below dir by their "$id".
+func DirSchemaResolver(dir string) (SchemaResolver, error) {
+	return FSSchemaResolver(os.DirFS(dir))
+}
+
+type fsSchemas map[string][]byte
+
+func (s fsSchemas) Resolve(_ context.Context, schema string) ([]byte, error) {
+	if b, ok := s[schema]; ok {
+		return b, nil
+	}
+	return nil, ErrSchemaNotFound
+}
+
+// MultiSchemaResolver returns a resolver asking each of resolvers in turn
+// until one knows the schema.
+func MultiSchemaResolver(resolvers ...SchemaResolver) SchemaResolver {
+	return SchemaResolverFunc(func(ctx context.Context, schema string) ([]byte, error) {
+		for _, r := range resolvers {
+			b, err := r.Resolve(ctx, schema)
+			if !errors.Is(err, ErrSchemaNotFound) {
+				return b, err
+			}
+		}
+		return nil, ErrSchemaNotFound
+	})
+}
+
+// WithSchemaValidation makes AspectsClient.Create and Update validate the
+// content of aspects against the JSON Schema document r resolves for their
+// schema before sending them.
+func WithSchemaValidation(r SchemaResolver) Option {
+	return func(o *options) {
+		o.schemas = r
+	}
+}
+
+// SchemaValidationError is returned when content does not validate against
+// the JSON Schema document of its schema. It matches
+// ivcaperr.ErrInvalidParameter and unwraps to the first of Errors.
+type SchemaValidationError struct {
+	// Schema of the content.
+	Schema string
+	// Errors lists the failed constraints. Their Name is the JSON path of
+	// the content at fault, such as "$.items[0].name", and Value its JSON
+	// encoding.
+	Errors []*aspect.InvalidParameterT
+}
+
+// Error returns the error message.
+func (e *SchemaValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, v := range e.Errors {
+		msgs[i] = v.Name + ": " + v.Message
+	}
+	return fmt.Sprintf("ivcap: content does not validate against %s: %s", e.Schema, strings.Join(msgs, "; "))
+}
+
+// Is reports whether target is ivcaperr.ErrInvalidParameter.
+func (e *SchemaValidationError) Is(target error) bool {
+	return target == ivcaperr.ErrInvalidParameter
+}
+
+// Unwrap returns the first failed constraint.
+func (e *SchemaValidationError) Unwrap() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e.Errors[0]
+}
+
+// schemaNotFoundTTL is how long a schema the resolver does not know is
+// assumed to stay unknown, before asking the resolver again.
+const schemaNotFoundTTL = time.Minute
+
+// schemaValidator validates content against the documents of a resolver,
+// compiling each schema once.
+type schemaValidator struct {
+	resolver SchemaResolver
+
+	mu       sync.Mutex
+	compiled map[string]*jsonschema.Schema
+	// missing records when the schemas the resolver did not know were
+	// looked up.
+	missing map[string]time.Time
+}
+
+func newSchemaValidator(r SchemaResolver) *schemaValidator {
+	return &schemaValidator{resolver: r, compiled: map[string]*jsonschema.Schema{}, missing: map[string]time.Time{}}
+}
+
+// Validate checks content against the JSON Schema document of schema,
+// returning a *SchemaValidationError listing the constraints it fails.
+// Content with a schema the resolver does not know is valid. Without
+// WithSchemaValidation all content is valid.
+func (s *AspectsClient) Validate(ctx context.Context, schema string, content any) error {
+	if s.c.schemas == nil {
+		return nil
+	}
+	return s.c.schemas.validate(ctx, schema, content)
+}
+
+// validate checks content against the document of schema.
+func (v *schemaValidator) validate(ctx context.Context, schema string, content any) error {
+	sch, err := v.compile(ctx, schema)
+	if err != nil || sch == nil {
+		return err
+	}
+	b, err := json.Marshal(content)
+	if err != nil {
+		return err
+	}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	var inst any
+	if err := d.Decode(&inst); err != nil {
+		return err
+	}
+	err = sch.Validate(inst)
+	var ve *jsonschema.ValidationError
+	if !errors.As(err, &ve) {
+		return err
+	}
+	res := &SchemaValidationError{Schema: schema}
+	var walk func(ve *jsonschema.ValidationError)
+	walk = func(ve *jsonschema.ValidationError) {
+		if len(ve.Causes) > 0 {
+			for _, c := range ve.Causes {
+				walk(c)
+			}
+			return
+		}
+		p, val := instancePath(inst, ve.InstanceLocation)
+		var value *string
+		if b, err := json.Marshal(val); err == nil {
+			v := string(b)
+			value = &v
+		}
+		res.Errors = append(res.Errors, &aspect.InvalidParameterT{Name: p, Message: ve.Message, Value: value})
+	}
+	walk(ve)
+	return res
+}
+
+// compile returns the compiled document of schema, or nil if the resolver
+// does not know it. The resolver is not asked again for schemas it did not
+// know for schemaNotFoundTTL. It is called without holding v.mu, so that a
+// slow resolver does not hold up the validation of other schemas.
+func (v *schemaValidator) compile(ctx context.Context, schema string) (*jsonschema.Schema, error) {
+	v.mu.Lock()
+	sch, ok := v.compiled[schema]
+	at, missing := v.missing[schema]
+	v.mu.Unlock()
+	if ok {
+		return sch, nil
+	}
+	if missing && time.Since(at) < schemaNotFoundTTL {
+		return nil, nil
+	}
+	doc, err := v.resolver.Resolve(ctx, schema)
+	if errors.Is(err, ErrSchemaNotFound) {
+		v.mu.Lock()
+		v.missing[schema] = time.Now()
+		v.mu.Unlock()
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	c := jsonschema.NewCompiler()
+	c.Draft = jsonschema.Draft2020
+	c.LoadURL = func(u string) (io.ReadCloser, error) {
+		b, err := v.resolver.Resolve(ctx, u)
+		if err != nil {
+			return nil, fmt.Errorf("resolving %s: %w", u, err)
+		}
+		return io.NopCloser(bytes.NewReader(b)), nil
+	}
+	if err := c.AddResource(schema, bytes.NewReader(doc)); err != nil {
+		return nil, fmt.Errorf("ivcap: reading schema %s: %w", schema, err)
+	}
+	sch, err = c.Compile(schema)
+	if err != nil {
+		return nil, fmt.Errorf("ivcap: compiling schema %s: %w", schema, err)
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	// Another caller may have compiled the schema meanwhile.
+	if prev, ok := v.compiled[schema]; ok {
+		return prev, nil
+	}
+	v.compiled[schema] = sch
+	delete(v.missing, schema)
+	return sch, nil
+}
+
+// instancePath converts the JSON pointer ptr into inst to a JSON path, and
+// returns the value it points to.
+func instancePath(inst any, ptr string) (string, any) {
+	p := "$"
+	if ptr == "" {
+		return p, inst
+	}
+	for _, tok := range strings.Split(strings.TrimPrefix(ptr, "/"), "/") {
+		tok = strings.NewReplacer("~1", "/", "~0", "~").Replace(tok)
+		switch v := inst.(type) {
+		case []any:
+			i, _ := strconv.Atoi(tok)
+			p += "[" + tok + "]"
+			if i >= 0 && i < len(v) {
+				inst = v[i]
+			}
+		case map[string]any:
+			p += "." + tok
+			inst = v[tok]
+		default:
+			p += "." + tok
+		}
+	}
+	return p, inst
+}
@@ -0,0 +1,109 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ivcap-works/ivcap-core-api/ivcap"
+	"github.com/ivcap-works/ivcap-core-api/ivcaperr"
+)
+
+const nameSchema = "urn:ivcap:schema:name.1"
+
+var nameDoc = []byte(`{
+	"$id": "urn:ivcap:schema:name.1",
+	"type": "object",
+	"properties": {"name": {"type": "string"}},
+	"required": ["name"]
+}`)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		content any
+		valid   bool
+	}{
+		{name: "valid", schema: nameSchema, content: map[string]any{"name": "x"}, valid: true},
+		{name: "missing property", schema: nameSchema, content: map[string]any{}},
+		{name: "wrong type", schema: nameSchema, content: map[string]any{"name": 1}},
+		{name: "unknown schema", schema: "urn:ivcap:schema:unknown.1", content: map[string]any{}, valid: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			r := ivcap.SchemaResolverFunc(func(ctx context.Context, schema string) ([]byte, error) {
+				atomic.AddInt32(&calls, 1)
+				if schema == nameSchema {
+					return nameDoc, nil
+				}
+				return nil, ivcap.ErrSchemaNotFound
+			})
+			c, _ := newTestClient(t, "urn:ivcap:user:alice", ivcap.WithSchemaValidation(r))
+			for i := 0; i < 2; i++ {
+				err := c.Aspects.Validate(context.Background(), tt.schema, tt.content)
+				var ve *ivcap.SchemaValidationError
+				if tt.valid && err != nil {
+					t.Fatal(err)
+				}
+				if !tt.valid && (!errors.As(err, &ve) || !errors.Is(err, ivcaperr.ErrInvalidParameter)) {
+					t.Fatalf("error %v, want a *SchemaValidationError", err)
+				}
+			}
+			if calls != 1 {
+				t.Errorf("resolved the schema %d times, want once", calls)
+			}
+		})
+	}
+}
+
+func TestValidateSlowResolver(t *testing.T) {
+	const slowSchema = "urn:ivcap:schema:slow.1"
+	resolving, release := make(chan struct{}), make(chan struct{})
+	r := ivcap.SchemaResolverFunc(func(ctx context.Context, schema string) ([]byte, error) {
+		if schema == slowSchema {
+			close(resolving)
+			<-release
+			return nil, ivcap.ErrSchemaNotFound
+		}
+		return nameDoc, nil
+	})
+	c, _ := newTestClient(t, "urn:ivcap:user:alice", ivcap.WithSchemaValidation(r))
+	ctx := context.Background()
+	done := make(chan error)
+	go func() { done <- c.Aspects.Validate(ctx, slowSchema, map[string]any{}) }()
+	<-resolving
+
+	// The schema resolved meanwhile is not held up by the slow one.
+	validated := make(chan error)
+	go func() { validated <- c.Aspects.Validate(ctx, nameSchema, map[string]any{"name": "x"}) }()
+	select {
+	case err := <-validated:
+		if err != nil {
+			t.Error(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("validation waited for the resolution of another schema")
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap
+
+import (
+	"context"
+	"net/http"
+
+	search "github.com/ivcap-works/ivcap-core-api/gen/search"
+	searchc "github.com/ivcap-works/ivcap-core-api/http/search"
+	goahttp "goa.design/goa/v3/http"
+	goa "goa.design/goa/v3/pkg"
+)
+
+// SearchClient gives typed access to the search service.
+type SearchClient struct {
+	c      *Client
+	search goa.Endpoint
+}
+
+func newSearchClient(c *Client, enc func(*http.Request) goahttp.Encoder, dec func(*http.Response) goahttp.Decoder, restoreBody bool) *SearchClient {
+	hc := searchc.NewClient(c.scheme, c.host, c.doer, enc, dec, restoreBody)
+	return &SearchClient{
+		c:      c,
+		search: c.endpoint(search.ServiceName, "search", hc.Search()),
+	}
+}
+
+// Search executes the query in p and returns a page of results.
+func (s *SearchClient) Search(ctx context.Context, p *search.SearchPayload) (*search.SearchListRT, error) {
+	var q search.SearchPayload
+	if p != nil {
+		q = *p
+	}
+	return invoke[*search.SearchListRT](ctx, s.search, &q)
+}
+
+// SearchIter returns an iterator over all results of the query in p,
+// following the next page links.
+func (s *SearchClient) SearchIter(ctx context.Context, p *search.SearchPayload) *Iterator[any] {
+	var q search.SearchPayload
+	if p != nil {
+		q = *p
+	}
+	q.Limit = pageLimit(q.Limit)
+	return newIterator(ctx, func(ctx context.Context, page string) ([]any, string, error) {
+		if page != "" {
+			q.Page = page
+		}
+		res, err := s.Search(ctx, &q)
+		if err != nil {
+			return nil, "", err
+		}
+		return res.Items, nextPage(res.Links, func(l *search.LinkT) (string, string) { return l.Rel, l.Href }), nil
+	})
+}
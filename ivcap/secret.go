@@ -0,0 +1,94 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap
+
+import (
+	"context"
+	"net/http"
+
+	secret "github.com/ivcap-works/ivcap-core-api/gen/secret"
+	secretc "github.com/ivcap-works/ivcap-core-api/http/secret"
+	goahttp "goa.design/goa/v3/http"
+	goa "goa.design/goa/v3/pkg"
+)
+
+// SecretsClient gives typed access to the secret service.
+type SecretsClient struct {
+	c    *Client
+	list goa.Endpoint
+	get  goa.Endpoint
+	set  goa.Endpoint
+}
+
+func newSecretsClient(c *Client, enc func(*http.Request) goahttp.Encoder, dec func(*http.Response) goahttp.Decoder, restoreBody bool) *SecretsClient {
+	hc := secretc.NewClient(c.scheme, c.host, c.doer, enc, dec, restoreBody)
+	return &SecretsClient{
+		c:    c,
+		list: c.endpoint(secret.ServiceName, "list", hc.List()),
+		get:  c.endpoint(secret.ServiceName, "get", hc.Get()),
+		set:  c.endpoint(secret.ServiceName, "set", hc.Set()),
+	}
+}
+
+// List returns a page of the secrets under the caller's account.
+func (s *SecretsClient) List(ctx context.Context, p *secret.ListPayload) (*secret.ListResult, error) {
+	var q secret.ListPayload
+	if p != nil {
+		q = *p
+	}
+	return invoke[*secret.ListResult](ctx, s.list, &q)
+}
+
+// Get returns the secret with the given name.
+func (s *SecretsClient) Get(ctx context.Context, p *secret.GetPayload) (*secret.SecretResultT, error) {
+	var q secret.GetPayload
+	if p != nil {
+		q = *p
+	}
+	return invoke[*secret.SecretResultT](ctx, s.get, &q)
+}
+
+// Set creates or replaces a secret.
+func (s *SecretsClient) Set(ctx context.Context, req *secret.SetSecretRequestT) error {
+	p := &secret.SetPayload{Secrets: req}
+	_, err := s.set(ctx, p)
+	return err
+}
+
+// ListIter returns an iterator over all secrets matching p, following the
+// offset tokens of the next page links.
+func (s *SecretsClient) ListIter(ctx context.Context, p *secret.ListPayload) *Iterator[*secret.SecretListItem] {
+	var q secret.ListPayload
+	if p != nil {
+		q = *p
+	}
+	var limit int
+	if q.Limit != nil {
+		limit = *q.Limit
+	}
+	limit = pageLimit(limit)
+	q.Limit = &limit
+	return newIterator(ctx, func(ctx context.Context, offset string) ([]*secret.SecretListItem, string, error) {
+		if offset != "" {
+			q.Offset = &offset
+		}
+		res, err := s.List(ctx, &q)
+		if err != nil {
+			return nil, "", err
+		}
+		next := nextLink(res.Links, func(l *secret.LinkT) (string, string) { return l.Rel, l.Href })
+		return res.Items, linkParam(next, "offset"), nil
+	})
+}
@@ -0,0 +1,88 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	secret "github.com/ivcap-works/ivcap-core-api/gen/secret"
+	"github.com/ivcap-works/ivcap-core-api/ivcap"
+	"github.com/ivcap-works/ivcap-core-api/ivcaptest"
+)
+
+func TestSecretsListIter(t *testing.T) {
+	const secrets = 60
+	tests := []struct {
+		name  string
+		limit *int
+		want  int
+	}{
+		{name: "default", want: ivcap.DefaultPageSize},
+		{name: "zero", limit: ptr(0), want: ivcap.DefaultPageSize},
+		{name: "negative", limit: ptr(-1), want: ivcap.DefaultPageSize},
+		{name: "within range", limit: ptr(7), want: 7},
+		{name: "too large", limit: ptr(1000), want: ivcap.MaxPageSize},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := ivcaptest.New()
+			var (
+				mu     sync.Mutex
+				limits []int
+			)
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/1/secrets/list" {
+					l, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+					mu.Lock()
+					limits = append(limits, l)
+					mu.Unlock()
+				}
+				d.Handler().ServeHTTP(w, r)
+			}))
+			t.Cleanup(srv.Close)
+			c, err := ivcap.New(srv.URL, ivcap.WithJWT(ivcaptest.Token("urn:ivcap:user:alice")))
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := context.Background()
+			for i := 0; i < secrets; i++ {
+				if err := c.Secrets.Set(ctx, &secret.SetSecretRequestT{SecretName: fmt.Sprintf("secret-%02d", i), SecretValue: "value"}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			all, err := c.Secrets.ListIter(ctx, &secret.ListPayload{Limit: tt.limit}).Collect()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(all) != secrets {
+				t.Errorf("listed %d secrets, want %d", len(all), secrets)
+			}
+			if len(limits) == 0 {
+				t.Fatal("no page requested")
+			}
+			for _, l := range limits {
+				if l != tt.want {
+					t.Fatalf("requested pages of %v, want %d", limits, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap
+
+import (
+	"context"
+	"net/http"
+
+	service "github.com/ivcap-works/ivcap-core-api/gen/service"
+	servicec "github.com/ivcap-works/ivcap-core-api/http/service"
+	goahttp "goa.design/goa/v3/http"
+	goa "goa.design/goa/v3/pkg"
+)
+
+// ServicesClient gives typed access to the service service.
+type ServicesClient struct {
+	c             *Client
+	list          goa.Endpoint
+	createService goa.Endpoint
+	read          goa.Endpoint
+	update        goa.Endpoint
+	delete        goa.Endpoint
+}
+
+func newServicesClient(c *Client, enc func(*http.Request) goahttp.Encoder, dec func(*http.Response) goahttp.Decoder, restoreBody bool) *ServicesClient {
+	hc := servicec.NewClient(c.scheme, c.host, c.doer, enc, dec, restoreBody)
+	return &ServicesClient{
+		c:             c,
+		list:          c.endpoint(service.ServiceName, "list", hc.List()),
+		createService: c.endpoint(service.ServiceName, "create_service", hc.CreateService()),
+		read:          c.endpoint(service.ServiceName, "read", hc.Read()),
+		update:        c.endpoint(service.ServiceName, "update", hc.Update()),
+		delete:        c.endpoint(service.ServiceName, "delete", hc.Delete()),
+	}
+}
+
+// List returns a page of services.
+func (s *ServicesClient) List(ctx context.Context, p *service.ListPayload) (*service.ServiceListRT, error) {
+	var q service.ListPayload
+	if p != nil {
+		q = *p
+	}
+	return invoke[*service.ServiceListRT](ctx, s.list, &q)
+}
+
+// CreateService registers a new service and returns its status.
+func (s *ServicesClient) CreateService(ctx context.Context, def *service.ServiceDefinitionT) (*service.ServiceStatusRT, error) {
+	p := &service.CreateServicePayload{Services: def}
+	return invoke[*service.ServiceStatusRT](ctx, s.createService, p)
+}
+
+// Read returns the status of the service with the given ID.
+func (s *ServicesClient) Read(ctx context.Context, id string) (*service.ServiceStatusRT, error) {
+	p := &service.ReadPayload{ID: id}
+	return invoke[*service.ServiceStatusRT](ctx, s.read, p)
+}
+
+// Update updates an existing service and returns its status.
+func (s *ServicesClient) Update(ctx context.Context, p *service.UpdatePayload) (*service.ServiceStatusRT, error) {
+	var q service.UpdatePayload
+	if p != nil {
+		q = *p
+	}
+	return invoke[*service.ServiceStatusRT](ctx, s.update, &q)
+}
+
+// Delete deletes the service with the given ID.
+func (s *ServicesClient) Delete(ctx context.Context, id string) error {
+	p := &service.DeletePayload{ID: id}
+	_, err := s.delete(ctx, p)
+	return err
+}
+
+// ListIter returns an iterator over all services matching p, following the next page links.
+func (s *ServicesClient) ListIter(ctx context.Context, p *service.ListPayload) *Iterator[*service.ServiceListItem] {
+	var q service.ListPayload
+	if p != nil {
+		q = *p
+	}
+	q.Limit = pageLimit(q.Limit)
+	return newIterator(ctx, func(ctx context.Context, page string) ([]*service.ServiceListItem, string, error) {
+		if page != "" {
+			q.Page = &page
+		}
+		res, err := s.List(ctx, &q)
+		if err != nil {
+			return nil, "", err
+		}
+		return res.Items, nextPage(res.Links, func(l *service.LinkT) (string, string) { return l.Rel, l.Href }), nil
+	})
+}
@@ -0,0 +1,234 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	goahttp "goa.design/goa/v3/http"
+)
+
+// expiryDelta is how long before its expiry a token is considered expired,
+// leaving room for clock skew and request latency.
+const expiryDelta = 30 * time.Second
+
+// ErrTokenExpired is returned by token sources which hold an expired token and
+// have no way to refresh it.
+var ErrTokenExpired = errors.New("ivcap: token expired")
+
+// Token is an access token issued for an IVCAP deployment.
+type Token struct {
+	// AccessToken is the JWT sent in the Authorization header.
+	AccessToken string `json:"access_token"`
+	// RefreshToken is used to obtain a new access token, if supported by the
+	// token source.
+	RefreshToken string `json:"refresh_token,omitempty"`
+	// Expiry is the time at which AccessToken expires. The zero value means
+	// the token does not expire.
+	Expiry time.Time `json:"expiry,omitempty"`
+}
+
+// Valid reports whether t holds an access token which is not about to expire.
+func (t *Token) Valid() bool {
+	if t == nil || t.AccessToken == "" {
+		return false
+	}
+	return t.Expiry.IsZero() || time.Now().Add(expiryDelta).Before(t.Expiry)
+}
+
+// TokenSource provides the token attached to each request. Implementations
+// must be safe for concurrent use.
+type TokenSource interface {
+	// Token returns a token or an error.
+	Token(ctx context.Context) (*Token, error)
+}
+
+// NewToken returns a token for jwt with its expiry taken from the "exp" claim,
+// if present.
+func NewToken(jwt string) *Token {
+	return &Token{AccessToken: jwt, Expiry: jwtExpiry(jwt)}
+}
+
+// jwtExpiry returns the time held in the "exp" claim of jwt, or the zero time
+// if jwt cannot be parsed or has no such claim. The signature is not verified.
+func jwtExpiry(jwt string) time.Time {
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(b, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+	return time.Unix(claims.Exp, 0)
+}
+
+// StaticTokenSource returns a token source which always returns jwt. It fails
+// with ErrTokenExpired once jwt has expired.
+func StaticTokenSource(jwt string) TokenSource {
+	return &staticTokenSource{token: NewToken(jwt)}
+}
+
+type staticTokenSource struct {
+	token *Token
+}
+
+func (s *staticTokenSource) Token(context.Context) (*Token, error) {
+	if !s.token.Valid() {
+		return nil, ErrTokenExpired
+	}
+	return s.token, nil
+}
+
+// EnvTokenSource returns a token source reading the JWT from the environment
+// variable name. The variable is read again whenever the previous token has
+// expired.
+func EnvTokenSource(name string) TokenSource {
+	return ReuseTokenSource(nil, envTokenSource(name))
+}
+
+type envTokenSource string
+
+func (s envTokenSource) Token(context.Context) (*Token, error) {
+	jwt := strings.TrimSpace(os.Getenv(string(s)))
+	if jwt == "" {
+		return nil, fmt.Errorf("ivcap: environment variable %s is not set", string(s))
+	}
+	t := NewToken(jwt)
+	if !t.Valid() {
+		return nil, fmt.Errorf("ivcap: token in environment variable %s: %w", string(s), ErrTokenExpired)
+	}
+	return t, nil
+}
+
+// FileTokenSource returns a token source reading the token from the file at
+// path. The file holds either a bare JWT or a JSON encoded Token, as written
+// by the device code token source. The file is read again whenever the
+// previous token has expired, so an external process can keep it fresh.
+func FileTokenSource(path string) TokenSource {
+	return ReuseTokenSource(nil, fileTokenSource(path))
+}
+
+type fileTokenSource string
+
+func (s fileTokenSource) Token(context.Context) (*Token, error) {
+	t, err := readTokenFile(string(s))
+	if err != nil {
+		return nil, err
+	}
+	if !t.Valid() {
+		return nil, fmt.Errorf("ivcap: token in %s: %w", string(s), ErrTokenExpired)
+	}
+	return t, nil
+}
+
+// readTokenFile reads a bare JWT or a JSON encoded Token from path.
+func readTokenFile(path string) (*Token, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("ivcap: reading token: %w", err)
+	}
+	s := strings.TrimSpace(string(b))
+	if !strings.HasPrefix(s, "{") {
+		return NewToken(s), nil
+	}
+	var t Token
+	if err := json.Unmarshal([]byte(s), &t); err != nil {
+		return nil, fmt.Errorf("ivcap: decoding token in %s: %w", path, err)
+	}
+	if t.Expiry.IsZero() {
+		t.Expiry = jwtExpiry(t.AccessToken)
+	}
+	return &t, nil
+}
+
+// writeTokenFile stores t JSON encoded at path, readable by the owner only.
+func writeTokenFile(path string, t *Token) error {
+	b, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0600)
+}
+
+// ReuseTokenSource returns a token source which returns t as long as it is
+// valid and asks src for a new token once it has expired. Concurrent callers
+// share a single refresh.
+func ReuseTokenSource(t *Token, src TokenSource) TokenSource {
+	if rs, ok := src.(*reuseTokenSource); ok {
+		if t == nil {
+			return rs
+		}
+		src = rs.src
+	}
+	return &reuseTokenSource{src: src, token: t}
+}
+
+type reuseTokenSource struct {
+	src   TokenSource
+	mu    sync.Mutex
+	token *Token
+}
+
+func (s *reuseTokenSource) Token(ctx context.Context) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token.Valid() {
+		return s.token, nil
+	}
+	t, err := s.src.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.token = t
+	return t, nil
+}
+
+// authDoer sets the Authorization header of every request which does not
+// already carry a JWT from its payload.
+type authDoer struct {
+	doer goahttp.Doer
+	src  TokenSource
+}
+
+func (d *authDoer) Do(req *http.Request) (*http.Response, error) {
+	if h := req.Header.Get("Authorization"); h != "" && h != "Bearer " {
+		return d.doer.Do(req)
+	}
+	t, err := d.src.Token(req.Context())
+	if err != nil {
+		if ex := exchangeFrom(req.Context()); ex != nil {
+			ex.authErr = err
+		}
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+t.AccessToken)
+	return d.doer.Do(req)
+}
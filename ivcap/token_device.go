@@ -0,0 +1,307 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	goahttp "goa.design/goa/v3/http"
+)
+
+// ErrSignInRequired is returned by the device code token source when the user
+// needs to sign in but the source is not allowed to prompt them, see
+// DeviceCodeConfig.Interactive.
+var ErrSignInRequired = errors.New("ivcap: sign-in required")
+
+// DeviceCodeConfig configures the OAuth2 device authorization grant (RFC 8628)
+// used to obtain tokens for interactive users.
+type DeviceCodeConfig struct {
+	// ClientID is the OAuth2 client ID registered for the application.
+	ClientID string `yaml:"client-id"`
+	// DeviceAuthURL is the device authorization endpoint of the identity
+	// provider.
+	DeviceAuthURL string `yaml:"device-auth-url"`
+	// TokenURL is the token endpoint of the identity provider.
+	TokenURL string `yaml:"token-url"`
+	// Scopes lists the requested scopes. "offline_access" is needed to obtain
+	// a refresh token.
+	Scopes []string `yaml:"scopes,omitempty"`
+	// Audience is the optional audience of the requested token.
+	Audience string `yaml:"audience,omitempty"`
+	// Interactive allows prompting the user to sign in when there is no
+	// token, or it cannot be refreshed. Otherwise the token source fails with
+	// ErrSignInRequired, so that unattended processes do not wait for a user.
+	Interactive bool `yaml:"interactive,omitempty"`
+	// Prompt is called with the URL the user needs to visit and the code to
+	// enter there. It defaults to printing both to stderr.
+	Prompt func(verificationURI, userCode string) `yaml:"-"`
+	// CachePath is the optional file the token is stored in, so it survives
+	// process restarts. It can be read with FileTokenSource.
+	CachePath string `yaml:"cache-path,omitempty"`
+	// Doer is the HTTP client used to talk to the identity provider. It
+	// defaults to http.DefaultClient.
+	Doer goahttp.Doer `yaml:"-"`
+}
+
+// DeviceCodeTokenSource returns a token source which obtains tokens through
+// the OAuth2 device authorization grant. Expired tokens are refreshed with the
+// refresh token if one was issued; otherwise, or if the identity provider
+// rejects the refresh token, the user is prompted again with Interactive set.
+// Concurrent callers share the same refresh or sign-in.
+func DeviceCodeTokenSource(cfg DeviceCodeConfig) TokenSource {
+	if cfg.Doer == nil {
+		cfg.Doer = http.DefaultClient
+	}
+	if cfg.Prompt == nil {
+		cfg.Prompt = func(uri, code string) {
+			fmt.Fprintf(os.Stderr, "To sign in, visit %s and enter the code %s\n", uri, code)
+		}
+	}
+	return &deviceCodeTokenSource{cfg: cfg}
+}
+
+type deviceCodeTokenSource struct {
+	cfg   DeviceCodeConfig
+	mu    sync.Mutex
+	token *Token
+	// pending is the refresh or sign-in in progress, if any.
+	pending *tokenFetch
+}
+
+// tokenFetch is a refresh or sign-in shared by the callers waiting for it.
+type tokenFetch struct {
+	done  chan struct{}
+	token *Token
+	err   error
+}
+
+// tokenResponse is the response of an OAuth2 token endpoint, successful or
+// not.
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func (s *deviceCodeTokenSource) Token(ctx context.Context) (*Token, error) {
+	s.mu.Lock()
+	if s.token == nil && s.cfg.CachePath != "" {
+		if t, err := readTokenFile(s.cfg.CachePath); err == nil {
+			s.token = t
+		}
+	}
+	if s.token.Valid() {
+		t := s.token
+		s.mu.Unlock()
+		return t, nil
+	}
+	if f := s.pending; f != nil {
+		s.mu.Unlock()
+		select {
+		case <-f.done:
+			return f.token, f.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	f := &tokenFetch{done: make(chan struct{})}
+	s.pending = f
+	old := s.token
+	s.mu.Unlock()
+
+	t, err := s.fetch(ctx, old)
+	s.mu.Lock()
+	if t != nil {
+		s.token = t
+	}
+	s.pending = nil
+	s.mu.Unlock()
+	if err != nil {
+		t = nil
+	}
+	f.token, f.err = t, err
+	close(f.done)
+	return f.token, f.err
+}
+
+// fetch refreshes the expired token old, or has the user sign in, and caches
+// the new token. The token is returned even if caching it fails.
+func (s *deviceCodeTokenSource) fetch(ctx context.Context, old *Token) (*Token, error) {
+	err := ErrSignInRequired
+	var t *Token
+	if old != nil && old.RefreshToken != "" {
+		t, err = s.refresh(ctx, old.RefreshToken)
+	}
+	if errors.Is(err, ErrSignInRequired) && s.cfg.Interactive {
+		t, err = s.authorize(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if s.cfg.CachePath != "" {
+		if err := writeTokenFile(s.cfg.CachePath, t); err != nil {
+			return t, fmt.Errorf("ivcap: caching token: %w", err)
+		}
+	}
+	return t, nil
+}
+
+// refresh exchanges a refresh token for a new token. A refresh token which is
+// rejected as invalid or expired fails with ErrSignInRequired.
+func (s *deviceCodeTokenSource) refresh(ctx context.Context, refreshToken string) (*Token, error) {
+	res, err := s.post(ctx, s.cfg.TokenURL, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+		"client_id":     {s.cfg.ClientID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res.Error == "invalid_grant" {
+		return nil, fmt.Errorf("%w: refreshing token: %s", ErrSignInRequired, res.describe())
+	}
+	if res.Error != "" {
+		return nil, fmt.Errorf("ivcap: refreshing token: %s", res.describe())
+	}
+	t := res.token()
+	if t.RefreshToken == "" {
+		t.RefreshToken = refreshToken
+	}
+	return t, nil
+}
+
+// authorize runs the device authorization flow, prompting the user and
+// polling the token endpoint until the user has signed in.
+func (s *deviceCodeTokenSource) authorize(ctx context.Context) (*Token, error) {
+	form := url.Values{"client_id": {s.cfg.ClientID}}
+	if len(s.cfg.Scopes) > 0 {
+		form.Set("scope", strings.Join(s.cfg.Scopes, " "))
+	}
+	if s.cfg.Audience != "" {
+		form.Set("audience", s.cfg.Audience)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", s.cfg.DeviceAuthURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := s.cfg.Doer.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ivcap: requesting device code: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("ivcap: requesting device code: unexpected status %d: %s", resp.StatusCode, body)
+	}
+	var dc struct {
+		DeviceCode              string `json:"device_code"`
+		UserCode                string `json:"user_code"`
+		VerificationURI         string `json:"verification_uri"`
+		VerificationURIComplete string `json:"verification_uri_complete"`
+		ExpiresIn               int64  `json:"expires_in"`
+		Interval                int64  `json:"interval"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&dc); err != nil {
+		return nil, fmt.Errorf("ivcap: decoding device code: %w", err)
+	}
+	uri := dc.VerificationURIComplete
+	if uri == "" {
+		uri = dc.VerificationURI
+	}
+	s.cfg.Prompt(uri, dc.UserCode)
+
+	interval := time.Duration(dc.Interval) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	deadline := time.Now().Add(time.Duration(dc.ExpiresIn) * time.Second)
+	for dc.ExpiresIn <= 0 || time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+		res, err := s.post(ctx, s.cfg.TokenURL, url.Values{
+			"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+			"device_code": {dc.DeviceCode},
+			"client_id":   {s.cfg.ClientID},
+		})
+		if err != nil {
+			return nil, err
+		}
+		switch res.Error {
+		case "":
+			return res.token(), nil
+		case "authorization_pending":
+		case "slow_down":
+			interval += 5 * time.Second
+		default:
+			return nil, fmt.Errorf("ivcap: device authorization: %s", res.describe())
+		}
+	}
+	return nil, errors.New("ivcap: device authorization: device code expired")
+}
+
+// post submits form to the token endpoint at u.
+func (s *deviceCodeTokenSource) post(ctx context.Context, u string, form url.Values) (*tokenResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", u, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := s.cfg.Doer.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ivcap: requesting token: %w", err)
+	}
+	defer resp.Body.Close()
+	var res tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, fmt.Errorf("ivcap: decoding token response (status %d): %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK && res.Error == "" {
+		return nil, fmt.Errorf("ivcap: requesting token: unexpected status %d", resp.StatusCode)
+	}
+	return &res, nil
+}
+
+func (r *tokenResponse) token() *Token {
+	t := &Token{AccessToken: r.AccessToken, RefreshToken: r.RefreshToken}
+	if r.ExpiresIn > 0 {
+		t.Expiry = time.Now().Add(time.Duration(r.ExpiresIn) * time.Second)
+	} else {
+		t.Expiry = jwtExpiry(r.AccessToken)
+	}
+	return t
+}
+
+func (r *tokenResponse) describe() string {
+	if r.ErrorDescription != "" {
+		return r.Error + ": " + r.ErrorDescription
+	}
+	return r.Error
+}
@@ -0,0 +1,166 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcap_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ivcap-works/ivcap-core-api/ivcap"
+)
+
+// identityProvider is a fake OAuth2 identity provider answering refresh
+// requests with refresh, and device code requests with a token at once.
+type identityProvider struct {
+	refresh     func(w http.ResponseWriter)
+	deviceCodes int32
+}
+
+func (p *identityProvider) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.URL.Path == "/device":
+		atomic.AddInt32(&p.deviceCodes, 1)
+		json.NewEncoder(w).Encode(map[string]any{"device_code": "dc", "user_code": "UC", "verification_uri": "https://example.com", "interval": 1})
+	case r.Form.Get("grant_type") == "refresh_token":
+		p.refresh(w)
+	default:
+		json.NewEncoder(w).Encode(map[string]any{"access_token": "signed-in", "expires_in": 3600})
+	}
+}
+
+// expiredToken writes an expired token with a refresh token to a file and
+// returns its path.
+func expiredToken(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "token.json")
+	b, _ := json.Marshal(&ivcap.Token{AccessToken: "expired", RefreshToken: "rt", Expiry: time.Now().Add(-time.Hour)})
+	if err := os.WriteFile(path, b, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDeviceCodeTokenSource(t *testing.T) {
+	refreshed := func(w http.ResponseWriter) {
+		json.NewEncoder(w).Encode(map[string]any{"access_token": "refreshed", "expires_in": 3600})
+	}
+	rejected := func(w http.ResponseWriter) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{"error": "invalid_grant"})
+	}
+	failed := func(w http.ResponseWriter) {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]any{"error": "server_error"})
+	}
+	tests := []struct {
+		name        string
+		expired     bool
+		refresh     func(w http.ResponseWriter)
+		interactive bool
+		// want is the access token returned, or "" for an error, which is
+		// ErrSignInRequired with signInRequired set.
+		want           string
+		signInRequired bool
+		signIn         bool
+	}{
+		{name: "refreshed", expired: true, refresh: refreshed, want: "refreshed"},
+		{name: "rejected", expired: true, refresh: rejected, signInRequired: true},
+		{name: "rejected interactive", expired: true, refresh: rejected, interactive: true, want: "signed-in", signIn: true},
+		{name: "refresh failed", expired: true, refresh: failed, interactive: true},
+		{name: "no token", signInRequired: true},
+		{name: "no token interactive", interactive: true, want: "signed-in", signIn: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idp := &identityProvider{refresh: tt.refresh}
+			srv := httptest.NewServer(idp)
+			t.Cleanup(srv.Close)
+			cfg := ivcap.DeviceCodeConfig{
+				ClientID:      "client",
+				DeviceAuthURL: srv.URL + "/device",
+				TokenURL:      srv.URL + "/token",
+				Interactive:   tt.interactive,
+				Prompt:        func(string, string) {},
+			}
+			if tt.expired {
+				cfg.CachePath = expiredToken(t)
+			}
+			tok, err := ivcap.DeviceCodeTokenSource(cfg).Token(context.Background())
+			switch {
+			case tt.want != "" && err != nil:
+				t.Fatal(err)
+			case tt.want != "" && tok.AccessToken != tt.want:
+				t.Errorf("token %q, want %q", tok.AccessToken, tt.want)
+			case tt.want == "" && err == nil:
+				t.Errorf("got token %q, want an error", tok.AccessToken)
+			case tt.want == "" && errors.Is(err, ivcap.ErrSignInRequired) != tt.signInRequired:
+				t.Errorf("error %v, sign-in required: %v", err, tt.signInRequired)
+			}
+			if signIn := idp.deviceCodes > 0; signIn != tt.signIn {
+				t.Errorf("signed in: %v, want %v", signIn, tt.signIn)
+			}
+		})
+	}
+}
+
+func TestDeviceCodeTokenSourceConcurrent(t *testing.T) {
+	idp := &identityProvider{}
+	srv := httptest.NewServer(idp)
+	t.Cleanup(srv.Close)
+	prompted := make(chan struct{})
+	src := ivcap.DeviceCodeTokenSource(ivcap.DeviceCodeConfig{
+		ClientID:      "client",
+		DeviceAuthURL: srv.URL + "/device",
+		TokenURL:      srv.URL + "/token",
+		Interactive:   true,
+		Prompt:        func(string, string) { close(prompted) },
+	})
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if _, err := src.Token(ctx); err != nil {
+			t.Error(err)
+		}
+	}()
+	<-prompted
+
+	// A caller giving up while the user signs in is not blocked.
+	cctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, err := src.Token(cctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error %v, want %v", err, context.DeadlineExceeded)
+	}
+	tok, err := src.Token(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wg.Wait()
+	if tok.AccessToken != "signed-in" || idp.deviceCodes != 1 {
+		t.Errorf("got %q after %d sign-ins, want one sign-in", tok.AccessToken, idp.deviceCodes)
+	}
+}
@@ -0,0 +1,260 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ivcaperr defines the error kinds shared by all IVCAP services. Every
+// service package declares its own error types, such as
+// artifact.ResourceNotFoundT and order.ResourceNotFoundT. The Error type wraps
+// them with the kind they belong to, so that a single
+//
+//	errors.Is(err, ivcaperr.ErrNotFound)
+//
+// check works for all services.
+package ivcaperr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	goahttp "goa.design/goa/v3/http"
+	goa "goa.design/goa/v3/pkg"
+)
+
+// Error kinds. Use errors.Is to test an error against them.
+var (
+	// ErrBadRequest indicates a malformed request.
+	ErrBadRequest = errors.New("bad request")
+	// ErrInvalidParameter indicates a request parameter with the wrong value.
+	ErrInvalidParameter = errors.New("invalid parameter")
+	// ErrUnauthorized indicates a missing, invalid or expired token.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden indicates the caller lacks the scopes required.
+	ErrForbidden = errors.New("forbidden")
+	// ErrNotFound indicates the resource does not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrAlreadyExists indicates the resource has already been created.
+	ErrAlreadyExists = errors.New("already exists")
+	// ErrConflict indicates the request conflicts with the current state of
+	// the resource.
+	ErrConflict = errors.New("conflict")
+	// ErrUnsupportedContentType indicates a content type the service does
+	// not accept.
+	ErrUnsupportedContentType = errors.New("unsupported content type")
+	// ErrNotImplemented indicates a method the service does not implement.
+	ErrNotImplemented = errors.New("not implemented")
+	// ErrUnavailable indicates the service is temporarily unavailable.
+	ErrUnavailable = errors.New("service unavailable")
+	// ErrTransport indicates the request could not be sent or the response
+	// could not be received.
+	ErrTransport = errors.New("transport failure")
+	// ErrInvalidResponse indicates a response which could not be decoded or
+	// had an unexpected status.
+	ErrInvalidResponse = errors.New("invalid response")
+	// ErrInternal indicates any other failure.
+	ErrInternal = errors.New("internal error")
+)
+
+// Error is an error returned by a method of an IVCAP service.
+type Error struct {
+	// Kind is one of the error kinds declared in this package.
+	Kind error
+	// Status is the HTTP status of the response, or 0 if none was received.
+	Status int
+	// Service is the name of the service, such as "artifact".
+	Service string
+	// Method is the name of the method, such as "read".
+	Method string
+	// RequestID is the ID the deployment assigned to the request, if any.
+	RequestID string
+	// Err is the underlying error, usually one of the error types of the
+	// service package.
+	Err error
+}
+
+// Error returns an error description.
+func (e *Error) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s: %s", e.Service, e.Method, e.Kind)
+	if e.Status != 0 {
+		fmt.Fprintf(&b, " (status %d", e.Status)
+		if e.RequestID != "" {
+			fmt.Fprintf(&b, ", request %s", e.RequestID)
+		}
+		b.WriteString(")")
+	}
+	if e.Err != nil && e.Err.Error() != e.Kind.Error() {
+		fmt.Fprintf(&b, ": %s", e.Err)
+	}
+	return b.String()
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the kind of e.
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
+
+// Wrap returns err as an *Error of method of service svc. status and
+// requestID describe the response err was decoded from, if any. Wrap returns
+// nil if err is nil and err itself if it already is an *Error.
+func Wrap(err error, svc, method string, status int, requestID string) error {
+	if err == nil {
+		return nil
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return err
+	}
+	kind := KindOf(err)
+	if kind == ErrInvalidResponse {
+		if k, ok := statusKinds[status]; ok {
+			kind = k
+		}
+	}
+	if status == 0 {
+		status = kindStatus[kind]
+	}
+	return &Error{
+		Kind:      kind,
+		Status:    status,
+		Service:   svc,
+		Method:    method,
+		RequestID: requestID,
+		Err:       err,
+	}
+}
+
+// typeKinds maps the names of the error types declared by the service
+// packages to their kind.
+var typeKinds = map[string]error{
+	"BadRequestT":             ErrBadRequest,
+	"InvalidParameterT":       ErrInvalidParameter,
+	"UnauthorizedT":           ErrUnauthorized,
+	"InvalidScopesT":          ErrForbidden,
+	"ResourceNotFoundT":       ErrNotFound,
+	"ResourceAlreadyCreatedT": ErrAlreadyExists,
+	"NotUniqueResourceT":      ErrConflict,
+	"UnsupportedContentTypeT": ErrUnsupportedContentType,
+	"NotImplementedT":         ErrNotImplemented,
+	"ServiceNotAvailableT":    ErrUnavailable,
+}
+
+// kindStatus maps error kinds to the HTTP status the services respond with.
+var kindStatus = map[error]int{
+	ErrBadRequest:             http.StatusBadRequest,
+	ErrInvalidParameter:       http.StatusUnprocessableEntity,
+	ErrUnauthorized:           http.StatusUnauthorized,
+	ErrForbidden:              http.StatusForbidden,
+	ErrNotFound:               http.StatusNotFound,
+	ErrAlreadyExists:          http.StatusConflict,
+	ErrConflict:               http.StatusFailedDependency,
+	ErrUnsupportedContentType: http.StatusUnsupportedMediaType,
+	ErrNotImplemented:         http.StatusNotImplemented,
+	ErrUnavailable:            http.StatusServiceUnavailable,
+	ErrInternal:               http.StatusInternalServerError,
+}
+
+// statusKinds maps the HTTP status of responses the services did not declare
+// to the kind they most likely indicate.
+var statusKinds = map[int]error{
+	http.StatusBadRequest:           ErrBadRequest,
+	http.StatusUnauthorized:         ErrUnauthorized,
+	http.StatusForbidden:            ErrForbidden,
+	http.StatusNotFound:             ErrNotFound,
+	http.StatusConflict:             ErrAlreadyExists,
+	http.StatusUnsupportedMediaType: ErrUnsupportedContentType,
+	http.StatusUnprocessableEntity:  ErrInvalidParameter,
+	http.StatusFailedDependency:     ErrConflict,
+	http.StatusTooManyRequests:      ErrUnavailable,
+	http.StatusInternalServerError:  ErrInternal,
+	http.StatusNotImplemented:       ErrNotImplemented,
+	http.StatusBadGateway:           ErrUnavailable,
+	http.StatusServiceUnavailable:   ErrUnavailable,
+	http.StatusGatewayTimeout:       ErrUnavailable,
+}
+
+// KindOf returns the kind of err: one of the error kinds declared in this
+// package, or nil if err is nil. Errors which cannot be classified are of kind
+// ErrInternal.
+func KindOf(err error) error {
+	if err == nil {
+		return nil
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+	for _, k := range kinds {
+		if err == k {
+			return k
+		}
+	}
+	if t := reflect.TypeOf(err); t.Kind() == reflect.Ptr {
+		if k, ok := typeKinds[t.Elem().Name()]; ok {
+			return k
+		}
+	}
+	var ce *goahttp.ClientError
+	if errors.As(err, &ce) {
+		switch ce.Name {
+		case "request_error":
+			return ErrTransport
+		case "invalid_response", "decoding_error", "validation_error":
+			return ErrInvalidResponse
+		case "invalid_type", "encoding_error", "invalid_url":
+			return ErrBadRequest
+		}
+	}
+	var se *goa.ServiceError
+	if errors.As(err, &se) {
+		switch se.Name {
+		case goa.InvalidFieldType, goa.MissingField, goa.InvalidEnumValue, goa.InvalidFormat,
+			goa.InvalidPattern, goa.InvalidRange, goa.InvalidLength:
+			return ErrBadRequest
+		case "unauthorized":
+			return ErrUnauthorized
+		}
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return ErrTransport
+	}
+	return ErrInternal
+}
+
+// kinds lists all error kinds.
+var kinds = []error{
+	ErrBadRequest, ErrInvalidParameter, ErrUnauthorized, ErrForbidden,
+	ErrNotFound, ErrAlreadyExists, ErrConflict, ErrUnsupportedContentType,
+	ErrNotImplemented, ErrUnavailable, ErrTransport, ErrInvalidResponse,
+	ErrInternal,
+}
+
+// HTTPStatus returns the HTTP status a service responds with for err.
+func HTTPStatus(err error) int {
+	var e *Error
+	if errors.As(err, &e) && e.Status != 0 {
+		return e.Status
+	}
+	if s, ok := kindStatus[KindOf(err)]; ok {
+		return s
+	}
+	return http.StatusInternalServerError
+}
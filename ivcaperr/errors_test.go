@@ -0,0 +1,176 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcaperr_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	artifact "github.com/ivcap-works/ivcap-core-api/gen/artifact"
+	aspect "github.com/ivcap-works/ivcap-core-api/gen/aspect"
+	order "github.com/ivcap-works/ivcap-core-api/gen/order"
+	"github.com/ivcap-works/ivcap-core-api/ivcaperr"
+	goahttp "goa.design/goa/v3/http"
+	goa "goa.design/goa/v3/pkg"
+)
+
+func TestWrapStatus(t *testing.T) {
+	tests := []struct {
+		status     int
+		kind       error
+		wantStatus int
+	}{
+		{status: http.StatusBadRequest, kind: ivcaperr.ErrBadRequest},
+		{status: http.StatusUnauthorized, kind: ivcaperr.ErrUnauthorized},
+		{status: http.StatusForbidden, kind: ivcaperr.ErrForbidden},
+		{status: http.StatusNotFound, kind: ivcaperr.ErrNotFound},
+		{status: http.StatusConflict, kind: ivcaperr.ErrAlreadyExists},
+		{status: http.StatusUnsupportedMediaType, kind: ivcaperr.ErrUnsupportedContentType},
+		{status: http.StatusUnprocessableEntity, kind: ivcaperr.ErrInvalidParameter},
+		{status: http.StatusFailedDependency, kind: ivcaperr.ErrConflict},
+		{status: http.StatusTooManyRequests, kind: ivcaperr.ErrUnavailable},
+		{status: http.StatusInternalServerError, kind: ivcaperr.ErrInternal},
+		{status: http.StatusNotImplemented, kind: ivcaperr.ErrNotImplemented},
+		{status: http.StatusBadGateway, kind: ivcaperr.ErrUnavailable},
+		{status: http.StatusServiceUnavailable, kind: ivcaperr.ErrUnavailable},
+		{status: http.StatusGatewayTimeout, kind: ivcaperr.ErrUnavailable},
+		{status: http.StatusTeapot, kind: ivcaperr.ErrInvalidResponse},
+		// Without a response, HTTPStatus falls back to 500.
+		{status: 0, kind: ivcaperr.ErrInvalidResponse, wantStatus: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.status), func(t *testing.T) {
+			err := ivcaperr.Wrap(goahttp.ErrInvalidResponse("artifact", "read", tt.status, "body"), "artifact", "read", tt.status, "req-1")
+			var e *ivcaperr.Error
+			if !errors.As(err, &e) {
+				t.Fatalf("got %T, want *ivcaperr.Error", err)
+			}
+			if e.Kind != tt.kind || !errors.Is(err, tt.kind) {
+				t.Errorf("kind %v, want %v", e.Kind, tt.kind)
+			}
+			if ivcaperr.KindOf(err) != tt.kind {
+				t.Errorf("KindOf = %v, want %v", ivcaperr.KindOf(err), tt.kind)
+			}
+			want := tt.wantStatus
+			if want == 0 {
+				want = tt.status
+			}
+			if e.Status != tt.status || ivcaperr.HTTPStatus(err) != want {
+				t.Errorf("status %d, HTTPStatus %d, want %d and %d", e.Status, ivcaperr.HTTPStatus(err), tt.status, want)
+			}
+		})
+	}
+}
+
+func TestKindOf(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		kind   error
+		status int
+	}{
+		{name: "nil", err: nil, kind: nil, status: http.StatusInternalServerError},
+		{name: "kind", err: ivcaperr.ErrNotFound, kind: ivcaperr.ErrNotFound, status: http.StatusNotFound},
+		{name: "artifact not found", err: &artifact.ResourceNotFoundT{ID: "a"}, kind: ivcaperr.ErrNotFound, status: http.StatusNotFound},
+		{name: "order not found", err: &order.ResourceNotFoundT{ID: "o"}, kind: ivcaperr.ErrNotFound, status: http.StatusNotFound},
+		{name: "aspect not unique", err: &aspect.NotUniqueResourceT{}, kind: ivcaperr.ErrConflict, status: http.StatusFailedDependency},
+		{name: "unavailable", err: &artifact.ServiceNotAvailableT{}, kind: ivcaperr.ErrUnavailable, status: http.StatusServiceUnavailable},
+		{name: "unauthorized", err: &artifact.UnauthorizedT{}, kind: ivcaperr.ErrUnauthorized, status: http.StatusUnauthorized},
+		{name: "request error", err: goahttp.ErrRequestError("artifact", "read", errors.New("refused")), kind: ivcaperr.ErrTransport, status: http.StatusInternalServerError},
+		{name: "decoding error", err: goahttp.ErrDecodingError("artifact", "read", errors.New("bad")), kind: ivcaperr.ErrInvalidResponse, status: http.StatusInternalServerError},
+		{name: "invalid url", err: goahttp.ErrInvalidURL("artifact", "read", ":", errors.New("bad")), kind: ivcaperr.ErrBadRequest, status: http.StatusBadRequest},
+		{name: "missing field", err: goa.MissingFieldError("id", "body"), kind: ivcaperr.ErrBadRequest, status: http.StatusBadRequest},
+		{name: "canceled", err: fmt.Errorf("reading: %w", context.Canceled), kind: ivcaperr.ErrTransport, status: http.StatusInternalServerError},
+		{name: "deadline", err: context.DeadlineExceeded, kind: ivcaperr.ErrTransport, status: http.StatusInternalServerError},
+		{name: "other", err: errors.New("disk full"), kind: ivcaperr.ErrInternal, status: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ivcaperr.KindOf(tt.err); got != tt.kind {
+				t.Errorf("KindOf = %v, want %v", got, tt.kind)
+			}
+			if got := ivcaperr.HTTPStatus(tt.err); got != tt.status {
+				t.Errorf("HTTPStatus = %d, want %d", got, tt.status)
+			}
+		})
+	}
+}
+
+func TestIsAs(t *testing.T) {
+	cause := &artifact.ResourceNotFoundT{ID: "urn:ivcap:artifact:1"}
+	wrapped := ivcaperr.Wrap(cause, "artifact", "read", 0, "")
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "wrapped", err: wrapped},
+		{name: "annotated", err: fmt.Errorf("downloading: %w", wrapped)},
+		{name: "wrapped twice", err: ivcaperr.Wrap(fmt.Errorf("downloading: %w", wrapped), "artifact", "download", 500, "")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !errors.Is(tt.err, ivcaperr.ErrNotFound) {
+				t.Error("not ErrNotFound")
+			}
+			for _, k := range []error{ivcaperr.ErrConflict, ivcaperr.ErrInternal, ivcaperr.ErrInvalidResponse} {
+				if errors.Is(tt.err, k) {
+					t.Errorf("matches %v", k)
+				}
+			}
+			var nf *artifact.ResourceNotFoundT
+			if !errors.As(tt.err, &nf) || nf != cause {
+				t.Errorf("As *artifact.ResourceNotFoundT = %v, want the cause", nf)
+			}
+			var onf *order.ResourceNotFoundT
+			if errors.As(tt.err, &onf) {
+				t.Error("As *order.ResourceNotFoundT succeeded")
+			}
+			var e *ivcaperr.Error
+			if !errors.As(tt.err, &e) {
+				t.Fatal("not an *ivcaperr.Error")
+			}
+			if e.Service != "artifact" || e.Method != "read" || e.Status != http.StatusNotFound {
+				t.Errorf("got %s %s status %d, want artifact read status 404", e.Service, e.Method, e.Status)
+			}
+		})
+	}
+	if ivcaperr.Wrap(nil, "artifact", "read", 0, "") != nil {
+		t.Error("Wrap(nil) is not nil")
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{
+			err:  ivcaperr.Wrap(&artifact.ResourceNotFoundT{}, "artifact", "read", 404, "req-1"),
+			want: "artifact read: not found (status 404, request req-1): NotFound is the type returned when attempting to manage a resource that does not exist.",
+		},
+		{
+			err:  &ivcaperr.Error{Kind: ivcaperr.ErrTransport, Service: "order", Method: "list", Err: ivcaperr.ErrTransport},
+			want: "order list: transport failure",
+		},
+	}
+	for _, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("got %q, want %q", got, tt.want)
+		}
+	}
+}
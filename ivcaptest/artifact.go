@@ -0,0 +1,369 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcaptest
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	artifact "github.com/ivcap-works/ivcap-core-api/gen/artifact"
+	artifactsvr "github.com/ivcap-works/ivcap-core-api/http/artifact/server"
+	"goa.design/goa/v3/security"
+)
+
+// ArtifactService is an in-memory fake of the artifact service.
+type ArtifactService struct {
+	d     *Deployment
+	mu    sync.Mutex
+	items map[string]*artifactRecord
+	order []*artifactRecord
+}
+
+type artifactRecord struct {
+	id         string
+	name       *string
+	collection *string
+	status     string
+	mimeType   *string
+	encoding   *string
+	size       int64
+	length     int64
+	etag       string
+	policy     *string
+	account    string
+	createdAt  time.Time
+	modifiedAt time.Time
+	content    []byte
+}
+
+var _ artifact.Service = (*ArtifactService)(nil)
+var _ artifact.Auther = (*ArtifactService)(nil)
+var _ artifactsvr.Resumer = (*ArtifactService)(nil)
+var _ artifactsvr.Blobber = (*ArtifactService)(nil)
+
+// JWTAuth implements artifact.Auther.
+func (s *ArtifactService) JWTAuth(ctx context.Context, token string, scheme *security.JWTScheme) (context.Context, error) {
+	return s.d.authorize(ctx, token, scheme, &artifact.UnauthorizedT{})
+}
+
+// List implements artifact.Service.
+func (s *ArtifactService) List(ctx context.Context, p *artifact.ListPayload) (*artifact.ArtifactListRT, error) {
+	q, offset, err := s.query(p)
+	if err != nil {
+		return nil, &artifact.BadRequestT{Message: err.Error()}
+	}
+	at, err := s.d.atTime(q.AtTime)
+	if err != nil {
+		return nil, &artifact.InvalidParameterT{Name: "at-time", Message: err.Error(), Value: q.AtTime}
+	}
+	s.mu.Lock()
+	var all []*artifactRecord
+	for _, r := range s.order {
+		if !r.createdAt.After(at) {
+			all = append(all, r)
+		}
+	}
+	s.mu.Unlock()
+	all, err = filterRecords(all, q.Filter, (*artifactRecord).fields)
+	if err != nil {
+		return nil, &artifact.InvalidParameterT{Name: "filter", Message: err.Error(), Value: q.Filter}
+	}
+	sortRecords(all, q.OrderBy, q.OrderDesc, (*artifactRecord).fields)
+	page, links := paginate(all, offset, q.Limit, func(o int) string {
+		return s.d.link("/1/artifacts", url.Values{"limit": {strconv.Itoa(q.Limit)}, "page": {encodeCursor(q, o)}})
+	})
+	res := &artifact.ArtifactListRT{AtTime: timePtr(at)}
+	for _, r := range page {
+		res.Items = append(res.Items, &artifact.ArtifactListItem{
+			ID:        r.id,
+			Name:      r.name,
+			Status:    r.status,
+			Size:      &r.size,
+			MimeType:  r.mimeType,
+			CreatedAt: formatTime(r.createdAt),
+			Href:      s.d.link("/1/artifacts/"+r.id, nil),
+		})
+	}
+	for _, l := range links {
+		res.Links = append(res.Links, &artifact.LinkT{Rel: l[0], Type: "application/json", Href: l[1]})
+	}
+	return res, nil
+}
+
+// query returns the query and offset of a list request, restoring them from
+// the page token if one is given.
+func (s *ArtifactService) query(p *artifact.ListPayload) (artifact.ListPayload, int, error) {
+	if p.Page != nil {
+		q, offset, err := decodeCursor[artifact.ListPayload](*p.Page)
+		q.Limit = p.Limit
+		return q, offset, err
+	}
+	q := *p
+	q.JWT = ""
+	if q.AtTime == nil {
+		q.AtTime = timePtr(s.d.now())
+	}
+	return q, 0, nil
+}
+
+// Read implements artifact.Service.
+func (s *ArtifactService) Read(ctx context.Context, p *artifact.ReadPayload) (*artifact.ArtifactStatusRT, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.items[p.ID]
+	if !ok {
+		return nil, &artifact.ResourceNotFoundT{ID: p.ID, Message: "artifact not found"}
+	}
+	return s.status(r), nil
+}
+
+// Upload implements artifact.Service. Content shorter than the announced
+// Upload-Length leaves the artifact in "partial" state.
+func (s *ArtifactService) Upload(ctx context.Context, p *artifact.UploadPayload, body io.ReadCloser) (*artifact.ArtifactUploadRT, error) {
+	defer body.Close()
+	content, err := io.ReadAll(body)
+	if err != nil {
+		return nil, &artifact.BadRequestT{Message: fmt.Sprintf("reading content: %s", err)}
+	}
+	now := s.d.now()
+	r := &artifactRecord{
+		id:         newUUIDURN(),
+		name:       p.Name,
+		collection: p.Collection,
+		mimeType:   p.ContentType,
+		encoding:   p.ContentEncoding,
+		policy:     p.Policy,
+		account:    Account(User(ctx)),
+		createdAt:  now,
+		modifiedAt: now,
+	}
+	if r.mimeType == nil {
+		r.mimeType = p.XContentType
+	}
+	r.length = -1
+	if p.UploadLength != nil {
+		r.length = int64(*p.UploadLength)
+	} else if p.XContentLength != nil {
+		r.length = int64(*p.XContentLength)
+	}
+	r.append(content)
+
+	s.mu.Lock()
+	s.items[r.id] = r
+	s.order = append(s.order, r)
+	st := s.status(r)
+	s.mu.Unlock()
+
+	res := &artifact.ArtifactUploadRT{
+		Location:       s.d.link("/1/artifacts/"+r.id, nil),
+		TusResumable:   p.TusResumable,
+		TusOffset:      &r.size,
+		ID:             st.ID,
+		Name:           st.Name,
+		Status:         st.Status,
+		MimeType:       st.MimeType,
+		Size:           st.Size,
+		Etag:           st.Etag,
+		CreatedAt:      st.CreatedAt,
+		LastModifiedAt: st.LastModifiedAt,
+		Policy:         st.Policy,
+		Account:        st.Account,
+		DataHref:       st.DataHref,
+		Links:          st.Links,
+	}
+	return res, nil
+}
+
+// tusScheme is the security scheme checked for TUS requests, matching the
+// one of the upload method.
+var tusScheme = &security.JWTScheme{
+	Name:           "jwt",
+	Scopes:         []string{"consumer:read", "consumer:write"},
+	RequiredScopes: []string{"consumer:write"},
+}
+
+// blobScheme is the security scheme checked for content requests, matching
+// the one of the read method.
+var blobScheme = &security.JWTScheme{
+	Name:           "jwt",
+	Scopes:         []string{"consumer:read", "consumer:write"},
+	RequiredScopes: []string{"consumer:read"},
+}
+
+// UploadOffset implements server.Resumer.
+func (s *ArtifactService) UploadOffset(ctx context.Context, jwt, id string) (int64, int64, error) {
+	if _, err := s.d.authorize(ctx, jwt, tusScheme, &artifact.UnauthorizedT{}); err != nil {
+		return 0, 0, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.items[id]
+	if !ok {
+		return 0, 0, &artifact.ResourceNotFoundT{ID: id, Message: "artifact not found"}
+	}
+	return r.size, r.length, nil
+}
+
+// AppendUpload implements server.Resumer. Content beyond the announced
+// Upload-Length is rejected.
+func (s *ArtifactService) AppendUpload(ctx context.Context, jwt, id string, offset int64, body io.Reader) (int64, error) {
+	if _, err := s.d.authorize(ctx, jwt, tusScheme, &artifact.UnauthorizedT{}); err != nil {
+		return 0, err
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return 0, &artifact.BadRequestT{Message: fmt.Sprintf("reading content: %s", err)}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.items[id]
+	if !ok {
+		return 0, &artifact.ResourceNotFoundT{ID: id, Message: "artifact not found"}
+	}
+	if offset != r.size {
+		return 0, artifactsvr.ErrOffsetMismatch
+	}
+	if r.length >= 0 && r.size+int64(len(data)) > r.length {
+		return 0, &artifact.BadRequestT{Message: "content exceeds Upload-Length"}
+	}
+	r.append(data)
+	r.modifiedAt = s.d.now()
+	return r.size, nil
+}
+
+// Blob implements server.Blobber.
+func (s *ArtifactService) Blob(ctx context.Context, jwt, id string) (*artifactsvr.Blob, error) {
+	if _, err := s.d.authorize(ctx, jwt, blobScheme, &artifact.UnauthorizedT{}); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.items[id]
+	if !ok {
+		return nil, &artifact.ResourceNotFoundT{ID: id, Message: "artifact not found"}
+	}
+	return &artifactsvr.Blob{
+		Content:         bytes.NewReader(r.content),
+		Etag:            r.etag,
+		ContentType:     stringOr(r.mimeType, "application/octet-stream"),
+		ContentEncoding: stringOr(r.encoding, ""),
+		ModTime:         r.modifiedAt,
+	}, nil
+}
+
+// SetStatus sets the status of an artifact, for instance to simulate a
+// failed ingestion.
+func (s *ArtifactService) SetStatus(id, status string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.items[id]
+	if !ok {
+		return &artifact.ResourceNotFoundT{ID: id, Message: "artifact not found"}
+	}
+	r.status = status
+	r.modifiedAt = s.d.now()
+	return nil
+}
+
+// Content returns the content of an artifact.
+func (s *ArtifactService) Content(id string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.items[id]
+	if !ok {
+		return nil, &artifact.ResourceNotFoundT{ID: id, Message: "artifact not found"}
+	}
+	return r.content, nil
+}
+
+// append adds data to the content of r and updates its size, etag and status.
+// As for objects uploaded in one part to S3, the etag is the MD5 of the
+// content.
+func (r *artifactRecord) append(data []byte) {
+	r.content = append(r.content, data...)
+	r.size = int64(len(r.content))
+	sum := md5.Sum(r.content)
+	r.etag = `"` + hex.EncodeToString(sum[:]) + `"`
+	if r.length >= 0 && r.size < r.length {
+		r.status = "partial"
+	} else {
+		r.status = "ready"
+	}
+}
+
+func (s *ArtifactService) status(r *artifactRecord) *artifact.ArtifactStatusRT {
+	size, etag, account := r.size, r.etag, r.account
+	self := s.d.link("/1/artifacts/"+r.id, nil)
+	data := s.d.link("/1/artifacts/"+r.id+"/blob", nil)
+	return &artifact.ArtifactStatusRT{
+		ID:             r.id,
+		Name:           r.name,
+		Status:         r.status,
+		MimeType:       r.mimeType,
+		Size:           &size,
+		Etag:           &etag,
+		CreatedAt:      timePtr(r.createdAt),
+		LastModifiedAt: timePtr(r.modifiedAt),
+		Policy:         r.policy,
+		Account:        &account,
+		DataHref:       &data,
+		Links: []*artifact.LinkT{
+			{Rel: "self", Type: "application/json", Href: self},
+			{Rel: "data", Type: stringOr(r.mimeType, "application/octet-stream"), Href: data},
+		},
+	}
+}
+
+func (r *artifactRecord) fields(name string) (string, bool) {
+	switch name {
+	case "id":
+		return r.id, true
+	case "name":
+		return stringOr(r.name, ""), r.name != nil
+	case "collection":
+		return stringOr(r.collection, ""), r.collection != nil
+	case "status":
+		return r.status, true
+	case "mime-type", "mime_type", "mimeType":
+		return stringOr(r.mimeType, ""), r.mimeType != nil
+	case "size":
+		return strconv.FormatInt(r.size, 10), true
+	case "etag":
+		return r.etag, true
+	case "policy":
+		return stringOr(r.policy, ""), r.policy != nil
+	case "account":
+		return r.account, true
+	case "created-at", "created_at", "createdAt":
+		return formatTime(r.createdAt), true
+	}
+	return "", false
+}
+
+// stringOr returns the string s points to, or def if s is nil.
+func stringOr(s *string, def string) string {
+	if s == nil {
+		return def
+	}
+	return *s
+}
@@ -0,0 +1,214 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcaptest
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	aspect "github.com/ivcap-works/ivcap-core-api/gen/aspect"
+	"goa.design/goa/v3/security"
+)
+
+// AspectService is an in-memory fake of the aspect service.
+type AspectService struct {
+	d     *Deployment
+	store *statementStore
+}
+
+var _ aspect.Service = (*AspectService)(nil)
+var _ aspect.Auther = (*AspectService)(nil)
+
+// JWTAuth implements aspect.Auther.
+func (s *AspectService) JWTAuth(ctx context.Context, token string, scheme *security.JWTScheme) (context.Context, error) {
+	return s.d.authorize(ctx, token, scheme, &aspect.UnauthorizedT{})
+}
+
+// Read implements aspect.Service.
+func (s *AspectService) Read(ctx context.Context, p *aspect.ReadPayload) (*aspect.AspectRT, error) {
+	st, ok := s.store.get(p.ID)
+	if !ok {
+		return nil, &aspect.ResourceNotFoundT{ID: p.ID, Message: "aspect not found"}
+	}
+	res := &aspect.AspectRT{
+		ID:          st.id,
+		Entity:      st.entity,
+		Schema:      st.schema,
+		Content:     st.content,
+		ContentType: st.contentType,
+		ValidFrom:   formatTime(st.validFrom),
+		ValidTo:     timePtr(st.validTo),
+		Asserter:    st.asserter,
+		Account:     st.account,
+		Policy:      st.policy,
+		Links: []*aspect.LinkT{
+			{Rel: "self", Type: "application/json", Href: s.d.link("/1/aspects/"+url.PathEscape(st.id), nil)},
+		},
+	}
+	if st.retracter != "" {
+		res.Retracter = &st.retracter
+	}
+	if st.replaces != "" {
+		res.Replaces = &st.replaces
+	}
+	return res, nil
+}
+
+// List implements aspect.Service.
+func (s *AspectService) List(ctx context.Context, p *aspect.ListPayload) (*aspect.AspectListRT, error) {
+	q, offset, err := s.query(p)
+	if err != nil {
+		return nil, &aspect.BadRequestT{Message: err.Error()}
+	}
+	at, err := s.d.atTime(q.AtTime)
+	if err != nil {
+		return nil, &aspect.InvalidParameterT{Name: "at-time", Message: err.Error(), Value: q.AtTime}
+	}
+	all := s.store.query(at, q.Entity, q.Schema)
+	if q.ContentPath != nil && *q.ContentPath != "" {
+		var matched []*statement
+		for _, st := range all {
+			if _, ok := st.contentPath(*q.ContentPath); ok {
+				matched = append(matched, st)
+			}
+		}
+		all = matched
+	}
+	all, err = filterRecords(all, &q.Filter, (*statement).fields)
+	if err != nil {
+		return nil, &aspect.InvalidParameterT{Name: "filter", Message: err.Error(), Value: &q.Filter}
+	}
+	sortRecords(all, &q.OrderBy, strings.EqualFold(q.OrderDirection, "desc"), (*statement).fields)
+	page, links := paginate(all, offset, q.Limit, func(o int) string {
+		return s.d.link("/1/aspects", url.Values{"limit": {strconv.Itoa(q.Limit)}, "page": {encodeCursor(q, o)}})
+	})
+	res := &aspect.AspectListRT{
+		Entity:     q.Entity,
+		Schema:     q.Schema,
+		AspectPath: q.ContentPath,
+		AtTime:     formatTime(at),
+		Items:      []*aspect.AspectListItemRT{},
+	}
+	includeContent := q.IncludeContent != nil && *q.IncludeContent
+	for _, st := range page {
+		item := &aspect.AspectListItemRT{
+			ID:          st.id,
+			Entity:      st.entity,
+			Schema:      st.schema,
+			ContentType: st.contentType,
+			ValidFrom:   timePtr(st.validFrom),
+			ValidTo:     timePtr(st.validTo),
+		}
+		if includeContent {
+			item.Content = st.content
+		}
+		res.Items = append(res.Items, item)
+	}
+	for _, l := range links {
+		res.Links = append(res.Links, &aspect.LinkT{Rel: l[0], Type: "application/json", Href: l[1]})
+	}
+	return res, nil
+}
+
+// query returns the query and offset of a list request, restoring them from
+// the page token if one is given.
+func (s *AspectService) query(p *aspect.ListPayload) (aspect.ListPayload, int, error) {
+	if p.Page != nil {
+		q, offset, err := decodeCursor[aspect.ListPayload](*p.Page)
+		q.Limit = p.Limit
+		return q, offset, err
+	}
+	q := *p
+	q.JWT = ""
+	if q.AtTime == nil {
+		q.AtTime = timePtr(s.d.now())
+	}
+	return q, 0, nil
+}
+
+// Create implements aspect.Service. If the payload names an aspect it
+// replaces, that aspect is retracted.
+func (s *AspectService) Create(ctx context.Context, p *aspect.CreatePayload) (*aspect.AspectIDRT, error) {
+	if err := checkJSONContentType(p.ContentType); err != nil {
+		return nil, &aspect.UnsupportedContentTypeT{Message: err.Error()}
+	}
+	user := User(ctx)
+	now := s.d.now()
+	var replaces string
+	if p.Replaces != nil && *p.Replaces != "" {
+		replaces = *p.Replaces
+		old, ok := s.store.get(replaces)
+		if !ok {
+			return nil, &aspect.ResourceNotFoundT{ID: replaces, Message: "replaced aspect not found"}
+		}
+		if old.entity != p.Entity || old.schema != p.Schema {
+			return nil, &aspect.BadRequestT{Message: "replaced aspect is about a different entity or schema"}
+		}
+		s.store.retract(replaces, user, now)
+	}
+	return s.add(ctx, p.Entity, p.Schema, p.Content, p.ContentType, p.Policy, replaces, now), nil
+}
+
+// Update implements aspect.Service. It retracts the active aspect of the
+// entity with the same schema, if any, and records the new content.
+func (s *AspectService) Update(ctx context.Context, p *aspect.UpdatePayload) (*aspect.AspectIDRT, error) {
+	if err := checkJSONContentType(p.ContentType); err != nil {
+		return nil, &aspect.UnsupportedContentTypeT{Message: err.Error()}
+	}
+	active := s.store.active(p.Entity, p.Schema)
+	if len(active) > 1 {
+		return nil, &aspect.NotUniqueResourceT{Message: "more than one active aspect for entity and schema"}
+	}
+	now := s.d.now()
+	var replaces string
+	if len(active) == 1 {
+		replaces = active[0]
+		s.store.retract(replaces, User(ctx), now)
+	}
+	return s.add(ctx, p.Entity, p.Schema, p.Content, p.ContentType, p.Policy, replaces, now), nil
+}
+
+func (s *AspectService) add(ctx context.Context, entity, schema string, content any, contentType string, policy *string, replaces string, now time.Time) *aspect.AspectIDRT {
+	user := User(ctx)
+	st := statement{
+		id:          newURN("aspect"),
+		entity:      entity,
+		schema:      schema,
+		content:     normalizeJSON(content),
+		contentType: contentType,
+		validFrom:   now,
+		asserter:    user,
+		replaces:    replaces,
+		account:     Account(user),
+		policy:      stringOr(policy, defaultPolicy),
+	}
+	if st.contentType == "" {
+		st.contentType = "application/json"
+	}
+	s.store.add(st)
+	return &aspect.AspectIDRT{ID: st.id}
+}
+
+// Retract implements aspect.Service.
+func (s *AspectService) Retract(ctx context.Context, p *aspect.RetractPayload) error {
+	if _, ok := s.store.get(p.ID); !ok {
+		return &aspect.ResourceNotFoundT{ID: p.ID, Message: "aspect not found"}
+	}
+	s.store.retract(p.ID, User(ctx), s.d.now())
+	return nil
+}
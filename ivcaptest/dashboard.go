@@ -0,0 +1,53 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcaptest
+
+import (
+	"context"
+	"sync"
+
+	dashboard "github.com/ivcap-works/ivcap-core-api/gen/dashboard"
+	"goa.design/goa/v3/security"
+)
+
+// DashboardService is an in-memory fake of the dashboard service. It returns
+// the dashboards set with SetDashboards.
+type DashboardService struct {
+	d     *Deployment
+	mu    sync.Mutex
+	items []*dashboard.DashboardListItem
+}
+
+var _ dashboard.Service = (*DashboardService)(nil)
+var _ dashboard.Auther = (*DashboardService)(nil)
+
+// JWTAuth implements dashboard.Auther.
+func (s *DashboardService) JWTAuth(ctx context.Context, token string, scheme *security.JWTScheme) (context.Context, error) {
+	return s.d.authorize(ctx, token, scheme, &dashboard.UnauthorizedT{})
+}
+
+// List implements dashboard.Service.
+func (s *DashboardService) List(ctx context.Context, p *dashboard.ListPayload) (*dashboard.DashboardListRT, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return &dashboard.DashboardListRT{Items: append([]*dashboard.DashboardListItem{}, s.items...)}, nil
+}
+
+// SetDashboards sets the dashboards returned by List.
+func (s *DashboardService) SetDashboards(items ...*dashboard.DashboardListItem) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.items = items
+}
@@ -0,0 +1,258 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ivcaptest provides stateful in-memory fakes of the IVCAP services.
+// Each fake implements the Service and Auther interfaces of its gen package
+// and can be wrapped with the package's NewEndpoints:
+//
+//	d := ivcaptest.New()
+//	endpoints := artifact.NewEndpoints(d.Artifacts)
+//
+// The fakes of a Deployment share a clock, the authorization logic and the
+// base URL of the links they return.
+package ivcaptest
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"goa.design/goa/v3/security"
+)
+
+// DefaultUser is the caller of requests whose token does not name one.
+const DefaultUser = "urn:ivcap:user:test"
+
+// Deployment holds the fakes of all the services of an IVCAP deployment.
+type Deployment struct {
+	// Now returns the current time. Tests may replace it to control the
+	// validity periods used by at-time queries.
+	Now func() time.Time
+	// BaseURL is the URL the links returned by the fakes point to. It
+	// defaults to "", which makes them relative to the server the fakes are
+	// mounted on, such as "/1/artifacts/<id>/blob".
+	BaseURL string
+	// Authorize returns the user making a request with token, or an error if
+	// the request is not authorized. It defaults to accepting any token, with
+	// the user taken from the token's "sub" claim.
+	Authorize func(ctx context.Context, token string, scheme *security.JWTScheme) (user string, err error)
+	// OpenAPISpec is the OpenAPI document served at
+	// /1/openapi/openapi3.json, such as the openapi3.json of this module.
+	// It defaults to a document listing the operations the fakes mount. It
+	// must be set before calling Handler.
+	OpenAPISpec []byte
+
+	Artifacts  *ArtifactService
+	Aspects    *AspectService
+	Dashboards *DashboardService
+	Metadata   *MetadataService
+	Orders     *OrderService
+	Packages   *PackageService
+	Projects   *ProjectService
+	Queues     *QueueService
+	Search     *SearchService
+	Secrets    *SecretService
+	Services   *ServiceService
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+// New returns a deployment with empty fakes of all services.
+func New() *Deployment {
+	d := &Deployment{
+		Now:       time.Now,
+		Authorize: authorizeAny,
+	}
+	d.Artifacts = &ArtifactService{d: d, items: map[string]*artifactRecord{}}
+	d.Aspects = &AspectService{d: d, store: newStatementStore()}
+	d.Dashboards = &DashboardService{d: d}
+	d.Metadata = &MetadataService{d: d, store: newStatementStore()}
+	d.Orders = &OrderService{d: d, items: map[string]*orderRecord{}}
+	d.Packages = &PackageService{d: d, tags: map[string]*imageRecord{}, blobs: map[string][]byte{}, sizes: map[string]int{}}
+	d.Projects = &ProjectService{d: d, items: map[string]*projectRecord{}, defaults: map[string]string{}}
+	d.Queues = &QueueService{d: d, items: map[string]*queueRecord{}}
+	d.Search = &SearchService{d: d}
+	d.Secrets = &SecretService{d: d, items: map[string]*secretRecord{}}
+	d.Services = &ServiceService{d: d, items: map[string]*serviceRecord{}}
+	return d
+}
+
+// Token returns an unsigned JWT naming user in its "sub" claim, as understood
+// by the default Authorize function.
+func Token(user string) string {
+	enc := base64.RawURLEncoding
+	claims, _ := json.Marshal(map[string]any{"sub": user})
+	return enc.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`)) + "." + enc.EncodeToString(claims) + "."
+}
+
+// authorizeAny accepts any token and returns the user named in its "sub"
+// claim, or DefaultUser.
+func authorizeAny(_ context.Context, token string, _ *security.JWTScheme) (string, error) {
+	parts := strings.Split(strings.TrimPrefix(token, "Bearer "), ".")
+	if len(parts) == 3 {
+		if b, err := base64.RawURLEncoding.DecodeString(parts[1]); err == nil {
+			var claims struct {
+				Sub string `json:"sub"`
+			}
+			if json.Unmarshal(b, &claims) == nil && claims.Sub != "" {
+				return claims.Sub, nil
+			}
+		}
+	}
+	return DefaultUser, nil
+}
+
+type userKey struct{}
+
+// authorize runs the Authorize function of the deployment and records the
+// user in the returned context. unauthorized is returned if it fails.
+func (d *Deployment) authorize(ctx context.Context, token string, scheme *security.JWTScheme, unauthorized error) (context.Context, error) {
+	user, err := d.Authorize(ctx, token, scheme)
+	if err != nil {
+		return ctx, unauthorized
+	}
+	return context.WithValue(ctx, userKey{}, user), nil
+}
+
+// User returns the user making the request handled with ctx.
+func User(ctx context.Context) string {
+	if u, ok := ctx.Value(userKey{}).(string); ok {
+		return u
+	}
+	return DefaultUser
+}
+
+// Account returns the billing account of user.
+func Account(user string) string {
+	return "urn:ivcap:account:" + strings.TrimPrefix(user, "urn:ivcap:user:")
+}
+
+// now returns the current time of the deployment. Successive calls return
+// strictly increasing times, so that records created one after the other
+// have distinct validity periods even with a frozen clock.
+func (d *Deployment) now() time.Time {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	t := d.Now().UTC()
+	if !t.After(d.last) {
+		t = d.last.Add(time.Microsecond)
+	}
+	d.last = t
+	return t
+}
+
+// atTime parses the optional at-time parameter, defaulting to now.
+func (d *Deployment) atTime(s *string) (time.Time, error) {
+	if s == nil || *s == "" {
+		return d.now(), nil
+	}
+	return time.Parse(time.RFC3339, *s)
+}
+
+// link builds a link into the deployment.
+func (d *Deployment) link(path string, query url.Values) string {
+	u := strings.TrimSuffix(d.BaseURL, "/") + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	return u
+}
+
+// newURN returns a fresh URN of the given kind, such as
+// "urn:ivcap:aspect:<uuid>".
+func newURN(kind string) string {
+	return "urn:ivcap:" + kind + ":" + uuid.NewString()
+}
+
+// newUUIDURN returns a fresh "urn:uuid:<uuid>" URN. It is used for the
+// resources whose IDs the generated clients validate both as URI and as UUID.
+func newUUIDURN() string {
+	return uuid.New().URN()
+}
+
+// formatTime formats t as expected by the date-time fields of the API.
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// timePtr formats t, returning nil for the zero time.
+func timePtr(t time.Time) *string {
+	if t.IsZero() {
+		return nil
+	}
+	s := formatTime(t)
+	return &s
+}
+
+// validAt reports whether a record valid from from until to (or forever, if
+// to is zero) is valid at t.
+func validAt(t, from, to time.Time) bool {
+	return !t.Before(from) && (to.IsZero() || t.Before(to))
+}
+
+// cursor is the state encoded in the page tokens of list methods. The query
+// is saved with the token, as the services ignore all parameters but the
+// limit when a page token is given.
+type cursor[Q any] struct {
+	Query  Q   `json:"q"`
+	Offset int `json:"o"`
+}
+
+func encodeCursor[Q any](q Q, offset int) string {
+	b, _ := json.Marshal(cursor[Q]{Query: q, Offset: offset})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor[Q any](token string) (q Q, offset int, err error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return q, 0, fmt.Errorf("invalid page token: %w", err)
+	}
+	var c cursor[Q]
+	if err := json.Unmarshal(b, &c); err != nil {
+		return q, 0, fmt.Errorf("invalid page token: %w", err)
+	}
+	return c.Query, c.Offset, nil
+}
+
+// pageLinks lists the relation and href of the links returned with a page.
+type pageLinks [][2]string
+
+// paginate returns the limit items of all starting at offset together with
+// the links of the page. next builds the href of the page starting at the
+// given offset.
+func paginate[T any](all []T, offset, limit int, next func(offset int) string) ([]T, pageLinks) {
+	if limit < 1 {
+		limit = 10
+	}
+	if offset > len(all) {
+		offset = len(all)
+	}
+	end := offset + limit
+	if end > len(all) {
+		end = len(all)
+	}
+	links := pageLinks{{"self", next(offset)}, {"first", next(0)}}
+	if end < len(all) {
+		links = append(links, [2]string{"next", next(end)})
+	}
+	return all[offset:end], links
+}
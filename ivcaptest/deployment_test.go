@@ -0,0 +1,340 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcaptest_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	artifact "github.com/ivcap-works/ivcap-core-api/gen/artifact"
+	aspect "github.com/ivcap-works/ivcap-core-api/gen/aspect"
+	project "github.com/ivcap-works/ivcap-core-api/gen/project"
+	"github.com/ivcap-works/ivcap-core-api/ivcap"
+	"github.com/ivcap-works/ivcap-core-api/ivcaperr"
+	"github.com/ivcap-works/ivcap-core-api/ivcaptest"
+	"goa.design/goa/v3/security"
+)
+
+// newClient returns a client for user talking to d, set up as shown in the
+// README.
+func newClient(t *testing.T, d *ivcaptest.Deployment, user string) *ivcap.Client {
+	t.Helper()
+	srv := httptest.NewServer(d.Handler())
+	t.Cleanup(srv.Close)
+	c, err := ivcap.New(srv.URL, ivcap.WithJWT(ivcaptest.Token(user)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestArtifactLinks(t *testing.T) {
+	content := strings.Repeat("0123456789", 100)
+	tests := []struct {
+		name   string
+		upload func(ctx context.Context, c *ivcap.Client) (string, error)
+	}{
+		{
+			name: "upload",
+			upload: func(ctx context.Context, c *ivcap.Client) (string, error) {
+				res, err := c.Artifacts.Upload(ctx, &artifact.UploadPayload{}, strings.NewReader(content))
+				if err != nil {
+					return "", err
+				}
+				return res.ID, nil
+			},
+		},
+		{
+			name: "resumable",
+			upload: func(ctx context.Context, c *ivcap.Client) (string, error) {
+				res, err := c.Artifacts.UploadResumable(ctx, &artifact.UploadPayload{}, strings.NewReader(content), int64(len(content)), &ivcap.ResumableUpload{ChunkSize: 256})
+				if err != nil {
+					return "", err
+				}
+				return res.ID, nil
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newClient(t, ivcaptest.New(), "urn:ivcap:user:alice")
+			ctx := context.Background()
+			id, err := tt.upload(ctx, c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var buf bytes.Buffer
+			if _, err := c.Artifacts.Download(ctx, id, &buf, nil); err != nil {
+				t.Fatalf("Download: %v", err)
+			}
+			if buf.String() != content {
+				t.Errorf("Download returned %q, want %q", buf.String(), content)
+			}
+			r, _, err := c.Artifacts.Open(ctx, id)
+			if err != nil {
+				t.Fatalf("Open: %v", err)
+			}
+			defer r.Close()
+			b, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != content {
+				t.Errorf("Open returned %q, want %q", b, content)
+			}
+		})
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	const user = "urn:ivcap:user:alice"
+	tests := []struct {
+		name      string
+		authorize func(ctx context.Context, token string, scheme *security.JWTScheme) (string, error)
+		// account is the account of the artifacts uploaded, or "" if the
+		// upload is not authorized.
+		account string
+	}{
+		{name: "token user", account: ivcaptest.Account(user)},
+		{
+			name: "custom",
+			authorize: func(context.Context, string, *security.JWTScheme) (string, error) {
+				return "urn:ivcap:user:bob", nil
+			},
+			account: ivcaptest.Account("urn:ivcap:user:bob"),
+		},
+		{
+			name: "rejected",
+			authorize: func(context.Context, string, *security.JWTScheme) (string, error) {
+				return "", errors.New("invalid token")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := ivcaptest.New()
+			if tt.authorize != nil {
+				d.Authorize = tt.authorize
+			}
+			c := newClient(t, d, user)
+			ctx := context.Background()
+			up, err := c.Artifacts.Upload(ctx, &artifact.UploadPayload{}, strings.NewReader("content"))
+			if tt.account == "" {
+				if !errors.Is(err, ivcaperr.ErrUnauthorized) {
+					t.Errorf("error %v, want %v", err, ivcaperr.ErrUnauthorized)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			st, err := c.Artifacts.Read(ctx, up.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := *st.Account; got != tt.account {
+				t.Errorf("account %s, want %s", got, tt.account)
+			}
+		})
+	}
+}
+
+func TestArtifactList(t *testing.T) {
+	const artifacts = 7
+	tests := []struct {
+		name   string
+		limit  int
+		filter string
+		want   int
+	}{
+		{name: "one per page", limit: 1, want: artifacts},
+		{name: "partial last page", limit: 3, want: artifacts},
+		{name: "single page", limit: 50, want: artifacts},
+		{name: "filtered", limit: 2, filter: "collection = 'even'", want: 4},
+		{name: "contains", filter: "name ~= '1'", want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newClient(t, ivcaptest.New(), "urn:ivcap:user:alice")
+			ctx := context.Background()
+			for i := 0; i < artifacts; i++ {
+				name, coll := fmt.Sprintf("artifact-%d", i), "odd"
+				if i%2 == 0 {
+					coll = "even"
+				}
+				if _, err := c.Artifacts.Upload(ctx, &artifact.UploadPayload{Name: &name, Collection: &coll}, strings.NewReader("content")); err != nil {
+					t.Fatal(err)
+				}
+			}
+			p := &artifact.ListPayload{Limit: tt.limit}
+			if tt.filter != "" {
+				p.Filter = &tt.filter
+			}
+			all, err := c.Artifacts.ListIter(ctx, p).Collect()
+			if err != nil {
+				t.Fatal(err)
+			}
+			seen := map[string]bool{}
+			for _, a := range all {
+				seen[a.ID] = true
+			}
+			if len(all) != tt.want || len(seen) != tt.want {
+				t.Errorf("listed %d artifacts, %d distinct, want %d", len(all), len(seen), tt.want)
+			}
+		})
+	}
+}
+
+func TestAspectAtTime(t *testing.T) {
+	const entity, schema = "urn:ivcap:entity:test", "urn:ivcap:schema:test.1"
+	d := ivcaptest.New()
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := t0
+	d.Now = func() time.Time { return now }
+	c := newClient(t, d, "urn:ivcap:user:alice")
+	ctx := context.Background()
+	if _, err := c.Aspects.Create(ctx, &aspect.CreatePayload{Entity: entity, Schema: schema, Content: map[string]any{"v": 1}, ContentType: "application/json"}); err != nil {
+		t.Fatal(err)
+	}
+	now = t0.Add(time.Hour)
+	if _, err := c.Aspects.Update(ctx, &aspect.UpdatePayload{Entity: entity, Schema: schema, Content: map[string]any{"v": 2}, ContentType: "application/json"}); err != nil {
+		t.Fatal(err)
+	}
+	now = t0.Add(2 * time.Hour)
+
+	tests := []struct {
+		name string
+		at   time.Time
+		// want is the content valid at the time, or 0 for none.
+		want float64
+	}{
+		{name: "before", at: t0.Add(-time.Minute)},
+		{name: "created", at: t0.Add(time.Minute), want: 1},
+		{name: "replaced", at: t0.Add(time.Hour + time.Minute), want: 2},
+		{name: "now", want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			include := true
+			p := &aspect.ListPayload{Entity: ptr(entity), Schema: ptr(schema), IncludeContent: &include}
+			if !tt.at.IsZero() {
+				p.AtTime = ptr(tt.at.Format(time.RFC3339))
+			}
+			all, err := c.Aspects.ListIter(ctx, p).Collect()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []float64
+			for _, a := range all {
+				got = append(got, a.Content.(map[string]any)["v"].(float64))
+			}
+			if tt.want == 0 && len(got) != 0 || tt.want != 0 && (len(got) != 1 || got[0] != tt.want) {
+				t.Errorf("content %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProjectMembership(t *testing.T) {
+	const owner, member, stranger = "urn:ivcap:user:alice", "urn:ivcap:user:bob", "urn:ivcap:user:mallory"
+	tests := []struct {
+		name string
+		user string
+		call func(ctx context.Context, c *ivcap.Client, urn string) error
+		want error
+	}{
+		{
+			name: "member reads",
+			user: member,
+			call: func(ctx context.Context, c *ivcap.Client, urn string) error {
+				_, err := c.Projects.Read(ctx, urn)
+				return err
+			},
+		},
+		{
+			name: "stranger reads",
+			user: stranger,
+			call: func(ctx context.Context, c *ivcap.Client, urn string) error {
+				_, err := c.Projects.Read(ctx, urn)
+				return err
+			},
+			want: ivcaperr.ErrForbidden,
+		},
+		{
+			name: "unknown project",
+			user: owner,
+			call: func(ctx context.Context, c *ivcap.Client, urn string) error {
+				_, err := c.Projects.Read(ctx, "urn:ivcap:project:unknown")
+				return err
+			},
+			want: ivcaperr.ErrNotFound,
+		},
+		{
+			name: "owner adds",
+			user: owner,
+			call: func(ctx context.Context, c *ivcap.Client, urn string) error {
+				return c.Projects.UpdateMembership(ctx, urn, stranger, ivcaptest.RoleMember)
+			},
+		},
+		{
+			name: "member adds",
+			user: member,
+			call: func(ctx context.Context, c *ivcap.Client, urn string) error {
+				return c.Projects.UpdateMembership(ctx, urn, stranger, ivcaptest.RoleMember)
+			},
+			want: ivcaperr.ErrForbidden,
+		},
+		{
+			name: "last owner removed",
+			user: owner,
+			call: func(ctx context.Context, c *ivcap.Client, urn string) error {
+				return c.Projects.RemoveMembership(ctx, urn, owner)
+			},
+			want: ivcaperr.ErrBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := ivcaptest.New()
+			ctx := context.Background()
+			oc := newClient(t, d, owner)
+			p, err := oc.Projects.CreateProject(ctx, &project.ProjectCreateRequest{Name: "test"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := oc.Projects.UpdateMembership(ctx, p.Urn, member, ivcaptest.RoleMember); err != nil {
+				t.Fatal(err)
+			}
+			err = tt.call(ctx, newClient(t, d, tt.user), p.Urn)
+			switch {
+			case tt.want == nil && err != nil:
+				t.Fatal(err)
+			case tt.want != nil && !errors.Is(err, tt.want):
+				t.Errorf("error %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
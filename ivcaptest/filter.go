@@ -0,0 +1,123 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcaptest
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// fields returns the value of the named attribute of a record, and whether
+// the record has such an attribute.
+type fields[T any] func(r T, name string) (string, bool)
+
+// clauseRE matches a single filter clause such as "name = 'foo'".
+var clauseRE = regexp.MustCompile(`^\s*([\w.-]+)\s*(==|=|!=|~=)\s*(?:'([^']*)'|"([^"]*)"|(\S+))\s*$`)
+
+// filterClause is a single comparison of a filter expression.
+type filterClause struct {
+	name, op, value string
+}
+
+// parseFilter parses a filter expression made of comparisons joined by
+// "and". Comparisons use =, != or ~= (contains) and quoted or bare values.
+func parseFilter(expr string) ([]filterClause, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, nil
+	}
+	var clauses []filterClause
+	for _, c := range regexp.MustCompile(`(?i)\s+and\s+`).Split(expr, -1) {
+		m := clauseRE.FindStringSubmatch(c)
+		if m == nil {
+			return nil, fmt.Errorf("unsupported filter clause %q", c)
+		}
+		clauses = append(clauses, filterClause{name: m[1], op: m[2], value: m[3] + m[4] + m[5]})
+	}
+	return clauses, nil
+}
+
+// match reports whether the record r described by f satisfies all clauses.
+func match[T any](clauses []filterClause, r T, f fields[T]) bool {
+	for _, c := range clauses {
+		v, ok := f(r, c.name)
+		switch c.op {
+		case "=", "==":
+			if !ok || v != c.value {
+				return false
+			}
+		case "!=":
+			if ok && v == c.value {
+				return false
+			}
+		case "~=":
+			if !ok || !strings.Contains(v, c.value) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// filterRecords returns the records satisfying the filter expression.
+func filterRecords[T any](records []T, expr *string, f fields[T]) ([]T, error) {
+	if expr == nil {
+		return records, nil
+	}
+	clauses, err := parseFilter(*expr)
+	if err != nil {
+		return nil, err
+	}
+	var res []T
+	for _, r := range records {
+		if match(clauses, r, f) {
+			res = append(res, r)
+		}
+	}
+	return res, nil
+}
+
+// sortRecords sorts records by the attribute orderBy, if set, keeping the
+// creation order for equal values.
+func sortRecords[T any](records []T, orderBy *string, desc bool, f fields[T]) {
+	if orderBy == nil || *orderBy == "" {
+		if desc {
+			for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+				records[i], records[j] = records[j], records[i]
+			}
+		}
+		return
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		a, _ := f(records[i], *orderBy)
+		b, _ := f(records[j], *orderBy)
+		if desc {
+			return a > b
+		}
+		return a < b
+	})
+}
+
+// likePattern reports whether s matches the SQL LIKE style pattern, using '%'
+// as wildcard.
+func likePattern(pattern, s string) bool {
+	if !strings.Contains(pattern, "%") {
+		return s == pattern
+	}
+	re := "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), "%", ".*") + "$"
+	ok, _ := regexp.MatchString(re, s)
+	return ok
+}
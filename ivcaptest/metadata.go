@@ -0,0 +1,200 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcaptest
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+
+	metadata "github.com/ivcap-works/ivcap-core-api/gen/metadata"
+	"goa.design/goa/v3/security"
+)
+
+// MetadataService is an in-memory fake of the metadata service.
+type MetadataService struct {
+	d     *Deployment
+	store *statementStore
+}
+
+var _ metadata.Service = (*MetadataService)(nil)
+var _ metadata.Auther = (*MetadataService)(nil)
+
+// JWTAuth implements metadata.Auther.
+func (s *MetadataService) JWTAuth(ctx context.Context, token string, scheme *security.JWTScheme) (context.Context, error) {
+	return s.d.authorize(ctx, token, scheme, &metadata.UnauthorizedT{})
+}
+
+// Read implements metadata.Service.
+func (s *MetadataService) Read(ctx context.Context, p *metadata.ReadPayload) (*metadata.MetadataRecordRT, error) {
+	st, ok := s.store.get(p.ID)
+	if !ok {
+		return nil, &metadata.ResourceNotFoundT{ID: p.ID, Message: "metadata record not found"}
+	}
+	res := &metadata.MetadataRecordRT{
+		ID:        st.id,
+		Entity:    st.entity,
+		Schema:    st.schema,
+		Aspect:    st.content,
+		ValidFrom: formatTime(st.validFrom),
+		ValidTo:   timePtr(st.validTo),
+		Asserter:  st.asserter,
+		Links: []*metadata.LinkT{
+			{Rel: "self", Type: "application/json", Href: s.d.link("/1/metadata/"+url.PathEscape(st.id), nil)},
+		},
+	}
+	if st.retracter != "" {
+		res.Revoker = &st.retracter
+	}
+	return res, nil
+}
+
+// List implements metadata.Service.
+func (s *MetadataService) List(ctx context.Context, p *metadata.ListPayload) (*metadata.ListMetaRT, error) {
+	q, offset, err := s.query(p)
+	if err != nil {
+		return nil, &metadata.BadRequestT{Message: err.Error()}
+	}
+	at, err := s.d.atTime(q.AtTime)
+	if err != nil {
+		return nil, &metadata.InvalidParameterT{Name: "at-time", Message: err.Error(), Value: q.AtTime}
+	}
+	all := s.store.query(at, q.EntityID, q.Schema)
+	contexts := map[string]*string{}
+	if q.AspectPath != nil && *q.AspectPath != "" {
+		var matched []*statement
+		for _, st := range all {
+			if v, ok := st.contentPath(*q.AspectPath); ok {
+				b, _ := json.Marshal(v)
+				c := string(b)
+				contexts[st.id] = &c
+				matched = append(matched, st)
+			}
+		}
+		all = matched
+	}
+	all, err = filterRecords(all, &q.Filter, (*statement).fields)
+	if err != nil {
+		return nil, &metadata.InvalidParameterT{Name: "filter", Message: err.Error(), Value: &q.Filter}
+	}
+	sortRecords(all, &q.OrderBy, q.OrderDesc != nil && *q.OrderDesc, (*statement).fields)
+	page, links := paginate(all, offset, q.Limit, func(o int) string {
+		return s.d.link("/1/metadata", url.Values{"limit": {strconv.Itoa(q.Limit)}, "page": {encodeCursor(q, o)}})
+	})
+	res := &metadata.ListMetaRT{
+		Entity:     q.EntityID,
+		Schema:     q.Schema,
+		AspectPath: q.AspectPath,
+		AtTime:     timePtr(at),
+		Items:      []*metadata.MetadataListItemRT{},
+	}
+	for _, st := range page {
+		item := &metadata.MetadataListItemRT{
+			ID:            st.id,
+			Entity:        st.entity,
+			Schema:        st.schema,
+			AspectContext: contexts[st.id],
+		}
+		if q.IncludeContent {
+			item.Aspect = st.content
+		}
+		res.Items = append(res.Items, item)
+	}
+	for _, l := range links {
+		res.Links = append(res.Links, &metadata.LinkT{Rel: l[0], Type: "application/json", Href: l[1]})
+	}
+	return res, nil
+}
+
+// query returns the query and offset of a list request, restoring them from
+// the page token if one is given.
+func (s *MetadataService) query(p *metadata.ListPayload) (metadata.ListPayload, int, error) {
+	if p.Page != nil {
+		q, offset, err := decodeCursor[metadata.ListPayload](*p.Page)
+		q.Limit = p.Limit
+		return q, offset, err
+	}
+	q := *p
+	q.JWT = ""
+	if q.AtTime == nil {
+		q.AtTime = timePtr(s.d.now())
+	}
+	return q, 0, nil
+}
+
+// Add implements metadata.Service.
+func (s *MetadataService) Add(ctx context.Context, p *metadata.AddPayload) (*metadata.AddMetaRT, error) {
+	if err := checkJSONContentType(p.ContentType); err != nil {
+		return nil, &metadata.BadRequestT{Message: err.Error()}
+	}
+	return s.add(ctx, p.EntityID, p.Schema, p.Aspect, p.PolicyID, ""), nil
+}
+
+// UpdateRecord implements metadata.Service. It revokes the record and adds a
+// new one, taking the attributes not set in the payload from the old record.
+func (s *MetadataService) UpdateRecord(ctx context.Context, p *metadata.UpdateRecordPayload) (*metadata.AddMetaRT, error) {
+	old, ok := s.store.get(p.ID)
+	if !ok {
+		return nil, &metadata.ResourceNotFoundT{ID: p.ID, Message: "metadata record not found"}
+	}
+	if p.ContentType != nil {
+		if err := checkJSONContentType(*p.ContentType); err != nil {
+			return nil, &metadata.BadRequestT{Message: err.Error()}
+		}
+	}
+	if !s.store.retract(p.ID, User(ctx), s.d.now()) {
+		return nil, &metadata.BadRequestT{Message: "metadata record is already revoked"}
+	}
+	entity, schema, policy := old.entity, old.schema, &old.policy
+	if p.EntityID != nil {
+		entity = *p.EntityID
+	}
+	if p.Schema != nil {
+		schema = *p.Schema
+	}
+	if p.PolicyID != nil {
+		policy = p.PolicyID
+	}
+	return s.add(ctx, entity, schema, p.Aspect, policy, old.id), nil
+}
+
+func (s *MetadataService) add(ctx context.Context, entity, schema string, content any, policy *string, replaces string) *metadata.AddMetaRT {
+	user := User(ctx)
+	st := statement{
+		id:          newUUIDURN(),
+		entity:      entity,
+		schema:      schema,
+		content:     normalizeJSON(content),
+		contentType: "application/json",
+		validFrom:   s.d.now(),
+		asserter:    user,
+		replaces:    replaces,
+		account:     Account(user),
+		policy:      stringOr(policy, defaultPolicy),
+	}
+	s.store.add(st)
+	return &metadata.AddMetaRT{RecordID: st.id}
+}
+
+// Revoke implements metadata.Service.
+func (s *MetadataService) Revoke(ctx context.Context, p *metadata.RevokePayload) error {
+	id := stringOr(p.ID, "")
+	if _, ok := s.store.get(id); !ok {
+		return &metadata.ResourceNotFoundT{ID: id, Message: "metadata record not found"}
+	}
+	s.store.retract(id, User(ctx), s.d.now())
+	return nil
+}
@@ -0,0 +1,404 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcaptest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	order "github.com/ivcap-works/ivcap-core-api/gen/order"
+	"goa.design/goa/v3/security"
+)
+
+// OrderService is an in-memory fake of the order service. Orders are created
+// in "pending" state and only move on when a test calls SetStatus.
+type OrderService struct {
+	d     *Deployment
+	mu    sync.Mutex
+	items map[string]*orderRecord
+	order []*orderRecord
+}
+
+type orderRecord struct {
+	id         string
+	req        order.OrderRequestT
+	status     string
+	account    string
+	orderedAt  time.Time
+	startedAt  time.Time
+	finishedAt time.Time
+	products   []string
+	logs       []logLine
+	top        order.OrderTopResultItemCollection
+}
+
+type logLine struct {
+	at   time.Time
+	text string
+}
+
+var _ order.Service = (*OrderService)(nil)
+var _ order.Auther = (*OrderService)(nil)
+
+// JWTAuth implements order.Auther.
+func (s *OrderService) JWTAuth(ctx context.Context, token string, scheme *security.JWTScheme) (context.Context, error) {
+	return s.d.authorize(ctx, token, scheme, &order.UnauthorizedT{})
+}
+
+// List implements order.Service.
+func (s *OrderService) List(ctx context.Context, p *order.ListPayload) (*order.OrderListRT, error) {
+	q, offset, err := s.query(p)
+	if err != nil {
+		return nil, &order.BadRequestT{Message: err.Error()}
+	}
+	at, err := s.d.atTime(q.AtTime)
+	if err != nil {
+		return nil, &order.InvalidParameterT{Name: "at-time", Message: err.Error(), Value: q.AtTime}
+	}
+	s.mu.Lock()
+	var all []*orderRecord
+	for _, r := range s.order {
+		if !r.orderedAt.After(at) {
+			c := *r
+			all = append(all, &c)
+		}
+	}
+	s.mu.Unlock()
+	all, err = filterRecords(all, q.Filter, (*orderRecord).fields)
+	if err != nil {
+		return nil, &order.InvalidParameterT{Name: "filter", Message: err.Error(), Value: q.Filter}
+	}
+	sortRecords(all, q.OrderBy, q.OrderDesc, (*orderRecord).fields)
+	page, links := paginate(all, offset, q.Limit, func(o int) string {
+		return s.d.link("/1/orders", url.Values{"limit": {strconv.Itoa(q.Limit)}, "page": {encodeCursor(q, o)}})
+	})
+	res := &order.OrderListRT{AtTime: formatTime(at), Items: []*order.OrderListItem{}}
+	for _, r := range page {
+		res.Items = append(res.Items, &order.OrderListItem{
+			ID:         r.id,
+			Name:       r.req.Name,
+			Status:     r.status,
+			OrderedAt:  timePtr(r.orderedAt),
+			StartedAt:  timePtr(r.startedAt),
+			FinishedAt: timePtr(r.finishedAt),
+			Service:    r.req.Service,
+			Account:    r.account,
+			Href:       s.d.link("/1/orders/"+url.PathEscape(r.id), nil),
+		})
+	}
+	for _, l := range links {
+		res.Links = append(res.Links, &order.LinkT{Rel: l[0], Type: "application/json", Href: l[1]})
+	}
+	return res, nil
+}
+
+// query returns the query and offset of a list request, restoring them from
+// the page token if one is given.
+func (s *OrderService) query(p *order.ListPayload) (order.ListPayload, int, error) {
+	if p.Page != nil {
+		q, offset, err := decodeCursor[order.ListPayload](*p.Page)
+		q.Limit = p.Limit
+		return q, offset, err
+	}
+	q := *p
+	q.JWT = ""
+	if q.AtTime == nil {
+		q.AtTime = timePtr(s.d.now())
+	}
+	return q, 0, nil
+}
+
+// Read implements order.Service.
+func (s *OrderService) Read(ctx context.Context, p *order.ReadPayload) (*order.OrderStatusRT, error) {
+	s.mu.Lock()
+	r, ok := s.items[p.ID]
+	var c orderRecord
+	if ok {
+		c = *r
+	}
+	s.mu.Unlock()
+	if !ok {
+		return nil, &order.ResourceNotFoundT{ID: p.ID, Message: "order not found"}
+	}
+	return s.status(ctx, &c), nil
+}
+
+// Products implements order.Service.
+func (s *OrderService) Products(ctx context.Context, p *order.ProductsPayload) (*order.PartialProductListT, error) {
+	q, offset := *p, 0
+	q.JWT = ""
+	if p.Page != nil {
+		var err error
+		if q, offset, err = decodeCursor[order.ProductsPayload](*p.Page); err != nil {
+			return nil, &order.BadRequestT{Message: err.Error()}
+		}
+		q.Limit = p.Limit
+	}
+	s.mu.Lock()
+	r, ok := s.items[q.OrderID]
+	var ids []string
+	if ok {
+		ids = append(ids, r.products...)
+	}
+	s.mu.Unlock()
+	if !ok {
+		return nil, &order.ResourceNotFoundT{ID: q.OrderID, Message: "order not found"}
+	}
+	return s.products(ctx, ids, q, offset), nil
+}
+
+func (s *OrderService) products(ctx context.Context, ids []string, q order.ProductsPayload, offset int) *order.PartialProductListT {
+	q.Page = nil
+	var all []*artifactRecord
+	for _, id := range ids {
+		s.d.Artifacts.mu.Lock()
+		if a, ok := s.d.Artifacts.items[id]; ok {
+			c := *a
+			all = append(all, &c)
+		}
+		s.d.Artifacts.mu.Unlock()
+	}
+	sortRecords(all, q.OrderBy, q.OrderDesc, (*artifactRecord).fields)
+	path := "/1/orders/" + url.PathEscape(q.OrderID) + "/products"
+	page, links := paginate(all, offset, q.Limit, func(o int) string {
+		return s.d.link(path, url.Values{"limit": {strconv.Itoa(q.Limit)}, "page": {encodeCursor(q, o)}})
+	})
+	res := &order.PartialProductListT{Items: []*order.ProductListItemT{}}
+	for _, a := range page {
+		size := a.size
+		data := s.d.link("/1/artifacts/"+a.id+"/blob", nil)
+		res.Items = append(res.Items, &order.ProductListItemT{
+			ID:       a.id,
+			Name:     a.name,
+			Status:   a.status,
+			MimeType: a.mimeType,
+			Size:     &size,
+			Href:     s.d.link("/1/artifacts/"+a.id, nil),
+			DataHref: &data,
+		})
+	}
+	for _, l := range links {
+		res.Links = append(res.Links, &order.LinkT{Rel: l[0], Type: "application/json", Href: l[1]})
+	}
+	return res
+}
+
+// Metadata implements order.Service. It lists the metadata records currently
+// attached to the order in the metadata fake of the deployment.
+func (s *OrderService) Metadata(ctx context.Context, p *order.MetadataPayload) (*order.PartialMetaListT, error) {
+	q, offset := *p, 0
+	q.JWT = ""
+	if p.Page != nil {
+		var err error
+		if q, offset, err = decodeCursor[order.MetadataPayload](*p.Page); err != nil {
+			return nil, &order.BadRequestT{Message: err.Error()}
+		}
+		q.Limit = p.Limit
+	}
+	q.Page = nil
+	s.mu.Lock()
+	_, ok := s.items[q.OrderID]
+	s.mu.Unlock()
+	if !ok {
+		return nil, &order.ResourceNotFoundT{ID: q.OrderID, Message: "order not found"}
+	}
+	all := s.d.Metadata.store.query(s.d.now(), &q.OrderID, nil)
+	sortRecords(all, q.OrderBy, q.OrderDesc, (*statement).fields)
+	path := "/1/orders/" + url.PathEscape(q.OrderID) + "/metadata"
+	page, links := paginate(all, offset, q.Limit, func(o int) string {
+		return s.d.link(path, url.Values{"limit": {strconv.Itoa(q.Limit)}, "page": {encodeCursor(q, o)}})
+	})
+	res := &order.PartialMetaListT{Items: []*order.OrderMetadataListItemRT{}}
+	for _, st := range page {
+		res.Items = append(res.Items, &order.OrderMetadataListItemRT{
+			ID:          st.id,
+			Schema:      st.schema,
+			Href:        s.d.link("/1/metadata/"+url.PathEscape(st.id), nil),
+			ContentType: st.contentType,
+		})
+	}
+	for _, l := range links {
+		res.Links = append(res.Links, &order.LinkT{Rel: l[0], Type: "application/json", Href: l[1]})
+	}
+	return res, nil
+}
+
+// Create implements order.Service. The ordered service must exist in the
+// service fake of the deployment.
+func (s *OrderService) Create(ctx context.Context, p *order.CreatePayload) (*order.OrderStatusRT, error) {
+	if p.Orders == nil {
+		return nil, &order.BadRequestT{Message: "missing order request"}
+	}
+	if !s.d.Services.exists(p.Orders.Service) {
+		return nil, &order.ResourceNotFoundT{ID: p.Orders.Service, Message: "service not found"}
+	}
+	r := &orderRecord{
+		id:        newUUIDURN(),
+		req:       *p.Orders,
+		status:    "pending",
+		account:   Account(User(ctx)),
+		orderedAt: s.d.now(),
+	}
+	s.mu.Lock()
+	s.items[r.id] = r
+	s.order = append(s.order, r)
+	c := *r
+	s.mu.Unlock()
+	return s.status(ctx, &c), nil
+}
+
+// Logs implements order.Service. From and To are Unix times in seconds.
+func (s *OrderService) Logs(ctx context.Context, p *order.LogsPayload) (io.ReadCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.items[p.OrderID]
+	if !ok {
+		return nil, &order.ResourceNotFoundT{ID: p.OrderID, Message: "order not found"}
+	}
+	var buf bytes.Buffer
+	for _, l := range r.logs {
+		if p.From != nil && l.at.Unix() < *p.From {
+			continue
+		}
+		if p.To != nil && l.at.Unix() > *p.To {
+			continue
+		}
+		fmt.Fprintln(&buf, l.text)
+	}
+	return io.NopCloser(&buf), nil
+}
+
+// Top implements order.Service.
+func (s *OrderService) Top(ctx context.Context, p *order.TopPayload) (order.OrderTopResultItemCollection, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.items[p.OrderID]
+	if !ok {
+		return nil, &order.ResourceNotFoundT{ID: p.OrderID, Message: "order not found"}
+	}
+	if r.top == nil {
+		return order.OrderTopResultItemCollection{}, nil
+	}
+	return r.top, nil
+}
+
+// SetStatus moves an order to status, recording when it started executing
+// and when it finished.
+func (s *OrderService) SetStatus(id, status string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.items[id]
+	if !ok {
+		return &order.ResourceNotFoundT{ID: id, Message: "order not found"}
+	}
+	now := s.d.now()
+	switch status {
+	case "executing":
+		if r.startedAt.IsZero() {
+			r.startedAt = now
+		}
+	case "succeeded", "failed", "error":
+		if r.startedAt.IsZero() {
+			r.startedAt = now
+		}
+		r.finishedAt = now
+	}
+	r.status = status
+	return nil
+}
+
+// AddProduct records the artifact with the given ID as a product of an order.
+func (s *OrderService) AddProduct(orderID, artifactID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.items[orderID]
+	if !ok {
+		return &order.ResourceNotFoundT{ID: orderID, Message: "order not found"}
+	}
+	r.products = append(r.products, artifactID)
+	return nil
+}
+
+// AppendLog appends a line to the log of an order.
+func (s *OrderService) AppendLog(orderID, line string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.items[orderID]
+	if !ok {
+		return &order.ResourceNotFoundT{ID: orderID, Message: "order not found"}
+	}
+	r.logs = append(r.logs, logLine{at: s.d.now(), text: line})
+	return nil
+}
+
+// SetTop sets the resource usage reported for an order.
+func (s *OrderService) SetTop(orderID string, top order.OrderTopResultItemCollection) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.items[orderID]
+	if !ok {
+		return &order.ResourceNotFoundT{ID: orderID, Message: "order not found"}
+	}
+	r.top = top
+	return nil
+}
+
+func (s *OrderService) status(ctx context.Context, r *orderRecord) *order.OrderStatusRT {
+	self := s.d.link("/1/orders/"+url.PathEscape(r.id), nil)
+	return &order.OrderStatusRT{
+		ID:         r.id,
+		Status:     r.status,
+		OrderedAt:  timePtr(r.orderedAt),
+		StartedAt:  timePtr(r.startedAt),
+		FinishedAt: timePtr(r.finishedAt),
+		Products:   s.products(ctx, r.products, order.ProductsPayload{OrderID: r.id, Limit: 10}, 0),
+		Service:    r.req.Service,
+		Account:    r.account,
+		Name:       r.req.Name,
+		Tags:       r.req.Tags,
+		Parameters: r.req.Parameters,
+		Links: []*order.LinkT{
+			{Rel: "self", Type: "application/json", Href: self},
+		},
+	}
+}
+
+func (r *orderRecord) fields(name string) (string, bool) {
+	switch name {
+	case "id":
+		return r.id, true
+	case "name":
+		return stringOr(r.req.Name, ""), r.req.Name != nil
+	case "status":
+		return r.status, true
+	case "service":
+		return r.req.Service, true
+	case "account":
+		return r.account, true
+	case "ordered-at", "ordered_at", "orderedAt":
+		return formatTime(r.orderedAt), true
+	case "started-at", "started_at", "startedAt":
+		return formatTime(r.startedAt), !r.startedAt.IsZero()
+	case "finished-at", "finished_at", "finishedAt":
+		return formatTime(r.finishedAt), !r.finishedAt.IsZero()
+	}
+	return "", false
+}
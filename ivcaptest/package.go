@@ -0,0 +1,236 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcaptest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	package_ "github.com/ivcap-works/ivcap-core-api/gen/package_"
+	"goa.design/goa/v3/security"
+)
+
+// PackageService is an in-memory fake of the package service. It stores the
+// manifest, config and layers of each pushed image by digest.
+type PackageService struct {
+	d     *Deployment
+	mu    sync.Mutex
+	tags  map[string]*imageRecord
+	blobs map[string][]byte
+	sizes map[string]int
+}
+
+type imageRecord struct {
+	tag      string
+	manifest string
+	config   string
+	layers   []string
+}
+
+var _ package_.Service = (*PackageService)(nil)
+var _ package_.Auther = (*PackageService)(nil)
+
+// JWTAuth implements package_.Auther.
+func (s *PackageService) JWTAuth(ctx context.Context, token string, scheme *security.JWTScheme) (context.Context, error) {
+	return s.d.authorize(ctx, token, scheme, &package_.UnauthorizedT{})
+}
+
+// List implements package_.Service. Tags are listed in lexical order,
+// restricted to those starting with Tag if set.
+func (s *PackageService) List(ctx context.Context, p *package_.ListPayload) (*package_.ListResult, error) {
+	q, offset := *p, 0
+	q.JWT = ""
+	if p.Page != nil {
+		var err error
+		if q, offset, err = decodeCursor[package_.ListPayload](*p.Page); err != nil {
+			return nil, &package_.BadRequestT{Message: err.Error()}
+		}
+		q.Limit = p.Limit
+	}
+	q.Page = nil
+	limit := 10
+	if q.Limit != nil {
+		limit = *q.Limit
+	}
+	s.mu.Lock()
+	var all []string
+	for tag := range s.tags {
+		if q.Tag == nil || strings.HasPrefix(tag, *q.Tag) {
+			all = append(all, tag)
+		}
+	}
+	s.mu.Unlock()
+	sort.Strings(all)
+	page, links := paginate(all, offset, limit, func(o int) string {
+		return s.d.link("/1/packages/list", url.Values{"limit": {strconv.Itoa(limit)}, "page": {encodeCursor(q, o)}})
+	})
+	res := &package_.ListResult{Items: append([]string{}, page...)}
+	for _, l := range links {
+		res.Links = append(res.Links, &package_.LinkT{Rel: l[0], Type: "application/json", Href: l[1]})
+	}
+	return res, nil
+}
+
+// Pull implements package_.Service. The manifest and config are pulled by
+// tag, layers by digest.
+func (s *PackageService) Pull(ctx context.Context, p *package_.PullPayload) (*package_.PullResultT, io.ReadCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	digest := p.Ref
+	if p.Type == "manifest" || p.Type == "config" {
+		img, ok := s.tags[p.Ref]
+		if !ok {
+			return nil, nil, &package_.InvalidParameterT{Name: "ref", Message: "unknown tag", Value: &p.Ref}
+		}
+		digest = img.manifest
+		if p.Type == "config" {
+			digest = img.config
+		}
+	}
+	blob, ok := s.blobs[digest]
+	if !ok {
+		return nil, nil, &package_.InvalidParameterT{Name: "ref", Message: "unknown " + p.Type, Value: &p.Ref}
+	}
+	offset := 0
+	if p.Offset != nil {
+		offset = *p.Offset
+	}
+	if offset < 0 || offset > len(blob) {
+		v := strconv.Itoa(offset)
+		return nil, nil, &package_.InvalidParameterT{Name: "offset", Message: "offset out of range", Value: &v}
+	}
+	res := &package_.PullResultT{Total: len(blob), Available: len(blob) - offset}
+	return res, io.NopCloser(bytes.NewReader(blob[offset:])), nil
+}
+
+// Push implements package_.Service. Layers may be pushed in chunks, each
+// giving its Start, End and the Total size of the layer. Pushing a layer
+// which is already complete reports it as existing.
+func (s *PackageService) Push(ctx context.Context, p *package_.PushPayload, body io.ReadCloser) (*package_.PushResult, error) {
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, &package_.BadRequestT{Message: fmt.Sprintf("reading content: %s", err)}
+	}
+	force := p.Force != nil && *p.Force
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	img, ok := s.tags[p.Tag]
+	if !ok {
+		img = &imageRecord{tag: p.Tag}
+	}
+	switch p.Type {
+	case "manifest":
+		if ok && img.manifest != "" && img.manifest != p.Digest && !force {
+			return nil, &package_.ResourceAlreadyCreatedT{ID: p.Tag, Message: "tag already exists"}
+		}
+		img.manifest = p.Digest
+	case "config":
+		img.config = p.Digest
+	case "layer":
+		if !containsString(img.layers, p.Digest) {
+			img.layers = append(img.layers, p.Digest)
+		}
+	default:
+		return nil, &package_.InvalidParameterT{Name: "type", Message: "must be manifest, config or layer", Value: &p.Type}
+	}
+	s.tags[p.Tag] = img
+	if s.complete(p.Digest) && !force {
+		return &package_.PushResult{Digest: p.Digest, Exists: true}, nil
+	}
+	if p.Start == nil {
+		s.blobs[p.Digest] = data
+		s.setSize(p.Digest, len(data))
+		return &package_.PushResult{Digest: p.Digest}, nil
+	}
+	start := *p.Start
+	if start < 0 {
+		v := strconv.Itoa(start)
+		return nil, &package_.InvalidParameterT{Name: "start", Message: "start must not be negative", Value: &v}
+	}
+	blob := s.blobs[p.Digest]
+	if end := start + len(data); end > len(blob) {
+		blob = append(blob, make([]byte, end-len(blob))...)
+	}
+	copy(blob[start:], data)
+	s.blobs[p.Digest] = blob
+	size := len(blob)
+	if p.Total != nil {
+		size = *p.Total
+	}
+	s.setSize(p.Digest, size)
+	return &package_.PushResult{Digest: p.Digest}, nil
+}
+
+// Status implements package_.Service.
+func (s *PackageService) Status(ctx context.Context, p *package_.StatusPayload) (*package_.PushStatusT, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	img, ok := s.tags[p.Tag]
+	if !ok {
+		return &package_.PushStatusT{Status: "unknown", Message: "unknown tag"}, nil
+	}
+	if p.Digest != img.manifest && p.Digest != img.config && !containsString(img.layers, p.Digest) {
+		return &package_.PushStatusT{Status: "unknown", Message: "digest is not part of the image"}, nil
+	}
+	if !s.complete(p.Digest) {
+		return &package_.PushStatusT{Status: "pending", Message: "push is incomplete"}, nil
+	}
+	return &package_.PushStatusT{Status: "ready", Message: "push is complete"}, nil
+}
+
+// Remove implements package_.Service.
+func (s *PackageService) Remove(ctx context.Context, p *package_.RemovePayload) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.tags[p.Tag]; !ok {
+		return &package_.InvalidParameterT{Name: "tag", Message: "unknown tag", Value: &p.Tag}
+	}
+	delete(s.tags, p.Tag)
+	return nil
+}
+
+// complete reports whether all the content of the blob with the given digest
+// has been pushed. s.mu must be held.
+func (s *PackageService) complete(digest string) bool {
+	blob, ok := s.blobs[digest]
+	if !ok {
+		return false
+	}
+	size, ok := s.sizes[digest]
+	return ok && len(blob) >= size
+}
+
+// setSize records the expected size of a blob. s.mu must be held.
+func (s *PackageService) setSize(digest string, size int) {
+	s.sizes[digest] = size
+}
+
+// containsString reports whether list contains s.
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,387 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcaptest
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	project "github.com/ivcap-works/ivcap-core-api/gen/project"
+	"goa.design/goa/v3/security"
+)
+
+// Roles of the members of a project.
+const (
+	RoleOwner  = "owner"
+	RoleMember = "member"
+)
+
+// ProjectService is an in-memory fake of the project service. The creator of
+// a project becomes its owner, and only owners may change its membership,
+// account or delete it.
+type ProjectService struct {
+	d        *Deployment
+	mu       sync.Mutex
+	items    map[string]*projectRecord
+	order    []*projectRecord
+	defaults map[string]string
+}
+
+type projectRecord struct {
+	urn        string
+	name       string
+	account    string
+	parent     *string
+	properties *project.ProjectProperties
+	members    map[string]string
+	createdAt  time.Time
+	modifiedAt time.Time
+}
+
+var _ project.Service = (*ProjectService)(nil)
+var _ project.Auther = (*ProjectService)(nil)
+
+// JWTAuth implements project.Auther.
+func (s *ProjectService) JWTAuth(ctx context.Context, token string, scheme *security.JWTScheme) (context.Context, error) {
+	return s.d.authorize(ctx, token, scheme, &project.UnauthorizedT{})
+}
+
+// List implements project.Service. It lists the projects the caller is a
+// member of, with the caller's role.
+func (s *ProjectService) List(ctx context.Context, p *project.ListPayload) (*project.ProjectListRT, string, error) {
+	q, offset := *p, 0
+	q.JWT = ""
+	if p.Page != nil {
+		var err error
+		if q, offset, err = decodeCursor[project.ListPayload](*p.Page); err != nil {
+			return nil, "", &project.BadRequestT{Message: err.Error()}
+		}
+		q.Limit = p.Limit
+	}
+	q.Page = nil
+	if q.AtTime == nil {
+		q.AtTime = timePtr(s.d.now())
+	}
+	at, err := s.d.atTime(q.AtTime)
+	if err != nil {
+		return nil, "", &project.InvalidParameterT{Name: "at-time", Message: err.Error(), Value: q.AtTime}
+	}
+	user := User(ctx)
+	s.mu.Lock()
+	var all []*projectRecord
+	for _, r := range s.order {
+		if _, ok := r.members[user]; ok && !r.createdAt.After(at) {
+			all = append(all, r.copy())
+		}
+	}
+	s.mu.Unlock()
+	all, err = filterRecords(all, q.Filter, (*projectRecord).fields)
+	if err != nil {
+		return nil, "", &project.InvalidParameterT{Name: "filter", Message: err.Error(), Value: q.Filter}
+	}
+	sortRecords(all, q.OrderBy, q.OrderDesc, (*projectRecord).fields)
+	page, links := paginate(all, offset, q.Limit, func(o int) string { return encodeCursor(q, o) })
+	res := &project.ProjectListRT{AtTime: timePtr(at), Projects: project.ProjectListItemCollection{}}
+	for _, r := range page {
+		name, urn, role := r.name, r.urn, r.members[user]
+		res.Projects = append(res.Projects, &project.ProjectListItem{
+			Name:       &name,
+			Role:       &role,
+			Urn:        &urn,
+			CreatedAt:  timePtr(r.createdAt),
+			ModifiedAt: timePtr(r.modifiedAt),
+			AtTime:     timePtr(at),
+		})
+	}
+	res.Page = nextToken(links)
+	return res, "default", nil
+}
+
+// CreateProject implements project.Service.
+func (s *ProjectService) CreateProject(ctx context.Context, p *project.CreateProjectPayload) (*project.ProjectStatusRT, string, error) {
+	if p.Project == nil || p.Project.Name == "" {
+		return nil, "", &project.BadRequestT{Message: "missing project name"}
+	}
+	user := User(ctx)
+	now := s.d.now()
+	r := &projectRecord{
+		urn:        newURN("project"),
+		name:       p.Project.Name,
+		account:    stringOr(p.Project.AccountUrn, Account(user)),
+		parent:     p.Project.ParentProjectUrn,
+		properties: p.Project.Properties,
+		members:    map[string]string{user: RoleOwner},
+		createdAt:  now,
+		modifiedAt: now,
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r.parent != nil {
+		if _, ok := s.items[*r.parent]; !ok {
+			return nil, "", &project.ResourceNotFoundT{ID: *r.parent, Message: "parent project not found"}
+		}
+	}
+	s.items[r.urn] = r
+	s.order = append(s.order, r)
+	return r.status(), "default", nil
+}
+
+// Delete implements project.Service.
+func (s *ProjectService) Delete(ctx context.Context, p *project.DeletePayload) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, err := s.owned(ctx, p.ID)
+	if err != nil {
+		return err
+	}
+	delete(s.items, r.urn)
+	for i, o := range s.order {
+		if o == r {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+	for user, urn := range s.defaults {
+		if urn == r.urn {
+			delete(s.defaults, user)
+		}
+	}
+	return nil
+}
+
+// Read implements project.Service.
+func (s *ProjectService) Read(ctx context.Context, p *project.ReadPayload) (*project.ProjectStatusRT, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, err := s.member(ctx, p.ID)
+	if err != nil {
+		return nil, "", err
+	}
+	return r.status(), "default", nil
+}
+
+// ListProjectMembers implements project.Service.
+func (s *ProjectService) ListProjectMembers(ctx context.Context, p *project.ListProjectMembersPayload) (*project.MembersList, error) {
+	q, offset := *p, 0
+	q.JWT = ""
+	if p.Page != nil {
+		var err error
+		if q, offset, err = decodeCursor[project.ListProjectMembersPayload](*p.Page); err != nil {
+			return nil, &project.BadRequestT{Message: err.Error()}
+		}
+		q.Limit = p.Limit
+	}
+	q.Page = nil
+	s.mu.Lock()
+	r, err := s.member(ctx, q.Urn)
+	var users []string
+	if err == nil {
+		for user, role := range r.members {
+			if q.Role == nil || *q.Role == role {
+				users = append(users, user)
+			}
+		}
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(users)
+	page, links := paginate(users, offset, q.Limit, func(o int) string { return encodeCursor(q, o) })
+	res := &project.MembersList{AtTime: timePtr(s.d.now()), Members: []*project.UserListItem{}}
+	s.mu.Lock()
+	for _, user := range page {
+		urn, role := user, r.members[user]
+		res.Members = append(res.Members, &project.UserListItem{Urn: &urn, Role: &role})
+	}
+	s.mu.Unlock()
+	res.Page = nextToken(links)
+	return res, nil
+}
+
+// UpdateMembership implements project.Service.
+func (s *ProjectService) UpdateMembership(ctx context.Context, p *project.UpdateMembershipPayload) error {
+	if p.Role != RoleOwner && p.Role != RoleMember {
+		return &project.InvalidParameterT{Name: "role", Message: "role must be owner or member", Value: &p.Role}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, err := s.owned(ctx, p.ProjectUrn)
+	if err != nil {
+		return err
+	}
+	r.members[p.UserUrn] = p.Role
+	r.modifiedAt = s.d.now()
+	return nil
+}
+
+// RemoveMembership implements project.Service. The last owner of a project
+// cannot be removed.
+func (s *ProjectService) RemoveMembership(ctx context.Context, p *project.RemoveMembershipPayload) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, err := s.owned(ctx, p.ProjectUrn)
+	if err != nil {
+		return err
+	}
+	role, ok := r.members[p.UserUrn]
+	if !ok {
+		return &project.ResourceNotFoundT{ID: p.UserUrn, Message: "user is not a member of the project"}
+	}
+	if role == RoleOwner {
+		owners := 0
+		for _, role := range r.members {
+			if role == RoleOwner {
+				owners++
+			}
+		}
+		if owners == 1 {
+			return &project.BadRequestT{Message: "cannot remove the last owner of a project"}
+		}
+	}
+	delete(r.members, p.UserUrn)
+	r.modifiedAt = s.d.now()
+	return nil
+}
+
+// DefaultProject implements project.Service.
+func (s *ProjectService) DefaultProject(ctx context.Context, p *project.DefaultProjectPayload) (*project.ProjectStatusRT, string, error) {
+	user := User(ctx)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	urn, ok := s.defaults[user]
+	if !ok {
+		return nil, "", &project.ResourceNotFoundT{ID: user, Message: "no default project set"}
+	}
+	return s.items[urn].status(), "default", nil
+}
+
+// SetDefaultProject implements project.Service. The user, the caller unless
+// given, must be a member of the project.
+func (s *ProjectService) SetDefaultProject(ctx context.Context, p *project.SetDefaultProjectPayload) error {
+	user := stringOr(p.UserUrn, User(ctx))
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.items[p.ProjectUrn]
+	if !ok {
+		return &project.ResourceNotFoundT{ID: p.ProjectUrn, Message: "project not found"}
+	}
+	if _, ok := r.members[user]; !ok {
+		return &project.InvalidScopesT{Message: "user is not a member of the project"}
+	}
+	s.defaults[user] = r.urn
+	return nil
+}
+
+// ProjectAccount implements project.Service.
+func (s *ProjectService) ProjectAccount(ctx context.Context, p *project.ProjectAccountPayload) (*project.AccountResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, err := s.member(ctx, p.ProjectUrn)
+	if err != nil {
+		return nil, err
+	}
+	return &project.AccountResult{AccountUrn: r.account}, nil
+}
+
+// SetProjectAccount implements project.Service.
+func (s *ProjectService) SetProjectAccount(ctx context.Context, p *project.SetProjectAccountPayload) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, err := s.owned(ctx, p.ProjectUrn)
+	if err != nil {
+		return err
+	}
+	r.account = p.AccountUrn
+	r.modifiedAt = s.d.now()
+	return nil
+}
+
+// member returns the project with the given URN if the caller is one of its
+// members. s.mu must be held.
+func (s *ProjectService) member(ctx context.Context, urn string) (*projectRecord, error) {
+	r, ok := s.items[urn]
+	if !ok {
+		return nil, &project.ResourceNotFoundT{ID: urn, Message: "project not found"}
+	}
+	if _, ok := r.members[User(ctx)]; !ok {
+		return nil, &project.InvalidScopesT{Message: "not a member of the project"}
+	}
+	return r, nil
+}
+
+// owned returns the project with the given URN if the caller is one of its
+// owners. s.mu must be held.
+func (s *ProjectService) owned(ctx context.Context, urn string) (*projectRecord, error) {
+	r, err := s.member(ctx, urn)
+	if err != nil {
+		return nil, err
+	}
+	if r.members[User(ctx)] != RoleOwner {
+		return nil, &project.InvalidScopesT{Message: "not an owner of the project"}
+	}
+	return r, nil
+}
+
+func (r *projectRecord) copy() *projectRecord {
+	c := *r
+	c.members = make(map[string]string, len(r.members))
+	for k, v := range r.members {
+		c.members[k] = v
+	}
+	return &c
+}
+
+func (r *projectRecord) status() *project.ProjectStatusRT {
+	status, account, name := "active", r.account, r.name
+	return &project.ProjectStatusRT{
+		Status:     &status,
+		CreatedAt:  timePtr(r.createdAt),
+		ModifiedAt: timePtr(r.modifiedAt),
+		Account:    &account,
+		Parent:     r.parent,
+		Properties: r.properties,
+		Urn:        r.urn,
+		Name:       &name,
+	}
+}
+
+func (r *projectRecord) fields(name string) (string, bool) {
+	switch name {
+	case "urn", "id":
+		return r.urn, true
+	case "name":
+		return r.name, true
+	case "account":
+		return r.account, true
+	case "created-at", "created_at", "createdAt":
+		return formatTime(r.createdAt), true
+	case "modified-at", "modified_at", "modifiedAt":
+		return formatTime(r.modifiedAt), true
+	}
+	return "", false
+}
+
+// nextToken returns the href of the "next" link among links, or nil.
+func nextToken(links pageLinks) *string {
+	for _, l := range links {
+		if l[0] == "next" {
+			return &l[1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,278 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcaptest
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	queue "github.com/ivcap-works/ivcap-core-api/gen/queue"
+	"goa.design/goa/v3/security"
+)
+
+// QueueService is an in-memory fake of the queue service. Messages are
+// dequeued in the order they were enqueued.
+type QueueService struct {
+	d     *Deployment
+	mu    sync.Mutex
+	items map[string]*queueRecord
+	order []*queueRecord
+}
+
+type queueRecord struct {
+	id          string
+	name        string
+	description *string
+	policy      *string
+	account     string
+	createdAt   time.Time
+	messages    []*queueMessage
+}
+
+type queueMessage struct {
+	id          string
+	content     any
+	schema      *string
+	contentType *string
+	size        uint64
+	at          time.Time
+}
+
+var _ queue.Service = (*QueueService)(nil)
+var _ queue.Auther = (*QueueService)(nil)
+
+// JWTAuth implements queue.Auther.
+func (s *QueueService) JWTAuth(ctx context.Context, token string, scheme *security.JWTScheme) (context.Context, error) {
+	return s.d.authorize(ctx, token, scheme, &queue.UnauthorizedT{})
+}
+
+// Create implements queue.Service. Queue names are unique.
+func (s *QueueService) Create(ctx context.Context, p *queue.CreatePayload) (*queue.Createqueueresponse, error) {
+	if p.Queues == nil || p.Queues.Name == "" {
+		return nil, &queue.BadRequestT{Message: "missing queue name"}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, r := range s.order {
+		if r.name == p.Queues.Name {
+			return nil, &queue.ResourceAlreadyCreatedT{ID: r.id, Message: "a queue with that name already exists"}
+		}
+	}
+	r := &queueRecord{
+		id:          newURN("queue"),
+		name:        p.Queues.Name,
+		description: p.Queues.Description,
+		policy:      p.Queues.Policy,
+		account:     Account(User(ctx)),
+		createdAt:   s.d.now(),
+	}
+	s.items[r.id] = r
+	s.order = append(s.order, r)
+	return &queue.Createqueueresponse{
+		ID:          r.id,
+		Name:        r.name,
+		Description: r.description,
+		Account:     &r.account,
+	}, nil
+}
+
+// Read implements queue.Service.
+func (s *QueueService) Read(ctx context.Context, p *queue.ReadPayload) (*queue.Readqueueresponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.items[p.ID]
+	if !ok {
+		return nil, &queue.ResourceNotFoundT{ID: p.ID, Message: "queue not found"}
+	}
+	total, size, consumers := uint64(len(r.messages)), uint64(0), 0
+	for _, m := range r.messages {
+		size += m.size
+	}
+	res := &queue.Readqueueresponse{
+		ID:            r.id,
+		Name:          r.name,
+		Description:   r.description,
+		TotalMessages: &total,
+		Bytes:         &size,
+		ConsumerCount: &consumers,
+		CreatedAt:     formatTime(r.createdAt),
+	}
+	if len(r.messages) > 0 {
+		res.FirstTime = timePtr(r.messages[0].at)
+		res.LastTime = timePtr(r.messages[len(r.messages)-1].at)
+	}
+	return res, nil
+}
+
+// Delete implements queue.Service.
+func (s *QueueService) Delete(ctx context.Context, p *queue.DeletePayload) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.items[p.ID]
+	if !ok {
+		return &queue.ResourceNotFoundT{ID: p.ID, Message: "queue not found"}
+	}
+	delete(s.items, r.id)
+	for i, o := range s.order {
+		if o == r {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// List implements queue.Service.
+func (s *QueueService) List(ctx context.Context, p *queue.ListPayload) (*queue.QueueListResult, error) {
+	q, offset, err := s.query(p)
+	if err != nil {
+		return nil, &queue.BadRequestT{Message: err.Error()}
+	}
+	at, err := s.d.atTime(q.AtTime)
+	if err != nil {
+		return nil, &queue.InvalidParameterT{Name: "at-time", Message: err.Error(), Value: q.AtTime}
+	}
+	s.mu.Lock()
+	var all []*queueRecord
+	for _, r := range s.order {
+		if !r.createdAt.After(at) {
+			c := *r
+			all = append(all, &c)
+		}
+	}
+	s.mu.Unlock()
+	all, err = filterRecords(all, q.Filter, (*queueRecord).fields)
+	if err != nil {
+		return nil, &queue.InvalidParameterT{Name: "filter", Message: err.Error(), Value: q.Filter}
+	}
+	sortRecords(all, q.OrderBy, q.OrderDesc, (*queueRecord).fields)
+	page, links := paginate(all, offset, q.Limit, func(o int) string {
+		return s.d.link("/1/queues", url.Values{"limit": {strconv.Itoa(q.Limit)}, "page": {encodeCursor(q, o)}})
+	})
+	res := &queue.QueueListResult{AtTime: formatTime(at), Items: []*queue.QueueListItem{}}
+	for _, r := range page {
+		name := r.name
+		res.Items = append(res.Items, &queue.QueueListItem{
+			ID:          r.id,
+			Name:        &name,
+			Description: r.description,
+			Account:     r.account,
+			Href:        s.d.link("/1/queues/"+url.PathEscape(r.id), nil),
+		})
+	}
+	for _, l := range links {
+		res.Links = append(res.Links, &queue.LinkT{Rel: l[0], Type: "application/json", Href: l[1]})
+	}
+	return res, nil
+}
+
+// query returns the query and offset of a list request, restoring them from
+// the page token if one is given.
+func (s *QueueService) query(p *queue.ListPayload) (queue.ListPayload, int, error) {
+	if p.Page != nil {
+		q, offset, err := decodeCursor[queue.ListPayload](*p.Page)
+		q.Limit = p.Limit
+		return q, offset, err
+	}
+	q := *p
+	q.JWT = ""
+	if q.AtTime == nil {
+		q.AtTime = timePtr(s.d.now())
+	}
+	return q, 0, nil
+}
+
+// Enqueue implements queue.Service.
+func (s *QueueService) Enqueue(ctx context.Context, p *queue.EnqueuePayload) (*queue.Messagestatus, error) {
+	if p.ContentType != nil {
+		if err := checkJSONContentType(*p.ContentType); err != nil {
+			return nil, &queue.BadRequestT{Message: err.Error()}
+		}
+	}
+	b, err := json.Marshal(p.Content)
+	if err != nil {
+		return nil, &queue.BadRequestT{Message: "invalid content: " + err.Error()}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.items[p.ID]
+	if !ok {
+		return nil, &queue.ResourceNotFoundT{ID: p.ID, Message: "queue not found"}
+	}
+	m := &queueMessage{
+		id:          newURN("message"),
+		content:     normalizeJSON(p.Content),
+		schema:      p.Schema,
+		contentType: p.ContentType,
+		size:        uint64(len(b)),
+		at:          s.d.now(),
+	}
+	r.messages = append(r.messages, m)
+	return &queue.Messagestatus{ID: &m.id}, nil
+}
+
+// Dequeue implements queue.Service. It removes and returns up to Limit
+// messages, one if no limit is given.
+func (s *QueueService) Dequeue(ctx context.Context, p *queue.DequeuePayload) (*queue.MessageList, error) {
+	limit := 1
+	if p.Limit != nil {
+		limit = *p.Limit
+	}
+	if limit < 1 {
+		v := strconv.Itoa(limit)
+		return nil, &queue.InvalidParameterT{Name: "limit", Message: "limit must be positive", Value: &v}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.items[p.ID]
+	if !ok {
+		return nil, &queue.ResourceNotFoundT{ID: p.ID, Message: "queue not found"}
+	}
+	if limit > len(r.messages) {
+		limit = len(r.messages)
+	}
+	res := &queue.MessageList{AtTime: timePtr(s.d.now()), Messages: []*queue.Publishedmessage{}}
+	for _, m := range r.messages[:limit] {
+		id := m.id
+		res.Messages = append(res.Messages, &queue.Publishedmessage{
+			ID:          &id,
+			Content:     m.content,
+			Schema:      m.schema,
+			ContentType: m.contentType,
+		})
+	}
+	r.messages = r.messages[limit:]
+	return res, nil
+}
+
+func (r *queueRecord) fields(name string) (string, bool) {
+	switch name {
+	case "id":
+		return r.id, true
+	case "name":
+		return r.name, true
+	case "description":
+		return stringOr(r.description, ""), r.description != nil
+	case "account":
+		return r.account, true
+	case "created-at", "created_at", "createdAt":
+		return formatTime(r.createdAt), true
+	}
+	return "", false
+}
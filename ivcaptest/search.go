@@ -0,0 +1,87 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcaptest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+
+	search "github.com/ivcap-works/ivcap-core-api/gen/search"
+	"goa.design/goa/v3/security"
+)
+
+// SearchService is an in-memory fake of the search service. Rather than
+// evaluating the query, it returns the aspects valid at the query time whose
+// JSON encoded content contains the query text.
+type SearchService struct {
+	d *Deployment
+}
+
+// searchQuery is the state saved in the page tokens of search results.
+type searchQuery struct {
+	Query  []byte `json:"q"`
+	AtTime string `json:"t"`
+}
+
+var _ search.Service = (*SearchService)(nil)
+var _ search.Auther = (*SearchService)(nil)
+
+// JWTAuth implements search.Auther.
+func (s *SearchService) JWTAuth(ctx context.Context, token string, scheme *security.JWTScheme) (context.Context, error) {
+	return s.d.authorize(ctx, token, scheme, &search.UnauthorizedT{})
+}
+
+// Search implements search.Service.
+func (s *SearchService) Search(ctx context.Context, p *search.SearchPayload) (*search.SearchListRT, error) {
+	q, offset := searchQuery{Query: p.Query}, 0
+	if page, ok := p.Page.(string); ok && page != "" {
+		var err error
+		if q, offset, err = decodeCursor[searchQuery](page); err != nil {
+			return nil, &search.BadRequestT{Message: err.Error()}
+		}
+	} else {
+		at, err := s.d.atTime(p.AtTime)
+		if err != nil {
+			return nil, &search.InvalidParameterT{Name: "at-time", Message: err.Error(), Value: p.AtTime}
+		}
+		q.AtTime = formatTime(at)
+	}
+	at, _ := s.d.atTime(&q.AtTime)
+	text := bytes.TrimSpace(q.Query)
+	var all []any
+	for _, st := range s.d.Aspects.store.query(at, nil, nil) {
+		b, err := json.Marshal(st.content)
+		if err != nil || !bytes.Contains(b, text) {
+			continue
+		}
+		all = append(all, map[string]any{
+			"id":      st.id,
+			"entity":  st.entity,
+			"schema":  st.schema,
+			"content": st.content,
+		})
+	}
+	page, links := paginate(all, offset, p.Limit, func(o int) string {
+		return s.d.link("/1/search", url.Values{"limit": {strconv.Itoa(p.Limit)}, "page": {encodeCursor(q, o)}})
+	})
+	res := &search.SearchListRT{AtTime: q.AtTime, Items: append([]any{}, page...)}
+	for _, l := range links {
+		res.Links = append(res.Links, &search.LinkT{Rel: l[0], Type: "application/json", Href: l[1]})
+	}
+	return res, nil
+}
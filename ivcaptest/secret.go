@@ -0,0 +1,147 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcaptest
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+
+	secret "github.com/ivcap-works/ivcap-core-api/gen/secret"
+	"goa.design/goa/v3/security"
+)
+
+// SecretService is an in-memory fake of the secret service. Secrets are kept
+// per account, and those past their expiry time are not returned.
+type SecretService struct {
+	d     *Deployment
+	mu    sync.Mutex
+	items map[string]*secretRecord
+}
+
+type secretRecord struct {
+	account    string
+	name       string
+	typ        string
+	value      string
+	expiryTime int64
+}
+
+var _ secret.Service = (*SecretService)(nil)
+var _ secret.Auther = (*SecretService)(nil)
+
+// JWTAuth implements secret.Auther.
+func (s *SecretService) JWTAuth(ctx context.Context, token string, scheme *security.JWTScheme) (context.Context, error) {
+	return s.d.authorize(ctx, token, scheme, &secret.UnauthorizedT{})
+}
+
+// List implements secret.Service. Secrets are listed by name, the next page
+// being addressed by an offset.
+func (s *SecretService) List(ctx context.Context, p *secret.ListPayload) (*secret.ListResult, error) {
+	offset := 0
+	if p.Offset != nil && *p.Offset != "" {
+		o, err := strconv.Atoi(*p.Offset)
+		if err != nil || o < 0 {
+			return nil, &secret.InvalidParameterT{Name: "offset", Message: "invalid offset", Value: p.Offset}
+		}
+		offset = o
+	}
+	limit := 10
+	if p.Limit != nil {
+		limit = *p.Limit
+	}
+	account := Account(User(ctx))
+	now := s.d.now().Unix()
+	s.mu.Lock()
+	var all []*secretRecord
+	for _, r := range s.items {
+		if r.account == account && r.valid(now) {
+			c := *r
+			all = append(all, &c)
+		}
+	}
+	s.mu.Unlock()
+	sort.Slice(all, func(i, j int) bool { return all[i].name < all[j].name })
+	all, err := filterRecords(all, p.Filter, (*secretRecord).fields)
+	if err != nil {
+		return nil, &secret.InvalidParameterT{Name: "filter", Message: err.Error(), Value: p.Filter}
+	}
+	page, links := paginate(all, offset, limit, func(o int) string {
+		q := url.Values{"limit": {strconv.Itoa(limit)}, "offset": {strconv.Itoa(o)}}
+		if p.Filter != nil {
+			q.Set("filter", *p.Filter)
+		}
+		return s.d.link("/1/secrets/list", q)
+	})
+	res := &secret.ListResult{Items: []*secret.SecretListItem{}}
+	for _, r := range page {
+		res.Items = append(res.Items, &secret.SecretListItem{SecretName: r.name, ExpiryTime: r.expiryTime})
+	}
+	for _, l := range links {
+		res.Links = append(res.Links, &secret.LinkT{Rel: l[0], Type: "application/json", Href: l[1]})
+	}
+	return res, nil
+}
+
+// Get implements secret.Service.
+func (s *SecretService) Get(ctx context.Context, p *secret.GetPayload) (*secret.SecretResultT, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.items[secretKey(Account(User(ctx)), p.SecretName)]
+	if !ok || !r.valid(s.d.now().Unix()) || (p.SecretType != nil && *p.SecretType != "" && *p.SecretType != r.typ) {
+		return nil, &secret.ResourceNotFoundT{ID: p.SecretName, Message: "secret not found"}
+	}
+	return &secret.SecretResultT{SecretName: r.name, SecretValue: r.value, ExpiryTime: r.expiryTime}, nil
+}
+
+// Set implements secret.Service.
+func (s *SecretService) Set(ctx context.Context, p *secret.SetPayload) error {
+	if p.Secrets == nil || p.Secrets.SecretName == "" {
+		return &secret.BadRequestT{Message: "missing secret name"}
+	}
+	r := &secretRecord{
+		account:    Account(User(ctx)),
+		name:       p.Secrets.SecretName,
+		typ:        stringOr(p.Secrets.SecretType, ""),
+		value:      p.Secrets.SecretValue,
+		expiryTime: p.Secrets.ExpiryTime,
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.items[secretKey(r.account, r.name)] = r
+	return nil
+}
+
+// valid reports whether the secret has not expired at Unix time now. An
+// expiry time of zero means the secret never expires.
+func (r *secretRecord) valid(now int64) bool {
+	return r.expiryTime == 0 || r.expiryTime > now
+}
+
+func (r *secretRecord) fields(name string) (string, bool) {
+	switch name {
+	case "name", "secret-name", "secret_name", "secretName":
+		return r.name, true
+	case "type", "secret-type", "secret_type", "secretType":
+		return r.typ, r.typ != ""
+	}
+	return "", false
+}
+
+func secretKey(account, name string) string {
+	return account + "\x00" + name
+}
@@ -0,0 +1,132 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcaptest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	artifact "github.com/ivcap-works/ivcap-core-api/gen/artifact"
+	aspect "github.com/ivcap-works/ivcap-core-api/gen/aspect"
+	dashboard "github.com/ivcap-works/ivcap-core-api/gen/dashboard"
+	metadata "github.com/ivcap-works/ivcap-core-api/gen/metadata"
+	openapi "github.com/ivcap-works/ivcap-core-api/gen/openapi"
+	order "github.com/ivcap-works/ivcap-core-api/gen/order"
+	package_ "github.com/ivcap-works/ivcap-core-api/gen/package_"
+	project "github.com/ivcap-works/ivcap-core-api/gen/project"
+	queue "github.com/ivcap-works/ivcap-core-api/gen/queue"
+	search "github.com/ivcap-works/ivcap-core-api/gen/search"
+	secret "github.com/ivcap-works/ivcap-core-api/gen/secret"
+	service "github.com/ivcap-works/ivcap-core-api/gen/service"
+	artifactsvr "github.com/ivcap-works/ivcap-core-api/http/artifact/server"
+	aspectsvr "github.com/ivcap-works/ivcap-core-api/http/aspect/server"
+	dashboardsvr "github.com/ivcap-works/ivcap-core-api/http/dashboard/server"
+	metadatasvr "github.com/ivcap-works/ivcap-core-api/http/metadata/server"
+	openapisvr "github.com/ivcap-works/ivcap-core-api/http/openapi/server"
+	ordersvr "github.com/ivcap-works/ivcap-core-api/http/order/server"
+	packagesvr "github.com/ivcap-works/ivcap-core-api/http/package_/server"
+	projectsvr "github.com/ivcap-works/ivcap-core-api/http/project/server"
+	queuesvr "github.com/ivcap-works/ivcap-core-api/http/queue/server"
+	searchsvr "github.com/ivcap-works/ivcap-core-api/http/search/server"
+	secretsvr "github.com/ivcap-works/ivcap-core-api/http/secret/server"
+	servicesvr "github.com/ivcap-works/ivcap-core-api/http/service/server"
+	goahttp "goa.design/goa/v3/http"
+)
+
+// Handler returns an HTTP handler serving all the fakes of the deployment
+// under their /1/... paths, speaking the wire format of the generated
+// clients. It can be served with net/http or httptest:
+//
+//	srv := httptest.NewServer(ivcaptest.New().Handler())
+//	c, err := ivcap.New(srv.URL, ivcap.WithJWT(ivcaptest.Token(user)))
+func (d *Deployment) Handler() http.Handler {
+	var (
+		mux = goahttp.NewMuxer()
+		dec = goahttp.RequestDecoder
+		enc = goahttp.ResponseEncoder
+		eh  = func(ctx context.Context, w http.ResponseWriter, err error) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	)
+	var (
+		artifacts  = artifactsvr.New(artifact.NewEndpoints(d.Artifacts), mux, dec, enc, eh, nil)
+		aspects    = aspectsvr.New(aspect.NewEndpoints(d.Aspects), mux, dec, enc, eh, nil)
+		dashboards = dashboardsvr.New(dashboard.NewEndpoints(d.Dashboards), mux, dec, enc, eh, nil)
+		metadatas  = metadatasvr.New(metadata.NewEndpoints(d.Metadata), mux, dec, enc, eh, nil)
+		orders     = ordersvr.New(order.NewEndpoints(d.Orders), mux, dec, enc, eh, nil)
+		packages   = packagesvr.New(package_.NewEndpoints(d.Packages), mux, dec, enc, eh, nil)
+		projects   = projectsvr.New(project.NewEndpoints(d.Projects), mux, dec, enc, eh, nil)
+		queues     = queuesvr.New(queue.NewEndpoints(d.Queues), mux, dec, enc, eh, nil)
+		searches   = searchsvr.New(search.NewEndpoints(d.Search), mux, dec, enc, eh, nil)
+		secrets    = secretsvr.New(secret.NewEndpoints(d.Secrets), mux, dec, enc, eh, nil)
+		services   = servicesvr.New(service.NewEndpoints(d.Services), mux, dec, enc, eh, nil)
+	)
+	artifactsvr.Mount(mux, artifacts)
+	artifactsvr.MountResumable(mux, d.Artifacts)
+	artifactsvr.MountBlob(mux, d.Artifacts)
+	aspectsvr.Mount(mux, aspects)
+	dashboardsvr.Mount(mux, dashboards)
+	metadatasvr.Mount(mux, metadatas)
+	ordersvr.Mount(mux, orders)
+	packagesvr.Mount(mux, packages)
+	projectsvr.Mount(mux, projects)
+	queuesvr.Mount(mux, queues)
+	searchsvr.Mount(mux, searches)
+	secretsvr.Mount(mux, secrets)
+	servicesvr.Mount(mux, services)
+	doc := d.OpenAPISpec
+	if doc == nil {
+		doc = spec(map[string][]*openapisvr.MountPoint{
+			artifact.ServiceName:  artifacts.Mounts,
+			aspect.ServiceName:    aspects.Mounts,
+			dashboard.ServiceName: dashboards.Mounts,
+			metadata.ServiceName:  metadatas.Mounts,
+			order.ServiceName:     orders.Mounts,
+			package_.ServiceName:  packages.Mounts,
+			project.ServiceName:   projects.Mounts,
+			queue.ServiceName:     queues.Mounts,
+			search.ServiceName:    searches.Mounts,
+			secret.ServiceName:    secrets.Mounts,
+			service.ServiceName:   services.Mounts,
+		})
+	}
+	openapisvr.Mount(mux, openapisvr.New(doc))
+	return mux
+}
+
+// spec returns the OpenAPI document listing the operations mounted by each
+// service, in the shape served by a real deployment.
+func spec(mounts map[string][]*openapisvr.MountPoint) []byte {
+	paths := map[string]map[string]any{}
+	for svc, mps := range mounts {
+		for _, mp := range mps {
+			if paths[mp.Pattern] == nil {
+				paths[mp.Pattern] = map[string]any{}
+			}
+			paths[mp.Pattern][strings.ToLower(mp.Verb)] = map[string]any{
+				"operationId": svc + "#" + mp.Method,
+				"tags":        []string{svc},
+			}
+		}
+	}
+	b, _ := json.Marshal(map[string]any{
+		"openapi": "3.0.3",
+		"info":    map[string]any{"title": "IVCAP", "version": openapi.APIVersion},
+		"paths":   paths,
+	})
+	return b
+}
@@ -0,0 +1,233 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcaptest
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	service "github.com/ivcap-works/ivcap-core-api/gen/service"
+	"goa.design/goa/v3/security"
+)
+
+// ServiceService is an in-memory fake of the service service.
+type ServiceService struct {
+	d     *Deployment
+	mu    sync.Mutex
+	items map[string]*serviceRecord
+	order []*serviceRecord
+}
+
+type serviceRecord struct {
+	id          string
+	def         service.ServiceDefinitionT
+	status      string
+	account     string
+	publishedAt time.Time
+	deletedAt   time.Time
+}
+
+var _ service.Service = (*ServiceService)(nil)
+var _ service.Auther = (*ServiceService)(nil)
+
+// JWTAuth implements service.Auther.
+func (s *ServiceService) JWTAuth(ctx context.Context, token string, scheme *security.JWTScheme) (context.Context, error) {
+	return s.d.authorize(ctx, token, scheme, &service.UnauthorizedT{})
+}
+
+// List implements service.Service.
+func (s *ServiceService) List(ctx context.Context, p *service.ListPayload) (*service.ServiceListRT, error) {
+	q, offset, err := s.query(p)
+	if err != nil {
+		return nil, &service.BadRequestT{Message: err.Error()}
+	}
+	at, err := s.d.atTime(q.AtTime)
+	if err != nil {
+		return nil, &service.InvalidParameterT{Name: "at-time", Message: err.Error(), Value: q.AtTime}
+	}
+	s.mu.Lock()
+	var all []*serviceRecord
+	for _, r := range s.order {
+		if validAt(at, r.publishedAt, r.deletedAt) {
+			c := *r
+			all = append(all, &c)
+		}
+	}
+	s.mu.Unlock()
+	all, err = filterRecords(all, q.Filter, (*serviceRecord).fields)
+	if err != nil {
+		return nil, &service.InvalidParameterT{Name: "filter", Message: err.Error(), Value: q.Filter}
+	}
+	sortRecords(all, q.OrderBy, q.OrderDesc, (*serviceRecord).fields)
+	page, links := paginate(all, offset, q.Limit, func(o int) string {
+		return s.d.link("/1/services", url.Values{"limit": {strconv.Itoa(q.Limit)}, "page": {encodeCursor(q, o)}})
+	})
+	res := &service.ServiceListRT{AtTime: formatTime(at), Items: []*service.ServiceListItem{}}
+	for _, r := range page {
+		res.Items = append(res.Items, &service.ServiceListItem{
+			ID:          r.id,
+			Name:        r.def.Name,
+			Description: &r.def.Description,
+			Banner:      r.def.Banner,
+			PublishedAt: timePtr(r.publishedAt),
+			Policy:      r.def.Policy,
+			Account:     r.account,
+			Href:        s.d.link("/1/services/"+url.PathEscape(r.id), nil),
+		})
+	}
+	for _, l := range links {
+		res.Links = append(res.Links, &service.LinkT{Rel: l[0], Type: "application/json", Href: l[1]})
+	}
+	return res, nil
+}
+
+// query returns the query and offset of a list request, restoring them from
+// the page token if one is given.
+func (s *ServiceService) query(p *service.ListPayload) (service.ListPayload, int, error) {
+	if p.Page != nil {
+		q, offset, err := decodeCursor[service.ListPayload](*p.Page)
+		q.Limit = p.Limit
+		return q, offset, err
+	}
+	q := *p
+	q.JWT = ""
+	if q.AtTime == nil {
+		q.AtTime = timePtr(s.d.now())
+	}
+	return q, 0, nil
+}
+
+// CreateService implements service.Service. Service names are unique.
+func (s *ServiceService) CreateService(ctx context.Context, p *service.CreateServicePayload) (*service.ServiceStatusRT, error) {
+	if p.Services == nil {
+		return nil, &service.BadRequestT{Message: "missing service definition"}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p.Services.Name != nil {
+		for _, r := range s.order {
+			if r.deletedAt.IsZero() && r.def.Name != nil && *r.def.Name == *p.Services.Name {
+				return nil, &service.ResourceAlreadyCreatedT{ID: r.id, Message: "a service with that name already exists"}
+			}
+		}
+	}
+	return s.create(ctx, newUUIDURN(), p.Services), nil
+}
+
+// create stores a new service. s.mu must be held.
+func (s *ServiceService) create(ctx context.Context, id string, def *service.ServiceDefinitionT) *service.ServiceStatusRT {
+	r := &serviceRecord{
+		id:          id,
+		def:         *def,
+		status:      "active",
+		account:     Account(User(ctx)),
+		publishedAt: s.d.now(),
+	}
+	s.items[r.id] = r
+	s.order = append(s.order, r)
+	return s.status(r)
+}
+
+// Read implements service.Service.
+func (s *ServiceService) Read(ctx context.Context, p *service.ReadPayload) (*service.ServiceStatusRT, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.items[p.ID]
+	if !ok || !r.deletedAt.IsZero() {
+		return nil, &service.ResourceNotFoundT{ID: p.ID, Message: "service not found"}
+	}
+	return s.status(r), nil
+}
+
+// Update implements service.Service. A missing service is created if
+// ForceCreate is set.
+func (s *ServiceService) Update(ctx context.Context, p *service.UpdatePayload) (*service.ServiceStatusRT, error) {
+	if p.Services == nil {
+		return nil, &service.BadRequestT{Message: "missing service definition"}
+	}
+	id := stringOr(p.ID, "")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.items[id]
+	if !ok || !r.deletedAt.IsZero() {
+		if p.ForceCreate == nil || !*p.ForceCreate {
+			return nil, &service.ResourceNotFoundT{ID: id, Message: "service not found"}
+		}
+		if id == "" {
+			id = newUUIDURN()
+		}
+		return s.create(ctx, id, p.Services), nil
+	}
+	r.def = *p.Services
+	return s.status(r), nil
+}
+
+// Delete implements service.Service.
+func (s *ServiceService) Delete(ctx context.Context, p *service.DeletePayload) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.items[p.ID]
+	if !ok || !r.deletedAt.IsZero() {
+		return &service.ResourceNotFoundT{ID: p.ID, Message: "service not found"}
+	}
+	r.deletedAt = s.d.now()
+	return nil
+}
+
+// exists reports whether the service with the given ID exists.
+func (s *ServiceService) exists(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.items[id]
+	return ok && r.deletedAt.IsZero()
+}
+
+func (s *ServiceService) status(r *serviceRecord) *service.ServiceStatusRT {
+	return &service.ServiceStatusRT{
+		ID:          r.id,
+		Description: &r.def.Description,
+		Status:      r.status,
+		Account:     r.account,
+		Name:        r.def.Name,
+		Tags:        r.def.Tags,
+		Parameters:  r.def.Parameters,
+		Links: []*service.LinkT{
+			{Rel: "self", Type: "application/json", Href: s.d.link("/1/services/"+url.PathEscape(r.id), nil)},
+		},
+	}
+}
+
+func (r *serviceRecord) fields(name string) (string, bool) {
+	switch name {
+	case "id":
+		return r.id, true
+	case "name":
+		return stringOr(r.def.Name, ""), r.def.Name != nil
+	case "description":
+		return r.def.Description, true
+	case "status":
+		return r.status, true
+	case "account":
+		return r.account, true
+	case "policy":
+		return stringOr(r.def.Policy, ""), r.def.Policy != nil
+	case "published-at", "published_at", "publishedAt":
+		return formatTime(r.publishedAt), true
+	}
+	return "", false
+}
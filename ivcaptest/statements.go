@@ -0,0 +1,254 @@
+// Copyright 2025 Commonwealth Scientific and Industrial Research Organisation (CSIRO) ABN 41 687 119 230
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ivcaptest
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultPolicy is the policy of records created without one.
+const defaultPolicy = "urn:ivcap:policy:ivcap.open.metadata"
+
+// statement is a record of the aspect and metadata stores: an assertion
+// about an entity, valid from the time it was made until retracted.
+type statement struct {
+	id          string
+	entity      string
+	schema      string
+	content     any
+	contentType string
+	validFrom   time.Time
+	validTo     time.Time
+	asserter    string
+	retracter   string
+	replaces    string
+	account     string
+	policy      string
+}
+
+// statementStore is an append-only store of statements. Retracting a
+// statement closes its validity period rather than removing it, so the store
+// can be queried at any point in time.
+type statementStore struct {
+	mu    sync.Mutex
+	items map[string]*statement
+	order []*statement
+}
+
+func newStatementStore() *statementStore {
+	return &statementStore{items: map[string]*statement{}}
+}
+
+// get returns a copy of the statement with the given ID.
+func (s *statementStore) get(id string) (statement, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, ok := s.items[id]
+	if !ok {
+		return statement{}, false
+	}
+	return *st, true
+}
+
+// add stores st.
+func (s *statementStore) add(st statement) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.items[st.id] = &st
+	s.order = append(s.order, &st)
+}
+
+// retract ends the validity of the statement with the given ID at time at. It
+// returns false if there is no such statement or it is already retracted.
+func (s *statementStore) retract(id, user string, at time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, ok := s.items[id]
+	if !ok || !st.validTo.IsZero() {
+		return false
+	}
+	st.validTo = at
+	st.retracter = user
+	return true
+}
+
+// active returns the IDs of the statements about entity with schema which
+// have not been retracted.
+func (s *statementStore) active(entity, schema string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var ids []string
+	for _, st := range s.order {
+		if st.entity == entity && st.schema == schema && st.validTo.IsZero() {
+			ids = append(ids, st.id)
+		}
+	}
+	return ids
+}
+
+// query returns copies of the statements valid at time at, in the order they
+// were made. entity and schema restrict the result if set, the latter being a
+// pattern using '%' as wildcard.
+func (s *statementStore) query(at time.Time, entity, schema *string) []*statement {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var res []*statement
+	for _, st := range s.order {
+		if !validAt(at, st.validFrom, st.validTo) {
+			continue
+		}
+		if entity != nil && *entity != "" && st.entity != *entity {
+			continue
+		}
+		if schema != nil && *schema != "" && !likePattern(*schema, st.schema) {
+			continue
+		}
+		c := *st
+		res = append(res, &c)
+	}
+	return res
+}
+
+// all returns copies of all statements, including retracted ones, in the
+// order they were made.
+func (s *statementStore) all() []*statement {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	res := make([]*statement, len(s.order))
+	for i, st := range s.order {
+		c := *st
+		res[i] = &c
+	}
+	return res
+}
+
+func (st *statement) fields(name string) (string, bool) {
+	switch name {
+	case "id":
+		return st.id, true
+	case "entity", "entity-id", "entity_id":
+		return st.entity, true
+	case "schema":
+		return st.schema, true
+	case "content-type", "content_type":
+		return st.contentType, true
+	case "valid-from", "valid_from", "validFrom":
+		return formatTime(st.validFrom), true
+	case "valid-to", "valid_to", "validTo":
+		return formatTime(st.validTo), !st.validTo.IsZero()
+	case "asserter":
+		return st.asserter, true
+	case "retracter", "revoker":
+		return st.retracter, st.retracter != ""
+	case "replaces":
+		return st.replaces, st.replaces != ""
+	case "account":
+		return st.account, true
+	case "policy":
+		return st.policy, st.policy != ""
+	}
+	return "", false
+}
+
+// contentPath returns the value selected by a simple JSON path such as
+// "$.a.b" in the content of st. Only member access is supported, optionally
+// followed by a filter such as `? (@.c == "v")` which, as in PostgreSQL,
+// only selects values whose member c equals the JSON literal "v".
+func (st *statement) contentPath(path string) (any, bool) {
+	path, filter, _ := strings.Cut(path, "?")
+	v, ok := lookupPath(normalizeJSON(st.content), path)
+	if !ok {
+		return nil, false
+	}
+	if filter = strings.TrimSpace(filter); filter != "" && !matchPathFilter(v, filter) {
+		return nil, false
+	}
+	return v, true
+}
+
+// lookupPath returns the value selected by a member access path such as
+// "$.a.b" or "@.a.b" in v.
+func lookupPath(v any, path string) (any, bool) {
+	path = strings.TrimLeft(strings.TrimSpace(path), "$@")
+	for _, key := range strings.Split(path, ".") {
+		if key == "" {
+			continue
+		}
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if v, ok = m[strings.Trim(key, `"`)]; !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// matchPathFilter reports whether v satisfies a JSON path filter of the form
+// "(@.path == literal)".
+func matchPathFilter(v any, filter string) bool {
+	filter = strings.TrimSuffix(strings.TrimPrefix(filter, "("), ")")
+	lhs, rhs, ok := strings.Cut(filter, "==")
+	if !ok || !strings.HasPrefix(strings.TrimSpace(lhs), "@") {
+		return false
+	}
+	got, ok := lookupPath(v, lhs)
+	var want any
+	if !ok || json.Unmarshal([]byte(strings.TrimSpace(rhs)), &want) != nil {
+		return false
+	}
+	return reflect.DeepEqual(got, want)
+}
+
+// normalizeJSON returns v as decoded by encoding/json into an any, so that
+// content given as typed Go values can be navigated as maps.
+func normalizeJSON(v any) any {
+	switch v.(type) {
+	case map[string]any, []any, string, float64, bool, nil:
+		return v
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var res any
+	if json.Unmarshal(b, &res) != nil {
+		return v
+	}
+	return res
+}
+
+// checkJSONContentType returns an error unless contentType is empty or a JSON
+// media type.
+func checkJSONContentType(contentType string) error {
+	if contentType == "" {
+		return nil
+	}
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return fmt.Errorf("invalid content type %q: %w", contentType, err)
+	}
+	if mt != "application/json" && !strings.HasSuffix(mt, "+json") {
+		return fmt.Errorf("unsupported content type %q", contentType)
+	}
+	return nil
+}